@@ -0,0 +1,364 @@
+/*
+analysis fits candidate statistical models to the structure of delivery networks. It is intended for comparing how different timestamp distributions shape a network: for example, whether stop degrees look Poisson under a uniform spec and heavier-tailed under a Gaussian one.
+
+Models are fitted by maximum likelihood, and each fit is scored with the Kolmogorov-Smirnov tools in the testutils package.
+*/
+package analysis
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"gonum.org/v1/gonum/stat"
+	"gonum.org/v1/gonum/stat/distuv"
+
+	"github.com/bdshroyer/burrow/network"
+	"github.com/bdshroyer/burrow/testutils"
+)
+
+// Fit describes a model fitted to a sample.
+//
+// KS is the Kolmogorov-Smirnov distance between the sample and the fitted model, and PValue the corresponding p-value; small p-values indicate a poor fit. AIC can be used to rank several models fitted to the same sample, lower being better.
+type Fit struct {
+	Model         string
+	Params        map[string]float64
+	N             int
+	LogLikelihood float64
+	AIC           float64
+	KS            float64
+	PValue        float64
+}
+
+// StopDegrees returns the in- and out-degrees of every stop in G, counting stop-to-stop edges only. Hub edges are left out since every stop in a generated network has the same number of them.
+func StopDegrees(G *network.DeliveryNetwork) (in, out []float64) {
+	inDegree := make(map[int64]int, len(G.Stops))
+
+	ids := make([]int64, 0, len(G.Stops))
+	for id := range G.Stops {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	out = make([]float64, 0, len(ids))
+	for _, id := range ids {
+		degree := 0
+		for _, edge := range G.DEdges[id] {
+			if !edge.Dst.IsHub() {
+				degree++
+				inDegree[edge.Dst.ID()]++
+			}
+		}
+		out = append(out, float64(degree))
+	}
+
+	in = make([]float64, 0, len(ids))
+	for _, id := range ids {
+		in = append(in, float64(inDegree[id]))
+	}
+
+	return in, out
+}
+
+// StopWeights returns the weights of every stop-to-stop edge in G.
+func StopWeights(G *network.DeliveryNetwork) []float64 {
+	weights := make([]float64, 0)
+
+	for id := range G.Stops {
+		for _, edge := range G.DEdges[id] {
+			if !edge.Dst.IsHub() {
+				weights = append(weights, edge.Wgt)
+			}
+		}
+	}
+
+	sort.Float64s(weights)
+	return weights
+}
+
+// NetworkFits collects the model fits for a single network, each in the order returned by FitDegrees or FitWeights.
+type NetworkFits struct {
+	InDegree  []Fit
+	OutDegree []Fit
+	Weights   []Fit
+}
+
+// FitNetwork fits the candidate degree models to the stop in- and out-degrees of G, and the candidate weight models to its stop-to-stop edge weights.
+func FitNetwork(G *network.DeliveryNetwork) (*NetworkFits, error) {
+	in, out := StopDegrees(G)
+	fits := &NetworkFits{}
+
+	var err error
+	if fits.InDegree, err = FitDegrees(in); err != nil {
+		return nil, fmt.Errorf("in-degree: %w", err)
+	}
+
+	if fits.OutDegree, err = FitDegrees(out); err != nil {
+		return nil, fmt.Errorf("out-degree: %w", err)
+	}
+
+	if fits.Weights, err = FitWeights(StopWeights(G)); err != nil {
+		return nil, fmt.Errorf("weights: %w", err)
+	}
+
+	return fits, nil
+}
+
+// FitDegrees fits every candidate degree model to the sample and returns the fits in order of increasing KS distance. The power law is fitted with the xmin chosen by FitPowerLawTail; since it only describes the tail, its likelihood and AIC aren't comparable with the other models, which is why the fits are ranked by KS distance instead. Models that cannot be fitted to the sample are left out.
+func FitDegrees(samples []float64) ([]Fit, error) {
+	if err := checkCounts(samples); err != nil {
+		return nil, err
+	}
+
+	fits := make([]Fit, 0, 3)
+
+	for _, fitter := range []func([]float64) (Fit, error){FitPoisson, FitGeometric, FitPowerLawTail} {
+		if fit, err := fitter(samples); err == nil {
+			fits = append(fits, fit)
+		}
+	}
+
+	sort.SliceStable(fits, func(i, j int) bool { return fits[i].KS < fits[j].KS })
+	return fits, nil
+}
+
+// FitPoisson fits a Poisson distribution to a sample of non-negative integers.
+func FitPoisson(samples []float64) (Fit, error) {
+	if err := checkCounts(samples); err != nil {
+		return Fit{}, err
+	}
+
+	lambda := stat.Mean(samples, nil)
+	if lambda == 0 {
+		return Fit{}, fmt.Errorf("Cannot fit a Poisson distribution to an all-zero sample")
+	}
+
+	dist := distuv.Poisson{Lambda: lambda}
+
+	ll := 0.0
+	for _, x := range samples {
+		ll += dist.LogProb(x)
+	}
+
+	return score("poisson", map[string]float64{"lambda": lambda}, samples, ll, dist.CDF, testutils.DiscreteKSStatistic)
+}
+
+// FitGeometric fits a geometric distribution over {0, 1, 2, ...} to a sample of non-negative integers. The fitted parameter p is the per-trial success probability.
+func FitGeometric(samples []float64) (Fit, error) {
+	if err := checkCounts(samples); err != nil {
+		return Fit{}, err
+	}
+
+	mean := stat.Mean(samples, nil)
+	if mean == 0 {
+		return Fit{}, fmt.Errorf("Cannot fit a geometric distribution to an all-zero sample")
+	}
+
+	p := 1.0 / (1.0 + mean)
+
+	ll := 0.0
+	for _, x := range samples {
+		ll += math.Log(p) + x*math.Log(1-p)
+	}
+
+	cdf := func(k float64) float64 {
+		if k < 0 {
+			return 0.0
+		}
+		return 1.0 - math.Pow(1-p, math.Floor(k)+1)
+	}
+
+	return score("geometric", map[string]float64{"p": p}, samples, ll, cdf, testutils.DiscreteKSStatistic)
+}
+
+// FitPowerLaw fits a discrete power law p(x) ∝ x^-alpha to the values in the sample that are at least xmin, following Clauset, Shalizi & Newman (2009). The returned fit only describes the tail; N is the number of values in it.
+func FitPowerLaw(samples []float64, xmin float64) (Fit, error) {
+	if err := checkCounts(samples); err != nil {
+		return Fit{}, err
+	}
+
+	if xmin < 1 {
+		return Fit{}, fmt.Errorf("xmin must be at least 1")
+	}
+
+	tail := make([]float64, 0, len(samples))
+	logSum := 0.0
+	for _, x := range samples {
+		if x >= xmin {
+			tail = append(tail, x)
+			logSum += math.Log(x / (xmin - 0.5))
+		}
+	}
+
+	if len(tail) < 2 || logSum == 0 {
+		return Fit{}, fmt.Errorf("Too few values at or above xmin to fit a power law")
+	}
+
+	alpha := 1.0 + float64(len(tail))/logSum
+	norm := hurwitzZeta(alpha, xmin)
+
+	ll := -float64(len(tail)) * math.Log(norm)
+	for _, x := range tail {
+		ll -= alpha * math.Log(x)
+	}
+
+	cdf := func(k float64) float64 {
+		if k < xmin {
+			return 0.0
+		}
+		return 1.0 - hurwitzZeta(alpha, math.Floor(k)+1)/norm
+	}
+
+	return score("power-law", map[string]float64{"alpha": alpha, "xmin": xmin}, tail, ll, cdf, testutils.DiscreteKSStatistic)
+}
+
+// FitPowerLawTail fits a power law at every distinct positive value of the sample and returns the fit whose tail is closest to the data in the KS sense, as suggested by Clauset et al.
+func FitPowerLawTail(samples []float64) (Fit, error) {
+	if err := checkCounts(samples); err != nil {
+		return Fit{}, err
+	}
+
+	candidates := distinct(samples)
+
+	var best Fit
+	found := false
+
+	for _, xmin := range candidates {
+		if xmin < 1 {
+			continue
+		}
+
+		fit, err := FitPowerLaw(samples, xmin)
+		if err != nil {
+			continue
+		}
+
+		if !found || fit.KS < best.KS {
+			best, found = fit, true
+		}
+	}
+
+	if !found {
+		return Fit{}, fmt.Errorf("No xmin yields a usable power-law tail")
+	}
+
+	return best, nil
+}
+
+// FitWeights fits normal and exponential distributions to a sample of edge weights and returns the fits in order of increasing AIC.
+func FitWeights(samples []float64) ([]Fit, error) {
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("Cannot fit an empty sample")
+	}
+
+	fits := make([]Fit, 0, 2)
+
+	mu, sigma := stat.PopMeanStdDev(samples, nil)
+	if sigma > 0 {
+		normal := distuv.Normal{Mu: mu, Sigma: sigma}
+
+		ll := 0.0
+		for _, x := range samples {
+			ll += normal.LogProb(x)
+		}
+
+		fit, err := score("normal", map[string]float64{"mu": mu, "sigma": sigma}, samples, ll, normal.CDF, testutils.KSStatistic)
+		if err != nil {
+			return nil, err
+		}
+		fits = append(fits, fit)
+	}
+
+	if mu > 0 {
+		exponential := distuv.Exponential{Rate: 1.0 / mu}
+
+		ll := 0.0
+		for _, x := range samples {
+			ll += exponential.LogProb(x)
+		}
+
+		fit, err := score("exponential", map[string]float64{"rate": exponential.Rate}, samples, ll, exponential.CDF, testutils.KSStatistic)
+		if err != nil {
+			return nil, err
+		}
+		fits = append(fits, fit)
+	}
+
+	sort.SliceStable(fits, func(i, j int) bool { return fits[i].AIC < fits[j].AIC })
+	return fits, nil
+}
+
+// score completes a fit by computing its AIC and its goodness of fit under the given KS statistic.
+func score(
+	model string,
+	params map[string]float64,
+	samples []float64,
+	ll float64,
+	cdf func(float64) float64,
+	ksStatistic func([]float64, func(float64) float64) (float64, error),
+) (Fit, error) {
+	fit := Fit{
+		Model:         model,
+		Params:        params,
+		N:             len(samples),
+		LogLikelihood: ll,
+		AIC:           2*float64(len(params)) - 2*ll,
+	}
+
+	var err error
+	if fit.KS, err = ksStatistic(samples, cdf); err != nil {
+		return Fit{}, err
+	}
+
+	if fit.PValue, err = testutils.KSPValue(fit.KS, len(samples)); err != nil {
+		return Fit{}, err
+	}
+
+	return fit, nil
+}
+
+// checkCounts verifies that a sample is non-empty and made up of non-negative integers.
+func checkCounts(samples []float64) error {
+	if len(samples) == 0 {
+		return fmt.Errorf("Cannot fit an empty sample")
+	}
+
+	for _, x := range samples {
+		if x < 0 || x != math.Trunc(x) {
+			return fmt.Errorf("Count samples must be non-negative integers, got %v", x)
+		}
+	}
+
+	return nil
+}
+
+// distinct returns the distinct values of a sample in ascending order.
+func distinct(samples []float64) []float64 {
+	sorted := make([]float64, len(samples))
+	copy(sorted, samples)
+	sort.Float64s(sorted)
+
+	values := make([]float64, 0, len(sorted))
+	for i, x := range sorted {
+		if i == 0 || x != sorted[i-1] {
+			values = append(values, x)
+		}
+	}
+
+	return values
+}
+
+// hurwitzZeta approximates the Hurwitz zeta function, the sum of (q+k)^-s over k >= 0, for s > 1 and q > 0. The first terms are summed directly and the remainder is estimated with the Euler-Maclaurin formula.
+func hurwitzZeta(s, q float64) float64 {
+	const direct = 20
+
+	sum := 0.0
+	for k := 0; k < direct; k++ {
+		sum += math.Pow(q+float64(k), -s)
+	}
+
+	a := q + direct
+	sum += math.Pow(a, 1-s)/(s-1) + 0.5*math.Pow(a, -s) + s*math.Pow(a, -s-1)/12.0
+
+	return sum
+}
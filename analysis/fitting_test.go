@@ -0,0 +1,182 @@
+package analysis_test
+
+import (
+	"math"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+
+	"github.com/bdshroyer/burrow"
+	"github.com/bdshroyer/burrow/analysis"
+	"github.com/bdshroyer/burrow/network"
+)
+
+func sample(n int, draw func() float64) []float64 {
+	samples := make([]float64, n)
+	for i := range samples {
+		samples[i] = draw()
+	}
+	return samples
+}
+
+var _ = Describe("Fitting", func() {
+	var src rand.Source
+
+	BeforeEach(func() {
+		src = rand.NewSource(3)
+	})
+
+	Describe("StopDegrees and StopWeights", func() {
+		It("Count stop-to-stop edges only", func() {
+			t0 := time.Date(2022, 3, 29, 8, 0, 0, 0, time.UTC)
+
+			G := network.NewDeliveryNetwork()
+			G.Hubs[1] = &network.HubNode{Val: 1}
+			G.Stops[2] = &network.StopNode{Val: 2, Timestamp: t0}
+			G.Stops[3] = &network.StopNode{Val: 3, Timestamp: t0.Add(time.Hour)}
+			G.Stops[4] = &network.StopNode{Val: 4, Timestamp: t0.Add(2 * time.Hour)}
+
+			G.DEdges[1] = []*network.DeliveryEdge{{Src: G.Hubs[1], Dst: G.Stops[2], Wgt: 1.0}}
+			G.DEdges[2] = []*network.DeliveryEdge{
+				{Src: G.Stops[2], Dst: G.Hubs[1], Wgt: 1.0},
+				{Src: G.Stops[2], Dst: G.Stops[3], Wgt: 3.0},
+				{Src: G.Stops[2], Dst: G.Stops[4], Wgt: 5.0},
+			}
+			G.DEdges[3] = []*network.DeliveryEdge{{Src: G.Stops[3], Dst: G.Stops[4], Wgt: 4.0}}
+
+			in, out := analysis.StopDegrees(G)
+			Expect(in).To(Equal([]float64{0, 1, 2}))
+			Expect(out).To(Equal([]float64{2, 1, 0}))
+
+			Expect(analysis.StopWeights(G)).To(Equal([]float64{3.0, 4.0, 5.0}))
+		})
+	})
+
+	Describe("FitPoisson", func() {
+		It("Recovers the rate of a Poisson sample", func() {
+			dist := distuv.Poisson{Lambda: 6.0, Src: src}
+			fit, err := analysis.FitPoisson(sample(2000, dist.Rand))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fit.Model).To(Equal("poisson"))
+			Expect(fit.Params["lambda"]).To(BeNumerically("~", 6.0, 0.2))
+			Expect(fit.N).To(Equal(2000))
+			Expect(fit.PValue).To(BeNumerically(">", 0.01))
+		})
+
+		It("Rejects samples that aren't counts", func() {
+			_, err := analysis.FitPoisson([]float64{1, 2.5})
+			Expect(err).To(HaveOccurred())
+
+			_, err = analysis.FitPoisson([]float64{})
+			Expect(err).To(MatchError("Cannot fit an empty sample"))
+
+			_, err = analysis.FitPoisson([]float64{0, 0})
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("FitGeometric", func() {
+		It("Recovers the success probability of a geometric sample", func() {
+			r := rand.New(src)
+			draw := func() float64 {
+				k := 0.0
+				for r.Float64() >= 0.25 {
+					k++
+				}
+				return k
+			}
+
+			fit, err := analysis.FitGeometric(sample(2000, draw))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fit.Params["p"]).To(BeNumerically("~", 0.25, 0.02))
+			Expect(fit.PValue).To(BeNumerically(">", 0.01))
+		})
+	})
+
+	Describe("FitPowerLaw", func() {
+		It("Recovers the exponent of a power-law tail", func() {
+			// Discretised Pareto sample with alpha = 2.5 above xmin = 5.
+			pareto := distuv.Pareto{Xm: 4.5, Alpha: 1.5, Src: src}
+			draw := func() float64 { return math.Floor(pareto.Rand() + 0.5) }
+
+			fit, err := analysis.FitPowerLaw(sample(3000, draw), 5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fit.Params["alpha"]).To(BeNumerically("~", 2.5, 0.15))
+			Expect(fit.Params["xmin"]).To(BeEquivalentTo(5))
+		})
+
+		It("Rejects an xmin below 1 or a tail that is too short", func() {
+			_, err := analysis.FitPowerLaw([]float64{1, 2, 3}, 0)
+			Expect(err).To(MatchError("xmin must be at least 1"))
+
+			_, err = analysis.FitPowerLaw([]float64{1, 2, 3}, 3)
+			Expect(err).To(HaveOccurred())
+		})
+	})
+
+	Describe("FitDegrees", func() {
+		It("Ranks the generating model first", func() {
+			dist := distuv.Poisson{Lambda: 10.0, Src: src}
+			fits, err := analysis.FitDegrees(sample(2000, dist.Rand))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fits).To(HaveLen(3))
+			Expect(fits[0].Model).To(Equal("poisson"))
+		})
+	})
+
+	Describe("FitWeights", func() {
+		It("Prefers the normal model for normally distributed weights", func() {
+			dist := distuv.Normal{Mu: 100.0, Sigma: 10.0, Src: src}
+			fits, err := analysis.FitWeights(sample(2000, dist.Rand))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fits).To(HaveLen(2))
+			Expect(fits[0].Model).To(Equal("normal"))
+			Expect(fits[0].PValue).To(BeNumerically(">", 0.01))
+			Expect(fits[1].PValue).To(BeNumerically("<", 0.01))
+		})
+
+		It("Prefers the exponential model for exponentially distributed weights", func() {
+			dist := distuv.Exponential{Rate: 0.5, Src: src}
+			fits, err := analysis.FitWeights(sample(2000, dist.Rand))
+
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fits[0].Model).To(Equal("exponential"))
+		})
+	})
+
+	Describe("FitNetwork", func() {
+		It("Fits degree and weight models to a generated network", func() {
+			distro, err := burrow.UniformTimestampDistribution(time.Date(2022, 3, 29, 0, 0, 0, 0, time.UTC), 24*time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+
+			G, err := burrow.MakeDeliveryNetwork(burrow.DeliveryNetworkConfig{
+				HubNodes:   2,
+				StopNodes:  200,
+				Distro:     distro,
+				EdgeBounds: &burrow.TimeBox{0, 2 * time.Hour},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			fits, err := analysis.FitNetwork(G)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fits.InDegree).NotTo(BeEmpty())
+			Expect(fits.OutDegree).NotTo(BeEmpty())
+			Expect(fits.Weights).To(HaveLen(2))
+
+			for _, fit := range fits.OutDegree {
+				Expect(fit.KS).To(And(BeNumerically(">=", 0), BeNumerically("<=", 1)))
+			}
+		})
+
+		It("Reports which sample could not be fitted", func() {
+			_, err := analysis.FitNetwork(network.NewDeliveryNetwork())
+			Expect(err).To(MatchError("in-degree: Cannot fit an empty sample"))
+		})
+	})
+})
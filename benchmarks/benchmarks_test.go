@@ -7,6 +7,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"github.com/bdshroyer/burrow"
+	"github.com/bdshroyer/burrow/network"
 	"github.com/onsi/gomega/gmeasure"
 )
 
@@ -47,23 +48,15 @@ var _ = Describe("Benchmark", func() {
 			edges := G.Edges()
 			stopwatch.Record("Edge Gathering Time", gmeasure.Precision(time.Microsecond))
 
-			nEdges := float64(edges.Len())
-			nStops := float64(len(G.Stops))
-			nHubs := float64(len(G.Hubs))
-
 			stopwatch.Reset()
 			for edges.Next() {
 			}
 			stopwatch.Record("Edge Iterating Time", gmeasure.Precision(time.Microsecond))
 
-			meanOutDegree := float64(0.0)
-			for _, edges := range G.DEdges {
-				meanOutDegree += float64(len(edges)) / nStops
-			}
-
-			experiment.RecordValue("nEdges", nEdges, gmeasure.Units("edges"))
-			experiment.RecordValue("delivery network edge density", nEdges/(2*nHubs*nStops+(nStops*(nStops-1))/2))
-			experiment.RecordValue("mean out-degree", meanOutDegree)
+			stats := network.Stats(G)
+			experiment.RecordValue("nEdges", float64(stats.Edges), gmeasure.Units("edges"))
+			experiment.RecordValue("delivery network edge density", stats.Density)
+			experiment.RecordValue("mean out-degree", stats.MeanStopOutDegree)
 		}
 	}
 
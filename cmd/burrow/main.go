@@ -0,0 +1,187 @@
+// Command burrow generates delivery networks from a NetworkSpec and reports on them.
+//
+// Usage:
+//
+//	burrow ensemble -spec spec.pb -n 100 -seed 1 -metrics out-degree,pagerank -o results.csv
+//	burrow stats -spec spec.yaml -seed 1 -format json
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math/rand"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/bdshroyer/burrow"
+	"github.com/bdshroyer/burrow/network"
+)
+
+type command struct {
+	summary string
+	run     func(args []string) error
+}
+
+var commands = map[string]command{
+	"ensemble": {"generate many instances of a spec and summarise node metrics as CSV", runEnsemble},
+	"stats":    {"generate one instance of a spec and report its summary statistics", runStats},
+}
+
+func main() {
+	if len(os.Args) < 2 {
+		usage()
+		os.Exit(2)
+	}
+
+	cmd, ok := commands[os.Args[1]]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "burrow: unknown command %q\n", os.Args[1])
+		usage()
+		os.Exit(2)
+	}
+
+	if err := cmd.run(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "burrow %s: %v\n", os.Args[1], err)
+		os.Exit(1)
+	}
+}
+
+func usage() {
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Fprintln(os.Stderr, "Usage: burrow <command> [flags]\n\nCommands:")
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", name, commands[name].summary)
+	}
+}
+
+// openOutput returns a writer for path, or stdout if path is "-". Closing the writer leaves stdout open.
+func openOutput(path string) (io.WriteCloser, error) {
+	if path == "-" {
+		return nopCloser{os.Stdout}, nil
+	}
+
+	return os.Create(path)
+}
+
+type nopCloser struct {
+	io.Writer
+}
+
+func (nopCloser) Close() error {
+	return nil
+}
+
+// closeOutput closes out, reporting a failure to close only if nothing else went wrong first. Failed writes to a file may not surface until it's closed.
+func closeOutput(out io.Closer, err *error) {
+	if cerr := out.Close(); cerr != nil && *err == nil {
+		*err = cerr
+	}
+}
+
+func runEnsemble(args []string) (err error) {
+	flags := flag.NewFlagSet("ensemble", flag.ContinueOnError)
+	specPath := flags.String("spec", "", "path to the network spec, as binary protobuf, JSON, text format or YAML")
+	instances := flags.Uint("n", 100, "number of instances to generate")
+	seed := flags.Int64("seed", 1, "seed of the first instance; instance i uses seed+i")
+	workers := flags.Uint("workers", 1, "number of instances to generate concurrently")
+	metrics := flags.String("metrics", "out-degree", "comma-separated list of node metrics")
+	outPath := flags.String("o", "-", "output CSV path, or - for stdout")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *specPath == "" {
+		return fmt.Errorf("-spec is required")
+	}
+
+	spec, err := burrow.LoadSpec(*specPath)
+	if err != nil {
+		return err
+	}
+
+	cfg := burrow.EnsembleConfig{
+		Spec:      spec,
+		Instances: *instances,
+		Seed:      *seed,
+		Workers:   *workers,
+		Metrics:   make(map[string]burrow.NodeMetric),
+	}
+
+	for _, name := range strings.Split(*metrics, ",") {
+		name = strings.TrimSpace(name)
+		metric, ok := burrow.StandardMetrics[name]
+		if !ok {
+			return fmt.Errorf("unknown metric %q", name)
+		}
+		cfg.Metrics[name] = metric
+	}
+
+	summaries, err := burrow.Ensemble(cfg)
+	if err != nil {
+		return err
+	}
+
+	out, err := openOutput(*outPath)
+	if err != nil {
+		return err
+	}
+	defer closeOutput(out, &err)
+
+	return burrow.WriteEnsembleCSV(out, summaries)
+}
+
+func runStats(args []string) (err error) {
+	flags := flag.NewFlagSet("stats", flag.ContinueOnError)
+	specPath := flags.String("spec", "", "path to the network spec, as binary protobuf, JSON, text format or YAML")
+	seed := flags.Int64("seed", 1, "seed for the generated instance")
+	format := flags.String("format", "text", "output format: text or json")
+	outPath := flags.String("o", "-", "output path, or - for stdout")
+
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	if *specPath == "" {
+		return fmt.Errorf("-spec is required")
+	}
+
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("unknown format %q", *format)
+	}
+
+	spec, err := burrow.LoadSpec(*specPath)
+	if err != nil {
+		return err
+	}
+
+	G, err := burrow.MakeNetworkFromSpec(spec, rand.New(rand.NewSource(*seed)))
+	if err != nil {
+		return err
+	}
+
+	out, err := openOutput(*outPath)
+	if err != nil {
+		return err
+	}
+	defer closeOutput(out, &err)
+
+	stats := network.Stats(G)
+
+	if *format == "json" {
+		enc := json.NewEncoder(out)
+		enc.SetIndent("", "  ")
+		return enc.Encode(stats)
+	}
+
+	_, err = fmt.Fprint(out, stats)
+	return err
+}
@@ -3,6 +3,7 @@ package burrow
 import (
 	"fmt"
 	"sort"
+	"sync"
 	"time"
 
 	"github.com/bdshroyer/burrow/network"
@@ -63,86 +64,528 @@ func (t TimeBox) inBounds(weight float64) bool {
 	return weight >= float64(t[0]) && weight <= float64(t[1])
 }
 
-// Creates a delivery network with the specified number of hubs and stops  using the provided distribution.
-// Returns an error if distro is not a valid sample distribution.
-func MakeDeliveryNetwork(cfg DeliveryNetworkConfig) (*network.DeliveryNetwork, error) {
-	nHubNodes, nStopNodes, distro, edgeBounds := cfg.HubNodes, cfg.StopNodes, cfg.Distro, cfg.EdgeBounds
+// Weight assigned to every hub-to-stop and stop-to-hub edge.
+const hubEdgeWeight = float64(1 * time.Hour)
+
+// Travel speed in kilometres per hour used for edge metadata when a config doesn't set one.
+const defaultSpeed = 40.0
+
+// speed returns the config's travel speed in kilometres per hour.
+func (cfg DeliveryNetworkConfig) speed() float64 {
+	if cfg.Speed > 0 {
+		return cfg.Speed
+	}
+
+	return defaultSpeed
+}
+
+// validate checks the parts of a config that every generator depends on.
+func (cfg DeliveryNetworkConfig) validate() error {
+	distro, edgeBounds := cfg.Distro, cfg.EdgeBounds
 
 	if distro == nil {
-		return nil, fmt.Errorf("Must receive a non-null sample distribution.")
+		return fmt.Errorf("Must receive a non-null sample distribution.")
+	}
+
+	if err := validateEdgeBounds(edgeBounds); err != nil {
+		return err
+	}
+
+	if cfg.Pairs > 0 && cfg.PairDelay == nil {
+		return fmt.Errorf("Pickup-and-delivery pairs require a delay distribution.")
 	}
 
+	if cfg.LineHaul < 0 {
+		return fmt.Errorf("Line-haul duration cannot be negative.")
+	}
+
+	return validateHubConfigs(cfg.HubConfigs, cfg.HubNodes)
+}
+
+// validateHubConfigs checks that there are no more hub configs than hubs, and that every hub's operating hours fall within a day.
+func validateHubConfigs(hubConfigs []HubConfig, hubNodes uint) error {
+	if uint(len(hubConfigs)) > hubNodes {
+		return fmt.Errorf("Received %d hub configs for %d hubs.", len(hubConfigs), hubNodes)
+	}
+
+	for i, hubCfg := range hubConfigs {
+		if hours := hubCfg.Hours; hours != nil && (hours.Open < 0 || hours.Open >= 24*time.Hour || hours.Close < 0 || hours.Close >= 24*time.Hour) {
+			return fmt.Errorf("Hub %d: Operating hours must fall within a single day.", i)
+		}
+	}
+
+	return nil
+}
+
+// validateEdgeBounds checks that edge bounds, if given, form a non-negative interval.
+func validateEdgeBounds(edgeBounds *TimeBox) error {
 	if edgeBounds != nil && edgeBounds[0] > edgeBounds[1] {
-		return nil, fmt.Errorf("Lower edge bound must not exceed upper edge bound.")
+		return fmt.Errorf("Lower edge bound must not exceed upper edge bound.")
 	} else if edgeBounds != nil && (edgeBounds[0] < 0 || edgeBounds[1] < 0) {
-		return nil, fmt.Errorf("Edge bounds cannot be negative.")
+		return fmt.Errorf("Edge bounds cannot be negative.")
+	}
+
+	return nil
+}
+
+// makeHub produces the i-th hub of a network, applying its hub config and location if the config has them.
+func (cfg DeliveryNetworkConfig) makeHub(nFactory *NodeFactory, i int) *network.HubNode {
+	hub := nFactory.MakeHub()
+
+	if i < len(cfg.HubConfigs) {
+		hubCfg := cfg.HubConfigs[i]
+		hub.Hours, hub.Fleet, hub.Capacity = hubCfg.Hours, hubCfg.Fleet, hubCfg.Capacity
+	}
+
+	if cfg.Locations != nil {
+		loc := cfg.Locations()
+		hub.Loc = &loc
+	}
+
+	return hub
+}
+
+// makeStop produces a stop with a sampled timestamp, and a sampled location and demand if the config has distributions for them.
+func (cfg DeliveryNetworkConfig) makeStop(nFactory *NodeFactory) *network.StopNode {
+	stop := nFactory.MakeStop(cfg.Distro())
+
+	if cfg.Locations != nil {
+		loc := cfg.Locations()
+		stop.Loc = &loc
+	}
+
+	if cfg.Demand != nil {
+		stop.Demand = cfg.Demand()
+	}
+
+	return stop
+}
+
+// Creates a delivery network with the specified number of hubs and stops  using the provided distribution.
+// Each stop is linked in both directions to the hubs chosen by cfg.Assignment, or to every hub if no assignment is set.
+// If cfg.LineHaul is set, every pair of hubs is linked both ways by transfer edges, which come before any hub-to-stop edge.
+// If cfg.Pairs is set, that many pickup-and-delivery pairs are generated after the ordinary stops and indexed in the network's Pairs map.
+// If cfg.Sink is set, every node and edge is also written to it as it is made; stop-to-stop edges come last, once every stop has been sampled. The whole network is still kept and returned; WriteDeliveryNetwork writes to the sink without keeping the edges.
+// Returns an error if distro is not a valid sample distribution, or if the assignment fails for some stop.
+func MakeDeliveryNetwork(cfg DeliveryNetworkConfig) (*network.DeliveryNetwork, error) {
+	return generateDeliveryNetwork(cfg, true)
+}
+
+// WriteDeliveryNetwork generates the same network MakeDeliveryNetwork would, but only writes it to cfg.Sink instead of returning it.
+// No edges are kept: stop-to-stop edges are built a batch of source stops at a time and dropped once written. The nodes are still held, since every stop has to be sampled and sorted before any of them can be linked.
+// Returns an error if cfg.Sink is not set, or for any reason MakeDeliveryNetwork would.
+func WriteDeliveryNetwork(cfg DeliveryNetworkConfig) error {
+	if cfg.Sink == nil {
+		return fmt.Errorf("Writing a network requires a sink.")
+	}
+
+	_, err := generateDeliveryNetwork(cfg, false)
+	return err
+}
+
+// generateDeliveryNetwork does the work of MakeDeliveryNetwork. If keepEdges isn't set, the returned network holds only the nodes and edges are only passed to the sink.
+func generateDeliveryNetwork(cfg DeliveryNetworkConfig, keepEdges bool) (*network.DeliveryNetwork, error) {
+	nHubNodes, nStopNodes := cfg.HubNodes, cfg.StopNodes
+
+	if err := cfg.validate(); err != nil {
+		return nil, err
 	}
 
 	G := &network.DeliveryNetwork{
-		Hubs:   make(map[int64]*network.HubNode, nHubNodes),
-		Stops:  make(map[int64]*network.StopNode, nStopNodes),
-		DEdges: make(map[int64][]*network.DeliveryEdge, nHubNodes+nStopNodes),
+		Hubs:  make(map[int64]*network.HubNode, nHubNodes),
+		Stops: make(map[int64]*network.StopNode, nStopNodes+2*cfg.Pairs),
+	}
+
+	if keepEdges {
+		G.DEdges = make(map[int64][]*network.DeliveryEdge, nHubNodes+nStopNodes)
 	}
 
 	nFactory := NewNodeFactory()
 
+	// Hubs are kept in a list as well as the map so that edge order does not depend on map iteration order.
+	hubList := make([]*network.HubNode, 0, nHubNodes)
+
 	for i := 0; uint(i) < nHubNodes; i++ {
-		newHub := nFactory.MakeHub()
+		newHub := cfg.makeHub(nFactory, i)
+		if cfg.Sink != nil {
+			if err := cfg.Sink.WriteHub(newHub); err != nil {
+				return nil, err
+			}
+		}
+
 		G.Hubs[newHub.ID()] = newHub
+		hubList = append(hubList, newHub)
 
 		// Allocation hint based on the assumption that most stops are reachable by all hubs
-		G.DEdges[newHub.ID()] = make([]*network.DeliveryEdge, 0, nStopNodes)
+		if keepEdges {
+			G.DEdges[newHub.ID()] = make([]*network.DeliveryEdge, 0, nStopNodes)
+		}
 	}
 
-	nodeList := make([]*network.StopNode, 0, nStopNodes)
+	for _, edge := range linkTransfers(G, hubList, cfg.LineHaul, cfg.speed()) {
+		if err := cfg.sinkEdges(edge); err != nil {
+			return nil, err
+		}
+	}
+
+	nodeList := make([]*network.StopNode, 0, nStopNodes+2*cfg.Pairs)
 
 	// Generate new stop nodes and store them on a sorted min-heap.
 	for i := 0; uint(i) < nStopNodes; i++ {
-		newStop := nFactory.MakeStop(distro())
+		newStop := cfg.makeStop(nFactory)
+		if cfg.Sink != nil {
+			if err := cfg.Sink.WriteStop(newStop); err != nil {
+				return nil, err
+			}
+		}
+
 		nodeList = append(nodeList, newStop)
 
 		// Allocation hint based on the assumption that most nodes will have an edge leading back to each hub
-		G.DEdges[newStop.ID()] = make([]*network.DeliveryEdge, 0, nHubNodes+(nStopNodes-uint(i)+1))
+		if keepEdges {
+			G.DEdges[newStop.ID()] = make([]*network.DeliveryEdge, 0, nHubNodes+(nStopNodes-uint(i)+1))
+		}
 
-		// Add edge nodes linking each hub node to each stop node in both directions.
-		for _, hub := range G.Hubs {
-			edge := &network.DeliveryEdge{
-				Src: hub,
-				Dst: newStop,
-				Wgt: float64(1 * time.Hour),
+		if err := cfg.linkHubs(G, hubList, newStop, i, true, true); err != nil {
+			return nil, err
+		}
+	}
+
+	// Pairs follow the ordinary stops, pickup first. A vehicle can't start its route at a dropoff or end it at a pickup, so pickups get no edges back to hubs and dropoffs no edges from them.
+	G.Pairs = make(map[int64]*network.PairedStopNode, 2*cfg.Pairs)
+	for k := 0; uint(k) < cfg.Pairs; k++ {
+		pickup, err := cfg.makePair(nFactory)
+		if err != nil {
+			return nil, err
+		}
+
+		if cfg.Sink != nil {
+			if err := cfg.Sink.WritePair(pickup); err != nil {
+				return nil, err
+			}
+		}
+
+		G.AddPair(pickup)
+
+		for j, stop := range []*network.PairedStopNode{pickup, pickup.Partner} {
+			nodeList = append(nodeList, &stop.StopNode)
+			if keepEdges {
+				G.DEdges[stop.ID()] = make([]*network.DeliveryEdge, 0, nHubNodes)
 			}
 
-			G.DEdges[hub.ID()] = append(G.DEdges[hub.ID()], edge)
-			G.DEdges[newStop.ID()] = append(G.DEdges[newStop.ID()], edge.ReversedEdge().(*network.DeliveryEdge))
+			if err := cfg.linkHubs(G, hubList, &stop.StopNode, int(nStopNodes)+2*k+j, stop.IsPickup(), stop.IsDropoff()); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	SortInPlace(nodeList)
 
-	// Extract nodes in order from the heap, connect its predecessors to it, and store it in the graph.
-	// Since each node stored in the graph prior to the given node is an earlier stop (due to the min-heap property), a new edge should be drawn from each node in the graph to the new node.
-	// The exception to this rule is if two nodes share the exact same timestamp.
-	for i := 0; i < len(nodeList); i++ {
-		nextStop := nodeList[i]
+	// Since the list is sorted, every stop after a given stop is a candidate destination for it. Stop-to-stop edges are built per source stop, so the work can be split across workers without changing the result.
+	// Without a network to keep them in, they are built a batch of sources at a time so that only one batch is ever held.
+	batch := len(nodeList)
+	if !keepEdges {
+		batch = linkChunkSize
+		if cfg.Workers > 1 {
+			batch *= int(cfg.Workers)
+		}
+	}
+
+	for from := 0; from < len(nodeList); from += batch {
+		to := from + batch
+		if to > len(nodeList) {
+			to = len(nodeList)
+		}
 
-		for _, prevStop := range G.Stops {
-			weight := float64(nextStop.Timestamp.Sub(prevStop.Timestamp))
+		stopEdges := linkStopRange(nodeList, from, to, cfg.EdgeBounds, cfg.speed(), cfg.Workers)
 
-			if weight > 0.0 {
-				if cfg.EdgeBounds == nil || cfg.EdgeBounds.inBounds(weight) {
-					edge := &network.DeliveryEdge{
-						Src: prevStop,
-						Dst: nextStop,
-						Wgt: weight,
-					}
+		for i, stop := range nodeList[from:to] {
+			if paired, ok := G.Pairs[stop.ID()]; ok && paired.IsPickup() {
+				stopEdges[i] = linkPartner(stopEdges[i], paired, cfg.speed())
+			}
 
-					G.DEdges[prevStop.ID()] = append(G.DEdges[prevStop.ID()], edge)
-				}
+			if err := cfg.sinkEdges(stopEdges[i]...); err != nil {
+				return nil, err
+			}
+
+			addEdges(G, stopEdges[i]...)
+			G.Stops[stop.ID()] = stop
+		}
+	}
+
+	return G, nil
+}
+
+// addEdges appends edges to their sources' lists in G, unless G keeps no edges because the network is only being written to a sink.
+func addEdges(G *network.DeliveryNetwork, edges ...*network.DeliveryEdge) {
+	if G.DEdges == nil {
+		return
+	}
+
+	for _, edge := range edges {
+		src := edge.Src.ID()
+		G.DEdges[src] = append(G.DEdges[src], edge)
+	}
+}
+
+// linkHubs links stop to the hubs assigned to it: from each hub if dispatch is set, and back to each hub if ret is set. idx is the stop's position in generation order.
+// Vehicles can always return to a hub, but a hub only dispatches to stops within its operating hours.
+func (cfg DeliveryNetworkConfig) linkHubs(G *network.DeliveryNetwork, hubList []*network.HubNode, stop *network.StopNode, idx int, dispatch, ret bool) error {
+	assigned := hubList
+	if cfg.Assignment != nil {
+		var err error
+		if assigned, err = cfg.Assignment(hubList, stop, idx); err != nil {
+			return fmt.Errorf("Stop %d: %w", idx, err)
+		}
+	}
+
+	for _, hub := range assigned {
+		edge := &network.DeliveryEdge{
+			Src:  hub,
+			Dst:  stop,
+			Wgt:  hubEdgeWeight,
+			Knd:  network.DispatchEdge,
+			Meta: network.TravelMeta(hub, stop, cfg.speed()),
+		}
+
+		if dispatch && hub.IsOpen(stop.Timestamp) {
+			addEdges(G, edge)
+			if err := cfg.sinkEdges(edge); err != nil {
+				return err
+			}
+		}
+
+		if ret {
+			back := edge.ReversedEdge().(*network.DeliveryEdge)
+			addEdges(G, back)
+			if err := cfg.sinkEdges(back); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// linkTransfers links every hub in hubList to every other one with a transfer edge of weight lineHaul, and returns the new edges. Each hub's transfers are ordered by destination position in hubList. Nothing is linked if lineHaul isn't positive.
+func linkTransfers(G *network.DeliveryNetwork, hubList []*network.HubNode, lineHaul time.Duration, speed float64) []*network.DeliveryEdge {
+	if lineHaul <= 0 {
+		return nil
+	}
+
+	edges := make([]*network.DeliveryEdge, 0, len(hubList)*(len(hubList)-1))
+	for _, src := range hubList {
+		for _, dst := range hubList {
+			if src == dst {
+				continue
+			}
+
+			edge := &network.DeliveryEdge{
+				Src:  src,
+				Dst:  dst,
+				Wgt:  float64(lineHaul),
+				Knd:  network.TransferEdge,
+				Meta: network.TravelMeta(src, dst, speed),
 			}
+
+			addEdges(G, edge)
+			edges = append(edges, edge)
 		}
+	}
+
+	return edges
+}
 
-		G.Stops[nextStop.ID()] = nextStop
+// sinkEdges passes edges to cfg.Sink, if there is one.
+func (cfg DeliveryNetworkConfig) sinkEdges(edges ...*network.DeliveryEdge) error {
+	if cfg.Sink == nil {
+		return nil
 	}
 
+	for _, edge := range edges {
+		if err := cfg.Sink.WriteEdge(edge); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// makePair produces a pickup-and-delivery pair. The pickup is sampled like any other stop; the dropoff follows after a delay drawn from cfg.PairDelay, gets its own location and unloads the pickup's demand, so its own demand is the negative of the pickup's.
+func (cfg DeliveryNetworkConfig) makePair(nFactory *NodeFactory) (*network.PairedStopNode, error) {
+	pickup := cfg.makeStop(nFactory)
+
+	delay := cfg.PairDelay()
+	if delay <= 0 {
+		return nil, fmt.Errorf("Pickup delays must be positive, got %s.", delay)
+	}
+
+	dropoff := nFactory.MakeStop(pickup.Timestamp.Add(delay))
+	if cfg.Locations != nil {
+		loc := cfg.Locations()
+		dropoff.Loc = &loc
+	}
+	dropoff.Demand = -pickup.Demand
+
+	p, _ := network.NewPair(*pickup, *dropoff)
+	return p, nil
+}
+
+// linkPartner makes sure a pickup's edges include one to its own dropoff, even if the delay between them falls outside the edge bounds, so that every pair can be served. Edges stay ordered by destination timestamp.
+func linkPartner(edges []*network.DeliveryEdge, pickup *network.PairedStopNode, speed float64) []*network.DeliveryEdge {
+	dropoff := &pickup.Partner.StopNode
+
+	pos := len(edges)
+	for i, edge := range edges {
+		if edge.Dst.ID() == dropoff.ID() {
+			return edges
+		}
+
+		if edge.Dst.(*network.StopNode).Timestamp.After(dropoff.Timestamp) {
+			pos = i
+			break
+		}
+	}
+
+	edge := &network.DeliveryEdge{
+		Src:  &pickup.StopNode,
+		Dst:  dropoff,
+		Wgt:  float64(dropoff.Timestamp.Sub(pickup.Timestamp)),
+		Knd:  network.StopLinkEdge,
+		Meta: network.TravelMeta(&pickup.StopNode, dropoff, speed),
+	}
+
+	edges = append(edges, nil)
+	copy(edges[pos+1:], edges[pos:])
+	edges[pos] = edge
+
+	return edges
+}
+
+// linkStops returns the stop-to-stop edges leaving nodeList[i], ordered by destination timestamp. nodeList must be sorted by timestamp. Edges between located stops get their travel metadata at speed.
+// Stops sharing the exact same timestamp are not linked.
+func linkStops(nodeList []*network.StopNode, i int, edgeBounds *TimeBox, speed float64) []*network.DeliveryEdge {
+	src := nodeList[i]
+	edges := make([]*network.DeliveryEdge, 0, len(nodeList)-i-1)
+
+	for _, dst := range nodeList[i+1:] {
+		weight := float64(dst.Timestamp.Sub(src.Timestamp))
+
+		if weight <= 0.0 {
+			continue
+		}
+
+		// Every later stop is at least as far away, so there is nothing more to link.
+		if edgeBounds != nil && weight > float64(edgeBounds[1]) {
+			break
+		}
+
+		if edgeBounds == nil || edgeBounds.inBounds(weight) {
+			edges = append(edges, &network.DeliveryEdge{
+				Src:  src,
+				Dst:  dst,
+				Wgt:  weight,
+				Knd:  network.StopLinkEdge,
+				Meta: network.TravelMeta(src, dst, speed),
+			})
+		}
+	}
+
+	return edges
+}
+
+// linkAllStops runs linkStops for every stop in nodeList, using a worker pool if more than one worker is requested. The result is indexed by source position in nodeList.
+func linkAllStops(nodeList []*network.StopNode, edgeBounds *TimeBox, speed float64, workers uint) [][]*network.DeliveryEdge {
+	return linkStopRange(nodeList, 0, len(nodeList), edgeBounds, speed, workers)
+}
+
+// linkStopRange is linkAllStops for the sources nodeList[from:to] only. The result is indexed by source position less from.
+func linkStopRange(nodeList []*network.StopNode, from, to int, edgeBounds *TimeBox, speed float64, workers uint) [][]*network.DeliveryEdge {
+	if workers > 1 {
+		return linkStopsParallel(nodeList, from, to, edgeBounds, speed, int(workers))
+	}
+
+	stopEdges := make([][]*network.DeliveryEdge, to-from)
+	for i := from; i < to; i++ {
+		stopEdges[i-from] = linkStops(nodeList, i, edgeBounds, speed)
+	}
+
+	return stopEdges
+}
+
+// Number of source stops handed to a worker at a time.
+const linkChunkSize = 64
+
+// linkStopsParallel runs linkStops for the sources nodeList[from:to] across the given number of workers. Results are indexed by source position less from, so the output is identical to a sequential pass.
+func linkStopsParallel(nodeList []*network.StopNode, from, to int, edgeBounds *TimeBox, speed float64, workers int) [][]*network.DeliveryEdge {
+	stopEdges := make([][]*network.DeliveryEdge, to-from)
+	chunks := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for start := range chunks {
+				end := start + linkChunkSize
+				if end > to {
+					end = to
+				}
+
+				for i := start; i < end; i++ {
+					stopEdges[i-from] = linkStops(nodeList, i, edgeBounds, speed)
+				}
+			}
+		}()
+	}
+
+	for start := from; start < to; start += linkChunkSize {
+		chunks <- start
+	}
+	close(chunks)
+	wg.Wait()
+
+	return stopEdges
+}
+
+// MakeImplicitDeliveryNetwork samples hubs and stops the same way MakeDeliveryNetwork does, but returns an implicit network that computes its edges on demand instead of storing them.
+// Given the same config and random state, both functions produce the same nodes and the same edges.
+// Implicit networks always link every stop to every hub and know nothing of pairs or transfers, so configs with a hub assignment, pickup-and-delivery pairs or a line haul are rejected, as are configs with a sink.
+func MakeImplicitDeliveryNetwork(cfg DeliveryNetworkConfig) (*network.ImplicitDeliveryNetwork, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	if cfg.Assignment != nil {
+		return nil, fmt.Errorf("Implicit networks do not support hub assignment.")
+	}
+
+	if cfg.Pairs > 0 {
+		return nil, fmt.Errorf("Implicit networks do not support pickup-and-delivery pairs.")
+	}
+
+	if cfg.Sink != nil {
+		return nil, fmt.Errorf("Implicit networks have no edges to pass to a sink.")
+	}
+
+	if cfg.LineHaul > 0 {
+		return nil, fmt.Errorf("Implicit networks do not support hub-to-hub transfers.")
+	}
+
+	nFactory := NewNodeFactory()
+
+	hubs := make([]*network.HubNode, 0, cfg.HubNodes)
+	for i := 0; uint(i) < cfg.HubNodes; i++ {
+		hubs = append(hubs, cfg.makeHub(nFactory, i))
+	}
+
+	stops := make([]*network.StopNode, 0, cfg.StopNodes)
+	for i := 0; uint(i) < cfg.StopNodes; i++ {
+		stops = append(stops, cfg.makeStop(nFactory))
+	}
+
+	G := network.NewImplicitDeliveryNetwork(hubs, stops, (*[2]time.Duration)(cfg.EdgeBounds), hubEdgeWeight)
+	G.Speed = cfg.speed()
+
 	return G, nil
 }
@@ -1,6 +1,7 @@
 package burrow_test
 
 import (
+	"bytes"
 	"math/rand"
 	"time"
 	"sort"
@@ -185,6 +186,44 @@ var _ = Describe("DeliveryGenerator", func() {
 				Expect(G.Edges().Len()).To(Equal(15))
 			})
 
+			It("Stores the kind of every edge it makes", func() {
+				cfg.LineHaul = time.Hour
+				G, err := burrow.MakeDeliveryNetwork(cfg)
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, edges := range G.DEdges {
+					for _, edge := range edges {
+						Expect(edge.Knd).NotTo(BeZero())
+						Expect(edge.Knd).To(Equal((&network.DeliveryEdge{Src: edge.Src, Dst: edge.Dst}).Kind()))
+					}
+				}
+			})
+
+			It("Gives every edge between located nodes its distance and travel time", func() {
+				cfg.LineHaul = time.Hour
+				cfg.Pairs = 2
+				cfg.PairDelay = func() time.Duration { return time.Hour }
+				cfg.Locations = func() network.Location {
+					return network.Location{Lat: 40 + rand.Float64(), Lon: -74 + rand.Float64()}
+				}
+				cfg.Speed = 30
+
+				G, err := burrow.MakeDeliveryNetwork(cfg)
+				Expect(err).NotTo(HaveOccurred())
+
+				for _, edges := range G.DEdges {
+					for _, edge := range edges {
+						Expect(edge.Meta).To(Equal(network.TravelMeta(edge.Src, edge.Dst, 30)))
+						Expect(edge.Meta.Distance).To(BeNumerically(">", 0))
+					}
+				}
+
+				cfg.Locations = nil
+				G, err = burrow.MakeDeliveryNetwork(cfg)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(G.Edges().(*network.DeliveryEdges).Payload[0].Meta).To(BeNil())
+			})
+
 			It("Has stop-to-stop edges that all comply with the happens-before relation", func() {
 				G, err := burrow.MakeDeliveryNetwork(cfg)
 				Expect(err).NotTo(HaveOccurred())
@@ -263,6 +302,213 @@ var _ = Describe("DeliveryGenerator", func() {
 			})
 		})
 
+		When("Given more than one worker", func() {
+			It("Produces exactly the same network as a sequential run under the same seed", func() {
+				cfg.StopNodes = 300
+				cfg.EdgeBounds = &burrow.TimeBox{10 * time.Minute, 8 * time.Hour}
+
+				rand.Seed(5)
+				G, err := burrow.MakeDeliveryNetwork(cfg)
+				Expect(err).NotTo(HaveOccurred())
+
+				cfg.Workers = 4
+				rand.Seed(5)
+				H, err := burrow.MakeDeliveryNetwork(cfg)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(H.DEdges).To(HaveLen(len(G.DEdges)))
+				for id, edges := range G.DEdges {
+					Expect(H.DEdges[id]).To(HaveLen(len(edges)))
+
+					for i, edge := range edges {
+						Expect(H.DEdges[id][i].From().ID()).To(Equal(edge.From().ID()))
+						Expect(H.DEdges[id][i].To().ID()).To(Equal(edge.To().ID()))
+						Expect(H.DEdges[id][i].Weight()).To(Equal(edge.Weight()))
+					}
+				}
+
+				for id, stop := range G.Stops {
+					Expect(H.Stops[id].Timestamp).To(Equal(stop.Timestamp))
+				}
+			})
+		})
+
+		When("Given hub configs", func() {
+			It("Applies each config to the matching hub", func() {
+				hours := &network.OperatingHours{Open: 8 * time.Hour, Close: 20 * time.Hour}
+				cfg.HubConfigs = []burrow.HubConfig{{Hours: hours, Fleet: 3, Capacity: 5}}
+
+				G, err := burrow.MakeDeliveryNetwork(cfg)
+				Expect(err).NotTo(HaveOccurred())
+
+				// Hubs get IDs 1 and 2 in generation order.
+				Expect(G.Hubs[1].Hours).To(Equal(hours))
+				Expect(G.Hubs[1].Fleet).To(BeEquivalentTo(3))
+				Expect(G.Hubs[1].Capacity).To(BeEquivalentTo(5))
+				Expect(G.Hubs[2].Hours).To(BeNil())
+			})
+
+			It("Only dispatches to stops within a hub's operating hours", func() {
+				cfg.StopNodes = 50
+				cfg.HubConfigs = []burrow.HubConfig{{Hours: &network.OperatingHours{Open: 8 * time.Hour, Close: 12 * time.Hour}}}
+
+				G, err := burrow.MakeDeliveryNetwork(cfg)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(len(G.DEdges[1])).To(BeNumerically("<", 50))
+				Expect(G.DEdges[2]).To(HaveLen(50))
+
+				for id, stop := range G.Stops {
+					Expect(G.HasEdgeFromTo(1, id)).To(Equal(G.Hubs[1].IsOpen(stop.Timestamp)))
+					Expect(G.HasEdgeFromTo(id, 1)).To(BeTrue())
+				}
+			})
+
+			It("Rejects more configs than hubs", func() {
+				cfg.HubConfigs = make([]burrow.HubConfig, 3)
+
+				_, err := burrow.MakeDeliveryNetwork(cfg)
+				Expect(err).To(MatchError("Received 3 hub configs for 2 hubs."))
+			})
+
+			It("Rejects operating hours outside a single day", func() {
+				cfg.HubConfigs = []burrow.HubConfig{{Hours: &network.OperatingHours{Open: 8 * time.Hour, Close: 25 * time.Hour}}}
+
+				_, err := burrow.MakeDeliveryNetwork(cfg)
+				Expect(err).To(MatchError("Hub 0: Operating hours must fall within a single day."))
+			})
+		})
+
+		When("Given a line haul", func() {
+			It("Links every pair of hubs both ways with transfer edges", func() {
+				cfg.HubNodes = 3
+				cfg.LineHaul = 2 * time.Hour
+
+				G, err := burrow.MakeDeliveryNetwork(cfg)
+				Expect(err).NotTo(HaveOccurred())
+
+				H := G.GetHubGraph()
+				Expect(H.Edges().Len()).To(Equal(6))
+				for src := range G.Hubs {
+					for dst := range G.Hubs {
+						if src == dst {
+							continue
+						}
+
+						w, ok := G.Weight(src, dst)
+						Expect(ok).To(BeTrue())
+						Expect(w).To(Equal(float64(2 * time.Hour)))
+					}
+
+					// Transfers come before each hub's dispatches.
+					Expect(G.DEdges[src][0].IsTransfer()).To(BeTrue())
+					Expect(G.DEdges[src][1].IsTransfer()).To(BeTrue())
+				}
+
+				Expect(G.GetStopGraph().Hubs).To(BeEmpty())
+			})
+
+			It("Leaves hubs unlinked without one", func() {
+				G, err := burrow.MakeDeliveryNetwork(cfg)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(G.GetHubGraph().DEdges).To(BeEmpty())
+			})
+
+			It("Rejects negative line hauls, and isn't supported by implicit networks", func() {
+				cfg.LineHaul = -time.Hour
+				_, err := burrow.MakeDeliveryNetwork(cfg)
+				Expect(err).To(MatchError("Line-haul duration cannot be negative."))
+
+				cfg.LineHaul = time.Hour
+				_, err = burrow.MakeImplicitDeliveryNetwork(cfg)
+				Expect(err).To(MatchError("Implicit networks do not support hub-to-hub transfers."))
+			})
+		})
+
+		When("Given a sink", func() {
+			It("Streams every node and edge in an order that reads back as the same network", func() {
+				cfg.StopNodes = 40
+				cfg.EdgeBounds = &burrow.TimeBox{0, 4 * time.Hour}
+				cfg.Pairs = 3
+				cfg.PairDelay = func() time.Duration { return time.Hour }
+				cfg.LineHaul = 3 * time.Hour
+
+				var buf bytes.Buffer
+				sink, err := network.NewStreamWriter(&buf)
+				Expect(err).NotTo(HaveOccurred())
+				cfg.Sink = sink
+
+				G, err := burrow.MakeDeliveryNetwork(cfg)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(sink.Close()).To(Succeed())
+
+				reader, err := network.NewStreamReader(&buf)
+				Expect(err).NotTo(HaveOccurred())
+				H, err := reader.ReadNetwork()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(H.Hubs).To(HaveLen(len(G.Hubs)))
+				Expect(H.Stops).To(HaveLen(len(G.Stops)))
+				Expect(H.Pairs).To(HaveLen(len(G.Pairs)))
+				for id, stop := range G.Stops {
+					Expect(H.Stops[id].Timestamp.Equal(stop.Timestamp)).To(BeTrue())
+				}
+
+				Expect(H.DEdges).To(HaveLen(len(G.DEdges)))
+				for id, edges := range G.DEdges {
+					Expect(H.DEdges[id]).To(HaveLen(len(edges)))
+
+					for i, edge := range edges {
+						Expect(H.DEdges[id][i].To().ID()).To(Equal(edge.To().ID()))
+						Expect(H.DEdges[id][i].Weight()).To(Equal(edge.Weight()))
+					}
+				}
+			})
+
+			It("Writes the same stream without keeping the network when asked to only write", func() {
+				cfg.StopNodes = 300
+				cfg.EdgeBounds = &burrow.TimeBox{10 * time.Minute, 8 * time.Hour}
+				cfg.Pairs = 5
+				cfg.PairDelay = func() time.Duration { return time.Hour }
+
+				stream := func(write func() error) []byte {
+					var buf bytes.Buffer
+					sink, err := network.NewStreamWriter(&buf)
+					Expect(err).NotTo(HaveOccurred())
+					cfg.Sink = sink
+
+					rand.Seed(7)
+					Expect(write()).To(Succeed())
+					Expect(sink.Close()).To(Succeed())
+					return buf.Bytes()
+				}
+
+				kept := stream(func() error {
+					_, err := burrow.MakeDeliveryNetwork(cfg)
+					return err
+				})
+
+				for _, workers := range []uint{0, 3} {
+					cfg.Workers = workers
+					written := stream(func() error { return burrow.WriteDeliveryNetwork(cfg) })
+					Expect(written).To(Equal(kept))
+				}
+
+				cfg.Sink = nil
+				Expect(burrow.WriteDeliveryNetwork(cfg)).To(MatchError("Writing a network requires a sink."))
+			})
+
+			It("Is not supported by implicit networks", func() {
+				var buf bytes.Buffer
+				sink, err := network.NewStreamWriter(&buf)
+				Expect(err).NotTo(HaveOccurred())
+				cfg.Sink = sink
+
+				_, err = burrow.MakeImplicitDeliveryNetwork(cfg)
+				Expect(err).To(MatchError("Implicit networks have no edges to pass to a sink."))
+			})
+		})
+
 		When("Passed an empty distribution", func() {
 			It("Returns an error", func() {
 				cfg.Distro = nil
@@ -273,4 +519,105 @@ var _ = Describe("DeliveryGenerator", func() {
 			})
 		})
 	})
+
+	Context("MakeImplicitDeliveryNetwork", func() {
+		var (
+			today time.Time
+			cfg   burrow.DeliveryNetworkConfig
+			err   error
+		)
+
+		BeforeEach(func() {
+			today, err = time.Parse(time.RFC3339, "2022-03-25T00:00:00-04:00")
+			Expect(err).NotTo(HaveOccurred())
+
+			cfg = burrow.DeliveryNetworkConfig{
+				HubNodes:   2,
+				StopNodes:  20,
+				Distro:     testTimeDist(today, window),
+				EdgeBounds: &burrow.TimeBox{30 * time.Minute, 4 * time.Hour},
+			}
+		})
+
+		It("Produces the same nodes and edges as MakeDeliveryNetwork under the same seed", func() {
+			rand.Seed(11)
+			G, err := burrow.MakeDeliveryNetwork(cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			rand.Seed(11)
+			H, err := burrow.MakeImplicitDeliveryNetwork(cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(H.Hubs).To(HaveLen(2))
+			Expect(H.Stops).To(HaveLen(20))
+
+			nEdges := 0
+			nodes := H.Nodes()
+			for nodes.Next() {
+				u := nodes.Node()
+				successors := H.From(u.ID())
+				nEdges += successors.Len()
+
+				for successors.Next() {
+					v := successors.Node()
+					weight, ok := G.Weight(u.ID(), v.ID())
+					Expect(ok).To(BeTrue())
+					Expect(H.WeightedEdge(u.ID(), v.ID()).Weight()).To(Equal(weight))
+				}
+			}
+
+			Expect(nEdges).To(Equal(G.Edges().Len()))
+		})
+
+		It("Agrees with MakeDeliveryNetwork on the metadata of located edges", func() {
+			cfg.Locations = func() network.Location {
+				return network.Location{Lat: 40 + rand.Float64(), Lon: -74 + rand.Float64()}
+			}
+
+			rand.Seed(11)
+			G, err := burrow.MakeDeliveryNetwork(cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			rand.Seed(11)
+			H, err := burrow.MakeImplicitDeliveryNetwork(cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, edge := range G.Edges().(*network.DeliveryEdges).Payload {
+				Expect(edge.Meta).NotTo(BeNil())
+				Expect(H.WeightedEdge(edge.Src.ID(), edge.Dst.ID()).(*network.DeliveryEdge).Meta).To(Equal(edge.Meta))
+			}
+		})
+
+		It("Agrees with MakeDeliveryNetwork on hubs with operating hours", func() {
+			cfg.HubConfigs = []burrow.HubConfig{{Hours: &network.OperatingHours{Open: 9 * time.Hour, Close: 17 * time.Hour}}}
+
+			rand.Seed(11)
+			G, err := burrow.MakeDeliveryNetwork(cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			rand.Seed(11)
+			H, err := burrow.MakeImplicitDeliveryNetwork(cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			for _, hub := range H.Hubs {
+				Expect(H.From(hub.ID()).Len()).To(Equal(len(G.DEdges[hub.ID()])))
+				for _, stop := range H.Stops {
+					Expect(H.HasEdgeFromTo(hub.ID(), stop.ID())).To(Equal(G.HasEdgeFromTo(hub.ID(), stop.ID())))
+					Expect(H.HasEdgeFromTo(stop.ID(), hub.ID())).To(BeTrue())
+				}
+			}
+		})
+
+		It("Rejects the same faulty configs as MakeDeliveryNetwork", func() {
+			cfg.EdgeBounds = &burrow.TimeBox{3 * time.Hour, 2 * time.Hour}
+			H, err := burrow.MakeImplicitDeliveryNetwork(cfg)
+			Expect(H).To(BeNil())
+			Expect(err).To(MatchError("Lower edge bound must not exceed upper edge bound."))
+
+			cfg.EdgeBounds, cfg.Distro = nil, nil
+			H, err = burrow.MakeImplicitDeliveryNetwork(cfg)
+			Expect(H).To(BeNil())
+			Expect(err).To(MatchError("Must receive a non-null sample distribution."))
+		})
+	})
 })
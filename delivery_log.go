@@ -0,0 +1,314 @@
+package burrow
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+// DeliveryLogColumns names the header columns of a delivery log that hold each field. Empty names fall back to those in DefaultDeliveryLogColumns.
+type DeliveryLogColumns struct {
+	Stop      string
+	Hub       string
+	Timestamp string
+
+	// Lat and Lon are optional: if the log lacks either column, stops get no location.
+	Lat string
+	Lon string
+}
+
+// DefaultDeliveryLogColumns are the column names ReadDeliveryLog expects unless told otherwise.
+var DefaultDeliveryLogColumns = DeliveryLogColumns{
+	Stop:      "stop_id",
+	Hub:       "hub_id",
+	Timestamp: "timestamp",
+	Lat:       "lat",
+	Lon:       "lon",
+}
+
+// DeliveryLogConfig describes how to read a delivery log and build a network from it.
+type DeliveryLogConfig struct {
+	Columns DeliveryLogColumns
+
+	// TimeLayout is the layout timestamps are parsed with, as in time.Parse. Defaults to time.RFC3339.
+	TimeLayout string
+
+	// Location is the time zone of timestamps that don't carry their own offset. Defaults to UTC.
+	Location *time.Location
+
+	// Comma is the field delimiter. Defaults to ','.
+	Comma rune
+
+	// EdgeBounds and Workers work as in DeliveryNetworkConfig.
+	EdgeBounds *TimeBox
+	Workers    uint
+}
+
+// RowError is a problem with a single row of a delivery log.
+type RowError struct {
+	Line int
+	Err  error
+}
+
+func (e *RowError) Error() string {
+	return fmt.Sprintf("Line %d: %s", e.Line, e.Err)
+}
+
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// DeliveryLogError reports every bad row found in a delivery log, in line order.
+type DeliveryLogError struct {
+	Rows []*RowError
+}
+
+func (e *DeliveryLogError) Error() string {
+	lines := make([]string, 0, len(e.Rows)+1)
+	lines = append(lines, fmt.Sprintf("Delivery log has %d bad rows:", len(e.Rows)))
+	for _, row := range e.Rows {
+		lines = append(lines, row.Error())
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// LoadDeliveryLog reads the delivery log at path with ReadDeliveryLog.
+func LoadDeliveryLog(path string, cfg DeliveryLogConfig) (*network.DeliveryNetwork, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return ReadDeliveryLog(f, cfg)
+}
+
+// ReadDeliveryLog builds a delivery network from a CSV log of stops. The first row is a header naming the columns; every other row is a stop, giving its ID, the ID of the hub that serves it, its timestamp and optionally its latitude and longitude. Hubs are created as rows mention them, and stop and hub IDs must not overlap.
+//
+// Edges follow the same rules as in MakeDeliveryNetwork: each stop is linked both ways to its hub, and to every later stop within cfg.EdgeBounds.
+//
+// Every row is checked before any network is built. If any are bad, ReadDeliveryLog returns a *DeliveryLogError listing them all with their line numbers.
+func ReadDeliveryLog(r io.Reader, cfg DeliveryLogConfig) (*network.DeliveryNetwork, error) {
+	if err := validateEdgeBounds(cfg.EdgeBounds); err != nil {
+		return nil, err
+	}
+
+	reader := csv.NewReader(r)
+	if cfg.Comma != 0 {
+		reader.Comma = cfg.Comma
+	}
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, fmt.Errorf("Delivery log has no header row.")
+	} else if err != nil {
+		return nil, err
+	}
+
+	cols, err := cfg.columns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	G := network.NewDeliveryNetwork()
+	hubList := make([]*network.HubNode, 0)
+	nodeList := make([]*network.StopNode, 0)
+	stopHubs := make([]*network.HubNode, 0)
+
+	stopLines := make(map[int64]int)
+	bad := make([]*RowError, 0)
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) {
+			bad = append(bad, &RowError{Line: parseErr.Line, Err: parseErr.Err})
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		line, _ := reader.FieldPos(0)
+
+		stop, hubID, err := cfg.parseRow(record, cols)
+		if err == nil {
+			err = checkIDs(G, stop.ID(), hubID, stopLines)
+		}
+
+		if err != nil {
+			bad = append(bad, &RowError{Line: line, Err: err})
+			continue
+		}
+
+		hub, ok := G.Hubs[hubID]
+		if !ok {
+			hub = &network.HubNode{Val: hubID}
+			G.Hubs[hubID] = hub
+			hubList = append(hubList, hub)
+		}
+
+		stopLines[stop.ID()] = line
+		nodeList = append(nodeList, stop)
+		stopHubs = append(stopHubs, hub)
+	}
+
+	if len(bad) > 0 {
+		return nil, &DeliveryLogError{Rows: bad}
+	}
+
+	// Each stop is linked to the hub its row names, through the same assignment hook generated networks use.
+	gen := DeliveryNetworkConfig{
+		Assignment: func(_ []*network.HubNode, _ *network.StopNode, idx int) ([]*network.HubNode, error) {
+			return stopHubs[idx : idx+1], nil
+		},
+	}
+
+	for i, stop := range nodeList {
+		if err := gen.linkHubs(G, hubList, stop, i, true, true); err != nil {
+			return nil, err
+		}
+	}
+
+	SortInPlace(nodeList)
+	stopEdges := linkAllStops(nodeList, cfg.EdgeBounds, gen.speed(), cfg.Workers)
+
+	for i, stop := range nodeList {
+		G.DEdges[stop.ID()] = append(G.DEdges[stop.ID()], stopEdges[i]...)
+		G.Stops[stop.ID()] = stop
+	}
+
+	return G, nil
+}
+
+// logColumns holds the position of each field in a delivery log's rows. Lat and Lon are -1 if the log has no locations.
+type logColumns struct {
+	stop, hub, timestamp, lat, lon int
+}
+
+// columns finds each configured column in header.
+func (cfg DeliveryLogConfig) columns(header []string) (logColumns, error) {
+	names := cfg.Columns
+	defaults := DefaultDeliveryLogColumns
+
+	for _, field := range []struct{ name, fallback *string }{
+		{&names.Stop, &defaults.Stop},
+		{&names.Hub, &defaults.Hub},
+		{&names.Timestamp, &defaults.Timestamp},
+		{&names.Lat, &defaults.Lat},
+		{&names.Lon, &defaults.Lon},
+	} {
+		if *field.name == "" {
+			*field.name = *field.fallback
+		}
+	}
+
+	pos := make(map[string]int, len(header))
+	for i, name := range header {
+		pos[strings.TrimSpace(name)] = i
+	}
+
+	cols := logColumns{lat: -1, lon: -1}
+	for _, field := range []struct {
+		name string
+		idx  *int
+	}{
+		{names.Stop, &cols.stop},
+		{names.Hub, &cols.hub},
+		{names.Timestamp, &cols.timestamp},
+	} {
+		i, ok := pos[field.name]
+		if !ok {
+			return cols, fmt.Errorf("Delivery log has no %q column.", field.name)
+		}
+		*field.idx = i
+	}
+
+	lat, latOk := pos[names.Lat]
+	lon, lonOk := pos[names.Lon]
+	if latOk && lonOk {
+		cols.lat, cols.lon = lat, lon
+	}
+
+	return cols, nil
+}
+
+// parseRow reads a stop and the ID of its hub from a row.
+func (cfg DeliveryLogConfig) parseRow(record []string, cols logColumns) (*network.StopNode, int64, error) {
+	stopID, err := strconv.ParseInt(strings.TrimSpace(record[cols.stop]), 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("Stop ID %q is not an integer.", record[cols.stop])
+	}
+
+	hubID, err := strconv.ParseInt(strings.TrimSpace(record[cols.hub]), 10, 64)
+	if err != nil {
+		return nil, 0, fmt.Errorf("Hub ID %q is not an integer.", record[cols.hub])
+	}
+
+	layout, loc := cfg.TimeLayout, cfg.Location
+	if layout == "" {
+		layout = time.RFC3339
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	ts, err := time.ParseInLocation(layout, strings.TrimSpace(record[cols.timestamp]), loc)
+	if err != nil {
+		return nil, 0, fmt.Errorf("Timestamp %q does not match layout %q.", record[cols.timestamp], layout)
+	}
+
+	stop := &network.StopNode{Val: stopID, Timestamp: ts}
+
+	if cols.lat < 0 {
+		return stop, hubID, nil
+	}
+
+	lat, lon := strings.TrimSpace(record[cols.lat]), strings.TrimSpace(record[cols.lon])
+	switch {
+	case lat == "" && lon == "":
+		return stop, hubID, nil
+	case lat == "" || lon == "":
+		return nil, 0, fmt.Errorf("Stop %d has only one of latitude and longitude.", stopID)
+	}
+
+	var l network.Location
+	if l.Lat, err = strconv.ParseFloat(lat, 64); err != nil || l.Lat < -90 || l.Lat > 90 {
+		return nil, 0, fmt.Errorf("Latitude %q is not a number between -90 and 90.", lat)
+	}
+	if l.Lon, err = strconv.ParseFloat(lon, 64); err != nil || l.Lon < -180 || l.Lon > 180 {
+		return nil, 0, fmt.Errorf("Longitude %q is not a number between -180 and 180.", lon)
+	}
+
+	stop.Loc = &l
+	return stop, hubID, nil
+}
+
+// checkIDs makes sure a row's stop ID is new and doesn't clash with a hub's, and that its hub ID doesn't clash with a stop's.
+func checkIDs(G *network.DeliveryNetwork, stopID, hubID int64, stopLines map[int64]int) error {
+	if line, ok := stopLines[stopID]; ok {
+		return fmt.Errorf("Stop %d was already listed on line %d.", stopID, line)
+	}
+
+	if _, ok := G.Hubs[stopID]; ok || stopID == hubID {
+		return fmt.Errorf("ID %d is used by both a stop and a hub.", stopID)
+	}
+
+	if _, ok := stopLines[hubID]; ok {
+		return fmt.Errorf("ID %d is used by both a stop and a hub.", hubID)
+	}
+
+	return nil
+}
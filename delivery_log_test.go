@@ -0,0 +1,120 @@
+package burrow_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bdshroyer/burrow"
+	"github.com/bdshroyer/burrow/network"
+)
+
+var _ = Describe("Delivery logs", func() {
+	t0 := time.Date(2022, 3, 28, 8, 0, 0, 0, time.UTC)
+
+	const log = `stop_id,hub_id,timestamp,lat,lon
+10,1,2022-03-28T08:00:00Z,40.7,-74.0
+11,1,2022-03-28T08:30:00Z,,
+12,2,2022-03-28T09:00:00Z,40.8,-73.9
+`
+
+	It("Builds a network with the same edge rules as generated networks", func() {
+		G, err := burrow.ReadDeliveryLog(strings.NewReader(log), burrow.DeliveryLogConfig{
+			EdgeBounds: &burrow.TimeBox{0, 45 * time.Minute},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(G.Hubs).To(HaveLen(2))
+		Expect(G.Stops).To(HaveLen(3))
+		Expect(G.Stops[10].Timestamp).To(Equal(t0))
+		Expect(*G.Stops[12].Loc).To(Equal(network.Location{Lat: 40.8, Lon: -73.9}))
+		Expect(G.Stops[11].Loc).To(BeNil())
+
+		// Each stop is linked both ways to its own hub only.
+		Expect(G.HasEdgeFromTo(1, 10)).To(BeTrue())
+		Expect(G.HasEdgeFromTo(10, 1)).To(BeTrue())
+		Expect(G.HasEdgeFromTo(2, 10)).To(BeFalse())
+		Expect(G.HasEdgeFromTo(2, 12)).To(BeTrue())
+
+		// Stop edges run forward in time, within the bounds.
+		Expect(G.HasEdgeFromTo(10, 11)).To(BeTrue())
+		Expect(G.HasEdgeFromTo(11, 12)).To(BeTrue())
+		Expect(G.HasEdgeFromTo(11, 10)).To(BeFalse())
+		Expect(G.HasEdgeFromTo(10, 12)).To(BeFalse())
+	})
+
+	It("Maps columns, delimiters, layouts and time zones", func() {
+		custom := "when;depot;id\n28/03/2022 08:00;1;10\n"
+
+		tz, err := time.LoadLocation("America/New_York")
+		Expect(err).NotTo(HaveOccurred())
+
+		G, err := burrow.ReadDeliveryLog(strings.NewReader(custom), burrow.DeliveryLogConfig{
+			Columns:    burrow.DeliveryLogColumns{Stop: "id", Hub: "depot", Timestamp: "when"},
+			TimeLayout: "02/01/2006 15:04",
+			Location:   tz,
+			Comma:      ';',
+		})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(G.Stops[10].Timestamp.Equal(t0.Add(4 * time.Hour))).To(BeTrue())
+	})
+
+	It("Reports every bad row with its line number", func() {
+		bad := `stop_id,hub_id,timestamp
+10,1,2022-03-28T08:00:00Z
+x,1,2022-03-28T08:00:00Z
+11,1,yesterday
+10,1,2022-03-28T09:00:00Z
+12,1
+13,10,2022-03-28T09:00:00Z
+`
+		_, err := burrow.ReadDeliveryLog(strings.NewReader(bad), burrow.DeliveryLogConfig{})
+
+		var logErr *burrow.DeliveryLogError
+		Expect(errors.As(err, &logErr)).To(BeTrue())
+
+		lines := make([]int, 0)
+		for _, row := range logErr.Rows {
+			lines = append(lines, row.Line)
+		}
+		Expect(lines).To(Equal([]int{3, 4, 5, 6, 7}))
+
+		Expect(logErr.Rows[0]).To(MatchError(`Line 3: Stop ID "x" is not an integer.`))
+		Expect(logErr.Rows[1]).To(MatchError(`Line 4: Timestamp "yesterday" does not match layout "2006-01-02T15:04:05Z07:00".`))
+		Expect(logErr.Rows[2]).To(MatchError("Line 5: Stop 10 was already listed on line 2."))
+		Expect(logErr.Rows[4]).To(MatchError("Line 7: ID 10 is used by both a stop and a hub."))
+		Expect(err.Error()).To(HavePrefix("Delivery log has 5 bad rows:\nLine 3:"))
+	})
+
+	It("Rejects half-given and out-of-range locations", func() {
+		bad := `stop_id,hub_id,timestamp,lat,lon
+10,1,2022-03-28T08:00:00Z,40.7,
+11,1,2022-03-28T08:00:00Z,95,0
+`
+		_, err := burrow.ReadDeliveryLog(strings.NewReader(bad), burrow.DeliveryLogConfig{})
+		Expect(err).To(MatchError(ContainSubstring("Line 2: Stop 10 has only one of latitude and longitude.")))
+		Expect(err).To(MatchError(ContainSubstring(`Line 3: Latitude "95" is not a number between -90 and 90.`)))
+	})
+
+	It("Requires a header with the mandatory columns", func() {
+		_, err := burrow.ReadDeliveryLog(strings.NewReader(""), burrow.DeliveryLogConfig{})
+		Expect(err).To(MatchError("Delivery log has no header row."))
+
+		_, err = burrow.ReadDeliveryLog(strings.NewReader("stop_id,timestamp\n"), burrow.DeliveryLogConfig{})
+		Expect(err).To(MatchError(`Delivery log has no "hub_id" column.`))
+	})
+
+	It("Loads logs from files", func() {
+		path := filepath.Join(GinkgoT().TempDir(), "log.csv")
+		Expect(os.WriteFile(path, []byte(log), 0o644)).To(Succeed())
+
+		G, err := burrow.LoadDeliveryLog(path, burrow.DeliveryLogConfig{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(G.Stops).To(HaveLen(3))
+	})
+})
@@ -0,0 +1,62 @@
+package burrow
+
+import "fmt"
+
+// FixedDemandDistribution produces a SampleDistribution that gives every stop the same demand.
+func FixedDemandDistribution(value float64) (SampleDistribution[float64], error) {
+	if value < 0 {
+		return nil, fmt.Errorf("Demand cannot be negative.")
+	}
+
+	distroFunc := func() float64 {
+		return value
+	}
+
+	return SampleDistribution[float64](distroFunc), nil
+}
+
+// UniformDemandDistribution produces a SampleDistribution of demands drawn uniformly from [min, max).
+func UniformDemandDistribution(min, max float64) (SampleDistribution[float64], error) {
+	return uniformDemandDistribution(globalRand{}, min, max)
+}
+
+func uniformDemandDistribution(rng randSource, min, max float64) (SampleDistribution[float64], error) {
+	if min < 0 {
+		return nil, fmt.Errorf("Demand cannot be negative.")
+	}
+
+	if max <= min {
+		return nil, fmt.Errorf("Maximum demand must exceed minimum demand.")
+	}
+
+	return uniformDistribution(rng, min, max)
+}
+
+// PoissonDemandDistribution produces a SampleDistribution of whole-number demands, such as parcel counts, following a Poisson distribution with the given mean.
+func PoissonDemandDistribution(mean float64) (SampleDistribution[float64], error) {
+	return poissonDemandDistribution(globalRand{}, mean)
+}
+
+func poissonDemandDistribution(rng randSource, mean float64) (SampleDistribution[float64], error) {
+	if mean <= 0 {
+		return nil, fmt.Errorf("Mean demand must be a positive number.")
+	}
+
+	return poissonDistribution[float64](rng, mean)
+}
+
+// parseDemandDistribution converts the spec's demand distribution into a sampling function. Returns a nil distribution if the spec leaves demand unset.
+func (spec *NetworkSpec) parseDemandDistribution(rng randSource) (SampleDistribution[float64], error) {
+	demand := spec.GetDemand()
+
+	switch {
+	case demand.GetFixed() != nil:
+		return FixedDemandDistribution(demand.GetFixed().Value)
+	case demand.GetUniform() != nil:
+		return uniformDemandDistribution(rng, demand.GetUniform().Min, demand.GetUniform().Max)
+	case demand.GetPoisson() != nil:
+		return poissonDemandDistribution(rng, demand.GetPoisson().Mean)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,118 @@
+package burrow_test
+
+import (
+	"math"
+	"math/rand"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gonum.org/v1/gonum/stat"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bdshroyer/burrow"
+)
+
+var _ = Describe("Demand", func() {
+	BeforeEach(func() {
+		rand.Seed(5)
+	})
+
+	sample := func(distro burrow.SampleDistribution[float64], n int) []float64 {
+		values := make([]float64, n)
+		for i := range values {
+			values[i] = distro()
+		}
+		return values
+	}
+
+	It("Gives every stop the same fixed demand", func() {
+		distro, err := burrow.FixedDemandDistribution(2.5)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sample(distro, 10)).To(HaveEach(2.5))
+
+		_, err = burrow.FixedDemandDistribution(-1)
+		Expect(err).To(MatchError("Demand cannot be negative."))
+	})
+
+	It("Samples uniform demand within its range", func() {
+		distro, err := burrow.UniformDemandDistribution(2, 6)
+		Expect(err).NotTo(HaveOccurred())
+
+		values := sample(distro, 10000)
+		for _, v := range values {
+			Expect(v).To(And(BeNumerically(">=", 2), BeNumerically("<", 6)))
+		}
+		Expect(stat.Mean(values, nil)).To(BeNumerically("~", 4, 0.05))
+
+		_, err = burrow.UniformDemandDistribution(6, 2)
+		Expect(err).To(MatchError("Maximum demand must exceed minimum demand."))
+	})
+
+	It("Samples whole-number Poisson demand", func() {
+		distro, err := burrow.PoissonDemandDistribution(4)
+		Expect(err).NotTo(HaveOccurred())
+
+		values := sample(distro, 10000)
+		for _, v := range values {
+			Expect(v).To(Equal(math.Trunc(v)))
+		}
+
+		mean, variance := stat.MeanVariance(values, nil)
+		Expect(mean).To(BeNumerically("~", 4, 0.1))
+		Expect(variance).To(BeNumerically("~", 4, 0.25))
+
+		_, err = burrow.PoissonDemandDistribution(0)
+		Expect(err).To(MatchError("Mean demand must be a positive number."))
+	})
+
+	It("Assigns sampled demand to generated stops", func() {
+		distro, err := burrow.FixedDemandDistribution(3)
+		Expect(err).NotTo(HaveOccurred())
+
+		G, err := burrow.MakeDeliveryNetwork(burrow.DeliveryNetworkConfig{
+			HubNodes:  1,
+			StopNodes: 10,
+			Distro:    testTimeDist(today(), window),
+			Demand:    distro,
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, stop := range G.Stops {
+			Expect(stop.Demand).To(Equal(3.0))
+		}
+	})
+
+	It("Reads the demand distribution from a spec", func() {
+		spec := &burrow.NetworkSpec{
+			Hubs:         1,
+			Stops:        200,
+			Start:        timestamppb.New(today()),
+			End:          timestamppb.New(today().Add(window)),
+			ShortEdge:    durationpb.New(0),
+			LongEdge:     durationpb.New(window),
+			Distribution: &burrow.NetworkSpec_Uniform{Uniform: &burrow.NetworkSpec_UniformDistro{}},
+			Demand: &burrow.NetworkSpec_DemandSpec{
+				Distribution: &burrow.NetworkSpec_DemandSpec_Uniform{
+					Uniform: &burrow.NetworkSpec_DemandSpec_UniformDemand{Min: 1, Max: 2},
+				},
+			},
+		}
+
+		cfg, err := burrow.NewNetworkConfigFrom(spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Demand).NotTo(BeNil())
+
+		G, err := burrow.MakeDeliveryNetwork(*cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, stop := range G.Stops {
+			Expect(stop.Demand).To(And(BeNumerically(">=", 1), BeNumerically("<", 2)))
+		}
+
+		spec.Demand = nil
+		cfg, err = burrow.NewNetworkConfigFrom(spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.Demand).To(BeNil())
+	})
+})
@@ -4,29 +4,44 @@ import (
 	"fmt"
 	"math/rand"
 	"time"
+
+	"gonum.org/v1/gonum/stat/distuv"
 )
 
+// randSource is the subset of *rand.Rand that the distributions in this package draw from. Distributions built by the exported constructors use the shared math/rand source; globalRand adapts it to this interface.
+type randSource interface {
+	Int63n(n int64) int64
+	Float64() float64
+	NormFloat64() float64
+}
+
+type globalRand struct{}
+
+func (globalRand) Int63n(n int64) int64 { return rand.Int63n(n) }
+func (globalRand) Float64() float64     { return rand.Float64() }
+func (globalRand) NormFloat64() float64 { return rand.NormFloat64() }
+
 // MakeUniformDistribution produces a SampleDistribution function with a uniform probability over the given range.
 func MakeUniformDistribution (uniformRange float64) (SampleDistribution[float64], error) {
 	if uniformRange <= 0 {
 		return nil, fmt.Errorf("Distribution range must be a positive number.")
 	}
 
-	distroFunc := func() float64 {
-		return rand.Float64() * uniformRange
-	}
-
-	return SampleDistribution[float64](distroFunc), nil
+	return uniformDistribution[float64](globalRand{}, 0, uniformRange)
 }
 
 // UniformTimestampDistribution produces a SampleDistribution function that generates timestamps over the given range.
 func UniformTimestampDistribution (tStart time.Time, uniformRange time.Duration) (SampleDistribution[time.Time], error) {
+	return uniformTimestampDistribution(globalRand{}, tStart, uniformRange)
+}
+
+func uniformTimestampDistribution(rng randSource, tStart time.Time, uniformRange time.Duration) (SampleDistribution[time.Time], error) {
 	if uniformRange <= 0 {
 		return nil, fmt.Errorf("Requires a non-zero duration")
 	}
 
 	distroFunc := func() time.Time {
-		window := time.Duration(rand.Int63n(int64(uniformRange)))
+		window := time.Duration(rng.Int63n(int64(uniformRange)))
 		return tStart.Add(window)
 	}
 
@@ -38,12 +53,16 @@ func GaussianTimestampDistribution(
 	tMean time.Time,
 	tStdDev time.Duration,
 ) (SampleDistribution[time.Time], error) {
+	return gaussianTimestampDistribution(globalRand{}, tMean, tStdDev)
+}
+
+func gaussianTimestampDistribution(rng randSource, tMean time.Time, tStdDev time.Duration) (SampleDistribution[time.Time], error) {
 	if tStdDev < 0 {
 		return nil, fmt.Errorf("Standard deviation should not be negative")
 	}
 
 	distroFunc := func() time.Time {
-		sample := rand.NormFloat64()
+		sample := rng.NormFloat64()
 		tSample := tMean.Add(time.Duration(int64(sample * float64(tStdDev))))
 
 		return tSample
@@ -51,3 +70,69 @@ func GaussianTimestampDistribution(
 
 	return distroFunc, nil
 }
+
+// TruncatedGaussianTimestampDistribution produces normally distributed timestamps centered on tMean, confined to [tStart, tEnd). Samples are drawn by inverting the normal CDF over the window, so a window far out in a tail costs no more than one around the mean.
+func TruncatedGaussianTimestampDistribution(
+	tMean time.Time,
+	tStdDev time.Duration,
+	tStart, tEnd time.Time,
+) (SampleDistribution[time.Time], error) {
+	return truncatedGaussianTimestampDistribution(globalRand{}, tMean, tStdDev, tStart, tEnd)
+}
+
+func truncatedGaussianTimestampDistribution(rng randSource, tMean time.Time, tStdDev time.Duration, tStart, tEnd time.Time) (SampleDistribution[time.Time], error) {
+	if tStdDev < 0 {
+		return nil, fmt.Errorf("Standard deviation should not be negative")
+	}
+
+	if !tEnd.After(tStart) {
+		return nil, fmt.Errorf("Truncation window must have a positive length.")
+	}
+
+	if tStdDev == 0 {
+		if tMean.Before(tStart) || !tMean.Before(tEnd) {
+			return nil, fmt.Errorf("Mean %s falls outside the truncation window, which a zero standard deviation can't reach.", tMean.Format(time.RFC3339))
+		}
+
+		return func() time.Time { return tMean }, nil
+	}
+
+	sigma := float64(tStdDev)
+	a, b := float64(tStart.Sub(tMean))/sigma, float64(tEnd.Sub(tMean))/sigma
+
+	distroFunc := func() time.Time {
+		tSample := tMean.Add(time.Duration(truncatedNormal(rng, a, b) * sigma))
+
+		// Rounding to whole nanoseconds can land a sample on the excluded end of the window.
+		if !tSample.Before(tEnd) {
+			tSample = tEnd.Add(-time.Nanosecond)
+		} else if tSample.Before(tStart) {
+			tSample = tStart
+		}
+
+		return tSample
+	}
+
+	return distroFunc, nil
+}
+
+// truncatedNormal draws a standard normal sample confined to [a, b) by inverting its CDF. Windows above the mean are mirrored below it, where the CDF is small and keeps its precision.
+func truncatedNormal(rng randSource, a, b float64) float64 {
+	mirrored := a > 0
+	if mirrored {
+		a, b = -b, -a
+	}
+
+	pa, pb := distuv.UnitNormal.CDF(a), distuv.UnitNormal.CDF(b)
+
+	z := b
+	if pb > pa {
+		z = distuv.UnitNormal.Quantile(pa + rng.Float64()*(pb-pa))
+	}
+
+	if mirrored {
+		return -z
+	}
+
+	return z
+}
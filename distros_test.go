@@ -9,6 +9,7 @@ import (
 	"github.com/bdshroyer/burrow/testutils"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
+	"gonum.org/v1/gonum/stat"
 	"gonum.org/v1/gonum/stat/distuv"
 )
 
@@ -126,4 +127,61 @@ var _ = Describe("Distros", func() {
 			})
 		})
 	})
+
+	Context("TruncatedGaussianTimestampDistribution", func() {
+		var (
+			tMu    time.Time
+			tSigma time.Duration
+		)
+
+		BeforeEach(func() {
+			tMu = today().Add(11 * time.Hour)
+			tSigma = 2 * time.Hour
+		})
+
+		sample := func(distro burrow.SampleDistribution[time.Time], n int) []float64 {
+			samples := make([]float64, 0, n)
+			for i := 0; i < n; i++ {
+				samples = append(samples, float64(distro().Sub(tMu))/float64(tSigma))
+			}
+
+			return samples
+		}
+
+		It("Confines samples to the window with the truncated normal's mean", func() {
+			distro, err := burrow.TruncatedGaussianTimestampDistribution(tMu, tSigma, tMu.Add(tSigma), tMu.Add(3*tSigma))
+			Expect(err).NotTo(HaveOccurred())
+
+			samples := sample(distro, 10000)
+			Expect(samples).To(HaveEach(And(BeNumerically(">=", 1), BeNumerically("<", 3))))
+
+			// The mean of a standard normal truncated to [a, b) is (φ(a) - φ(b)) / (Φ(b) - Φ(a)).
+			norm := distuv.UnitNormal
+			expected := (norm.Prob(1) - norm.Prob(3)) / (norm.CDF(3) - norm.CDF(1))
+			Expect(stat.Mean(samples, nil)).To(BeNumerically("~", expected, 0.01))
+		})
+
+		It("Samples windows deep in a tail", func() {
+			distro, err := burrow.TruncatedGaussianTimestampDistribution(tMu, tSigma, tMu.Add(-12*tSigma), tMu.Add(-10*tSigma))
+			Expect(err).NotTo(HaveOccurred())
+
+			samples := sample(distro, 1000)
+			Expect(samples).To(HaveEach(And(BeNumerically(">=", -12), BeNumerically("<", -10))))
+
+			// Nearly all the mass lies just inside the edge closest to the mean.
+			Expect(stat.Mean(samples, nil)).To(BeNumerically("~", -10.1, 0.05))
+		})
+
+		It("Rejects empty windows and means a zero deviation can't leave", func() {
+			_, err := burrow.TruncatedGaussianTimestampDistribution(tMu, tSigma, tMu, tMu)
+			Expect(err).To(MatchError("Truncation window must have a positive length."))
+
+			_, err = burrow.TruncatedGaussianTimestampDistribution(tMu, 0, tMu.Add(time.Hour), tMu.Add(2*time.Hour))
+			Expect(err).To(MatchError(ContainSubstring("falls outside the truncation window")))
+
+			distro, err := burrow.TruncatedGaussianTimestampDistribution(tMu, 0, tMu, tMu.Add(time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(distro()).To(Equal(tMu))
+		})
+	})
 })
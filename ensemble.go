@@ -0,0 +1,268 @@
+package burrow
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+
+	"gonum.org/v1/gonum/stat"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+// NodeMetric computes a per-node score, such as a centrality measure, over a generated network. The returned map is keyed by node ID.
+type NodeMetric func(G *network.DeliveryNetwork) map[int64]float64
+
+// DefaultQuantiles are the quantiles reported by Ensemble when none are configured.
+var DefaultQuantiles = []float64{0.05, 0.25, 0.5, 0.75, 0.95}
+
+// EnsembleConfig describes a batch of random networks drawn from the same spec.
+//
+// Instance i is generated from its own random source seeded with Seed + i, so results do not depend on the number of workers or on the order in which instances finish.
+type EnsembleConfig struct {
+	Spec      *NetworkSpec
+	Instances uint
+	Seed      int64
+
+	// Workers sets the number of instances generated concurrently. Values of 0 or 1 generate them one at a time.
+	Workers uint
+
+	Metrics   map[string]NodeMetric
+	Quantiles []float64
+}
+
+// Quantile pairs a probability with the corresponding sample quantile.
+type Quantile struct {
+	P     float64
+	Value float64
+}
+
+// NodeRank records how consistently a node ranks under a metric across an ensemble. Rank 1 is the highest-scoring node in an instance; tied nodes share the mean of their ranks.
+type NodeRank struct {
+	ID         int64
+	Instances  int
+	MeanRank   float64
+	RankStdDev float64
+}
+
+// MetricSummary aggregates a single metric over every node of every instance in an ensemble.
+type MetricSummary struct {
+	Name      string
+	Samples   int
+	Mean      float64
+	Variance  float64
+	Quantiles []Quantile
+
+	// Ranks is sorted by node ID.
+	Ranks []NodeRank
+}
+
+// Ensemble generates cfg.Instances networks from cfg.Spec, applies every configured metric to each and summarises the results. Summaries are returned sorted by metric name.
+func Ensemble(cfg EnsembleConfig) ([]MetricSummary, error) {
+	if cfg.Spec == nil {
+		return nil, fmt.Errorf("Ensemble requires a network spec.")
+	}
+
+	if cfg.Instances == 0 {
+		return nil, fmt.Errorf("Ensemble requires at least one instance.")
+	}
+
+	if len(cfg.Metrics) == 0 {
+		return nil, fmt.Errorf("Ensemble requires at least one metric.")
+	}
+
+	names := make([]string, 0, len(cfg.Metrics))
+	for name := range cfg.Metrics {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	// results[i][m] holds the scores of metric names[m] on instance i.
+	results := make([][]map[int64]float64, cfg.Instances)
+	errs := make([]error, cfg.Instances)
+
+	instances := make(chan int)
+	workers := int(cfg.Workers)
+	if workers < 1 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range instances {
+				results[i], errs[i] = runInstance(cfg, names, cfg.Seed+int64(i))
+			}
+		}()
+	}
+
+	for i := 0; uint(i) < cfg.Instances; i++ {
+		instances <- i
+	}
+	close(instances)
+	wg.Wait()
+
+	for i, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("Instance %d: %w", i, err)
+		}
+	}
+
+	quantiles := cfg.Quantiles
+	if quantiles == nil {
+		quantiles = DefaultQuantiles
+	}
+
+	summaries := make([]MetricSummary, 0, len(names))
+	for m, name := range names {
+		scores := make([]map[int64]float64, len(results))
+		for i := range results {
+			scores[i] = results[i][m]
+		}
+
+		summaries = append(summaries, summarizeMetric(name, scores, quantiles))
+	}
+
+	return summaries, nil
+}
+
+// runInstance generates a single ensemble member from its own seed and scores it with each named metric.
+func runInstance(cfg EnsembleConfig, names []string, seed int64) ([]map[int64]float64, error) {
+	G, err := MakeNetworkFromSpec(cfg.Spec, rand.New(rand.NewSource(seed)))
+	if err != nil {
+		return nil, err
+	}
+
+	scores := make([]map[int64]float64, len(names))
+	for m, name := range names {
+		scores[m] = cfg.Metrics[name](G)
+	}
+
+	return scores, nil
+}
+
+// summarizeMetric pools a metric's scores across instances and computes per-node rank statistics.
+func summarizeMetric(name string, scores []map[int64]float64, quantiles []float64) MetricSummary {
+	summary := MetricSummary{Name: name}
+
+	pooled := make([]float64, 0)
+	ranks := make(map[int64][]float64)
+
+	for _, instance := range scores {
+		for _, value := range instance {
+			pooled = append(pooled, value)
+		}
+
+		for id, rank := range rankNodes(instance) {
+			ranks[id] = append(ranks[id], rank)
+		}
+	}
+
+	// Sorting first also fixes the summation order, so the statistics don't wobble with map iteration order.
+	sort.Float64s(pooled)
+
+	summary.Samples = len(pooled)
+	if len(pooled) > 0 {
+		summary.Mean, summary.Variance = stat.MeanVariance(pooled, nil)
+		if len(pooled) == 1 {
+			summary.Variance = 0.0
+		}
+
+		for _, p := range quantiles {
+			summary.Quantiles = append(summary.Quantiles, Quantile{P: p, Value: stat.Quantile(p, stat.Empirical, pooled, nil)})
+		}
+	}
+
+	summary.Ranks = make([]NodeRank, 0, len(ranks))
+	for id, nodeRanks := range ranks {
+		rank := NodeRank{ID: id, Instances: len(nodeRanks)}
+		rank.MeanRank, rank.RankStdDev = stat.PopMeanStdDev(nodeRanks, nil)
+		summary.Ranks = append(summary.Ranks, rank)
+	}
+
+	sort.Slice(summary.Ranks, func(i, j int) bool {
+		return summary.Ranks[i].ID < summary.Ranks[j].ID
+	})
+
+	return summary
+}
+
+// rankNodes ranks the nodes of a single instance in descending order of score. Ties share the mean of the ranks they span.
+func rankNodes(scores map[int64]float64) map[int64]float64 {
+	ids := make([]int64, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, id)
+	}
+
+	sort.Slice(ids, func(i, j int) bool {
+		if scores[ids[i]] != scores[ids[j]] {
+			return scores[ids[i]] > scores[ids[j]]
+		}
+		return ids[i] < ids[j]
+	})
+
+	ranks := make(map[int64]float64, len(ids))
+	for start := 0; start < len(ids); {
+		end := start + 1
+		for end < len(ids) && scores[ids[end]] == scores[ids[start]] {
+			end++
+		}
+
+		// Ranks are 1-based, so positions start..end-1 hold ranks start+1..end.
+		shared := float64(start+1+end) / 2.0
+		for _, id := range ids[start:end] {
+			ranks[id] = shared
+		}
+
+		start = end
+	}
+
+	return ranks
+}
+
+// WriteEnsembleCSV writes ensemble summaries to w in long format, with the header metric,statistic,node,value.
+// Pooled statistics (mean, variance and quantiles such as q0.5) leave the node column empty. Per-node rank statistics are reported as mean_rank and rank_stddev.
+func WriteEnsembleCSV(w io.Writer, summaries []MetricSummary) error {
+	out := csv.NewWriter(w)
+
+	if err := out.Write([]string{"metric", "statistic", "node", "value"}); err != nil {
+		return err
+	}
+
+	for _, s := range summaries {
+		rows := [][]string{
+			{s.Name, "samples", "", strconv.Itoa(s.Samples)},
+			{s.Name, "mean", "", formatFloat(s.Mean)},
+			{s.Name, "variance", "", formatFloat(s.Variance)},
+		}
+
+		for _, q := range s.Quantiles {
+			rows = append(rows, []string{s.Name, "q" + formatFloat(q.P), "", formatFloat(q.Value)})
+		}
+
+		for _, r := range s.Ranks {
+			node := strconv.FormatInt(r.ID, 10)
+			rows = append(rows,
+				[]string{s.Name, "mean_rank", node, formatFloat(r.MeanRank)},
+				[]string{s.Name, "rank_stddev", node, formatFloat(r.RankStdDev)},
+			)
+		}
+
+		if err := out.WriteAll(rows); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
@@ -0,0 +1,174 @@
+package burrow_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bdshroyer/burrow"
+	"github.com/bdshroyer/burrow/network"
+)
+
+var _ = Describe("Ensemble", func() {
+	var cfg burrow.EnsembleConfig
+
+	BeforeEach(func() {
+		cfg = burrow.EnsembleConfig{
+			Spec: &burrow.NetworkSpec{
+				Hubs:         2,
+				Stops:        12,
+				Start:        timestamppb.New(today()),
+				End:          timestamppb.New(today().Add(24 * time.Hour)),
+				ShortEdge:    durationpb.New(0),
+				LongEdge:     durationpb.New(6 * time.Hour),
+				Distribution: &burrow.NetworkSpec_Uniform{Uniform: &burrow.NetworkSpec_UniformDistro{}},
+			},
+			Instances: 8,
+			Seed:      17,
+			Metrics: map[string]burrow.NodeMetric{
+				"out-degree": burrow.OutDegree,
+				"in-degree":  burrow.InDegree,
+			},
+		}
+	})
+
+	It("Summarises every metric over every node of every instance", func() {
+		summaries, err := burrow.Ensemble(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(summaries).To(HaveLen(2))
+
+		// Sorted by metric name.
+		Expect(summaries[0].Name).To(Equal("in-degree"))
+		Expect(summaries[1].Name).To(Equal("out-degree"))
+
+		for _, s := range summaries {
+			Expect(s.Samples).To(Equal(8 * 14))
+			Expect(s.Quantiles).To(HaveLen(len(burrow.DefaultQuantiles)))
+			Expect(s.Ranks).To(HaveLen(14))
+
+			for _, r := range s.Ranks {
+				Expect(r.Instances).To(Equal(8))
+				Expect(r.MeanRank).To(And(BeNumerically(">=", 1), BeNumerically("<=", 14)))
+			}
+		}
+
+		// Every edge has exactly one source and one destination.
+		Expect(summaries[0].Mean).To(BeNumerically("~", summaries[1].Mean, 1e-9))
+	})
+
+	It("Is reproducible and independent of the worker count", func() {
+		first, err := burrow.Ensemble(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg.Workers = 3
+		second, err := burrow.Ensemble(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(second).To(Equal(first))
+
+		cfg.Seed = 18
+		third, err := burrow.Ensemble(cfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(third).NotTo(Equal(first))
+	})
+
+	It("Assigns tied nodes the mean of their ranks", func() {
+		cfg.Metrics = map[string]burrow.NodeMetric{
+			"constant": func(G *network.DeliveryNetwork) map[int64]float64 {
+				return map[int64]float64{1: 1.0, 2: 1.0, 3: 0.0}
+			},
+		}
+
+		summaries, err := burrow.Ensemble(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		ranks := summaries[0].Ranks
+		Expect(ranks).To(HaveLen(3))
+		Expect(ranks[0].MeanRank).To(BeEquivalentTo(1.5))
+		Expect(ranks[1].MeanRank).To(BeEquivalentTo(1.5))
+		Expect(ranks[2].MeanRank).To(BeEquivalentTo(3.0))
+		Expect(ranks[2].RankStdDev).To(BeZero())
+	})
+
+	It("Rejects incomplete configs", func() {
+		bad := cfg
+		bad.Spec = nil
+		_, err := burrow.Ensemble(bad)
+		Expect(err).To(MatchError("Ensemble requires a network spec."))
+
+		bad = cfg
+		bad.Instances = 0
+		_, err = burrow.Ensemble(bad)
+		Expect(err).To(MatchError("Ensemble requires at least one instance."))
+
+		bad = cfg
+		bad.Metrics = nil
+		_, err = burrow.Ensemble(bad)
+		Expect(err).To(MatchError("Ensemble requires at least one metric."))
+	})
+
+	Describe("WriteEnsembleCSV", func() {
+		It("Writes summaries in long format", func() {
+			summaries := []burrow.MetricSummary{
+				{
+					Name:      "out-degree",
+					Samples:   4,
+					Mean:      2.5,
+					Variance:  1.25,
+					Quantiles: []burrow.Quantile{{P: 0.5, Value: 2.0}},
+					Ranks:     []burrow.NodeRank{{ID: 3, Instances: 2, MeanRank: 1.5, RankStdDev: 0.5}},
+				},
+			}
+
+			var buf bytes.Buffer
+			Expect(burrow.WriteEnsembleCSV(&buf, summaries)).To(Succeed())
+
+			records, err := csv.NewReader(&buf).ReadAll()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(records).To(Equal([][]string{
+				{"metric", "statistic", "node", "value"},
+				{"out-degree", "samples", "", "4"},
+				{"out-degree", "mean", "", "2.5"},
+				{"out-degree", "variance", "", "1.25"},
+				{"out-degree", "q0.5", "", "2"},
+				{"out-degree", "mean_rank", "3", "1.5"},
+				{"out-degree", "rank_stddev", "3", "0.5"},
+			}))
+		})
+	})
+})
+
+var _ = Describe("Node metrics", func() {
+	var G *network.DeliveryNetwork
+
+	BeforeEach(func() {
+		G = network.NewDeliveryNetwork()
+		G.Hubs[1] = &network.HubNode{Val: 1}
+		G.Stops[2] = &network.StopNode{Val: 2}
+		G.Stops[3] = &network.StopNode{Val: 3}
+
+		G.DEdges[1] = []*network.DeliveryEdge{
+			{Src: G.Hubs[1], Dst: G.Stops[2], Wgt: 1.0},
+			{Src: G.Hubs[1], Dst: G.Stops[3], Wgt: 1.0},
+		}
+		G.DEdges[2] = []*network.DeliveryEdge{{Src: G.Stops[2], Dst: G.Stops[3], Wgt: 1.0}}
+	})
+
+	It("Counts outbound edges", func() {
+		Expect(burrow.OutDegree(G)).To(Equal(map[int64]float64{1: 2, 2: 1, 3: 0}))
+	})
+
+	It("Counts inbound edges", func() {
+		Expect(burrow.InDegree(G)).To(Equal(map[int64]float64{1: 0, 2: 1, 3: 2}))
+	})
+
+	It("Scores every node under betweenness", func() {
+		scores := burrow.Betweenness(G)
+		Expect(scores).To(HaveLen(3))
+	})
+})
@@ -0,0 +1,196 @@
+package burrow
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+// HubAssignment selects the hubs that serve a stop. Hub-to-stop and stop-to-hub edges are only drawn between a stop and the hubs assigned to it.
+//
+// hubs holds every hub in the network in generation order, and idx is the stop's 0-based position in generation order.
+type HubAssignment func(hubs []*network.HubNode, stop *network.StopNode, idx int) ([]*network.HubNode, error)
+
+// LocationDistribution is a function that returns a location drawn from some distribution.
+type LocationDistribution func() network.Location
+
+// UniformLocationDistribution produces a LocationDistribution that samples uniformly by latitude and longitude within the box bounded by the south-west and north-east corners.
+func UniformLocationDistribution(southWest, northEast network.Location) (LocationDistribution, error) {
+	return uniformLocationDistribution(globalRand{}, southWest, northEast)
+}
+
+func uniformLocationDistribution(rng randSource, southWest, northEast network.Location) (LocationDistribution, error) {
+	if southWest.Lat > northEast.Lat || southWest.Lon > northEast.Lon {
+		return nil, fmt.Errorf("South-west corner must lie south and west of the north-east corner.")
+	}
+
+	distroFunc := func() network.Location {
+		return network.Location{
+			Lat: southWest.Lat + rng.Float64()*(northEast.Lat-southWest.Lat),
+			Lon: southWest.Lon + rng.Float64()*(northEast.Lon-southWest.Lon),
+		}
+	}
+
+	return distroFunc, nil
+}
+
+// NearestHubAssignment assigns each stop to the single hub closest to it. Ties go to the hub generated first.
+// Every hub and stop must have a location, which in practice means the config needs a Locations distribution.
+func NearestHubAssignment() HubAssignment {
+	return func(hubs []*network.HubNode, stop *network.StopNode, idx int) ([]*network.HubNode, error) {
+		if stop.Loc == nil {
+			return nil, fmt.Errorf("Nearest-hub assignment requires stop %d to have a location.", stop.ID())
+		}
+
+		var nearest *network.HubNode
+		best := math.Inf(1)
+
+		for _, hub := range hubs {
+			if hub.Loc == nil {
+				return nil, fmt.Errorf("Nearest-hub assignment requires hub %d to have a location.", hub.ID())
+			}
+
+			if d := network.Distance(*hub.Loc, *stop.Loc); d < best {
+				nearest, best = hub, d
+			}
+		}
+
+		if nearest == nil {
+			return []*network.HubNode{}, nil
+		}
+
+		return []*network.HubNode{nearest}, nil
+	}
+}
+
+// ProportionalHubAssignment assigns each stop to a single hub chosen at random, where hub i is chosen with probability proportional to weights[i].
+// There must be exactly one weight per hub; this is checked when the assignment runs.
+func ProportionalHubAssignment(weights []float64) (HubAssignment, error) {
+	return proportionalHubAssignment(globalRand{}, weights)
+}
+
+func proportionalHubAssignment(rng randSource, weights []float64) (HubAssignment, error) {
+	total := 0.0
+	for _, w := range weights {
+		if w < 0 {
+			return nil, fmt.Errorf("Hub weights cannot be negative.")
+		}
+		total += w
+	}
+
+	if total <= 0 {
+		return nil, fmt.Errorf("Hub weights must sum to a positive number.")
+	}
+
+	cumulative := make([]float64, len(weights))
+	sum := 0.0
+	for i, w := range weights {
+		sum += w
+		cumulative[i] = sum / total
+	}
+
+	assignment := func(hubs []*network.HubNode, stop *network.StopNode, idx int) ([]*network.HubNode, error) {
+		if len(hubs) != len(weights) {
+			return nil, fmt.Errorf("Expected %d hub weights, got %d.", len(hubs), len(weights))
+		}
+
+		u := rng.Float64()
+		for i, c := range cumulative {
+			if u < c {
+				return []*network.HubNode{hubs[i]}, nil
+			}
+		}
+
+		// Only reachable through rounding error in the last cumulative weight.
+		return []*network.HubNode{hubs[len(hubs)-1]}, nil
+	}
+
+	return assignment, nil
+}
+
+// ExplicitHubAssignment assigns stops to hubs according to a membership table mapping each hub's index to the indices of the stops it serves. A stop may belong to several hubs; stops belonging to none have no hub edges.
+func ExplicitHubAssignment(members map[int][]int) HubAssignment {
+	servedBy := make(map[int][]int)
+	for hub, stops := range members {
+		for _, stop := range stops {
+			servedBy[stop] = append(servedBy[stop], hub)
+		}
+	}
+
+	return func(hubs []*network.HubNode, stop *network.StopNode, idx int) ([]*network.HubNode, error) {
+		for _, member := range servedBy[idx] {
+			if member < 0 || member >= len(hubs) {
+				return nil, fmt.Errorf("Hub index %d is out of range.", member)
+			}
+		}
+
+		assigned := make([]*network.HubNode, 0, len(servedBy[idx]))
+
+		// Walk the hubs in order so edge order doesn't depend on map iteration order.
+		for h, hub := range hubs {
+			for _, member := range servedBy[idx] {
+				if member == h {
+					assigned = append(assigned, hub)
+					break
+				}
+			}
+		}
+
+		return assigned, nil
+	}
+}
+
+// parseHubAssignment converts the spec's assignment and area into a HubAssignment and a LocationDistribution. Either may be nil if the spec leaves it unset.
+func (spec *NetworkSpec) parseHubAssignment(rng randSource) (HubAssignment, LocationDistribution, error) {
+	var locations LocationDistribution
+	var err error
+
+	if area := spec.GetArea(); area != nil {
+		locations, err = uniformLocationDistribution(
+			rng,
+			network.Location{Lat: area.South, Lon: area.West},
+			network.Location{Lat: area.North, Lon: area.East},
+		)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	assignment := spec.GetAssignment()
+	if assignment == nil {
+		return nil, locations, nil
+	}
+
+	switch {
+	case assignment.GetNearest() != nil:
+		if locations == nil {
+			return nil, nil, fmt.Errorf("Nearest-hub assignment requires an area")
+		}
+		return NearestHubAssignment(), locations, nil
+
+	case assignment.GetProportional() != nil:
+		weights := assignment.GetProportional().Weights
+		if len(weights) != int(spec.Hubs) {
+			return nil, nil, fmt.Errorf("Expected %d hub weights, got %d", spec.Hubs, len(weights))
+		}
+
+		hubAssignment, err := proportionalHubAssignment(rng, weights)
+		return hubAssignment, locations, err
+
+	case assignment.GetExplicit() != nil:
+		members := make(map[int][]int)
+		for _, membership := range assignment.GetExplicit().Members {
+			if membership.Hub >= spec.Hubs {
+				return nil, nil, fmt.Errorf("Hub index %d is out of range", membership.Hub)
+			}
+
+			for _, stop := range membership.Stops {
+				members[int(membership.Hub)] = append(members[int(membership.Hub)], int(stop))
+			}
+		}
+		return ExplicitHubAssignment(members), locations, nil
+	}
+
+	return nil, locations, nil
+}
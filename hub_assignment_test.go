@@ -0,0 +1,246 @@
+package burrow_test
+
+import (
+	"math/rand"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bdshroyer/burrow"
+	"github.com/bdshroyer/burrow/network"
+)
+
+// hubsOf returns the IDs of the hubs with an edge to stop, in edge order.
+func hubsOf(G *network.DeliveryNetwork, stop int64) []int64 {
+	ids := make([]int64, 0)
+	for _, edge := range G.DEdges[stop] {
+		if edge.Dst.IsHub() {
+			ids = append(ids, edge.Dst.ID())
+		}
+	}
+	return ids
+}
+
+var _ = Describe("HubAssignment", func() {
+	var (
+		today time.Time
+		cfg   burrow.DeliveryNetworkConfig
+	)
+
+	BeforeEach(func() {
+		today = time.Date(2022, 3, 28, 0, 0, 0, 0, time.UTC)
+		cfg = burrow.DeliveryNetworkConfig{
+			HubNodes:  3,
+			StopNodes: 20,
+			Distro:    testTimeDist(today, window),
+		}
+	})
+
+	It("Links every stop to every hub when no assignment is given", func() {
+		G, err := burrow.MakeDeliveryNetwork(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		for id := range G.Stops {
+			Expect(hubsOf(G, id)).To(HaveLen(3))
+		}
+	})
+
+	Describe("NearestHubAssignment", func() {
+		It("Links each stop, in both directions, to its closest hub only", func() {
+			locations, err := burrow.UniformLocationDistribution(
+				network.Location{Lat: 51.3, Lon: -0.5},
+				network.Location{Lat: 51.7, Lon: 0.3},
+			)
+			Expect(err).NotTo(HaveOccurred())
+
+			cfg.Locations = locations
+			cfg.Assignment = burrow.NearestHubAssignment()
+
+			G, err := burrow.MakeDeliveryNetwork(cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			for id, stop := range G.Stops {
+				Expect(stop.Loc).NotTo(BeNil())
+
+				hubs := hubsOf(G, id)
+				Expect(hubs).To(HaveLen(1))
+
+				nearest := network.Distance(*G.Hubs[hubs[0]].Loc, *stop.Loc)
+				for _, hub := range G.Hubs {
+					Expect(network.Distance(*hub.Loc, *stop.Loc)).To(BeNumerically(">=", nearest))
+				}
+
+				Expect(G.HasEdgeFromTo(hubs[0], id)).To(BeTrue())
+			}
+		})
+
+		It("Fails when nodes have no location", func() {
+			cfg.Assignment = burrow.NearestHubAssignment()
+
+			_, err := burrow.MakeDeliveryNetwork(cfg)
+			Expect(err).To(MatchError(ContainSubstring("to have a location")))
+		})
+	})
+
+	Describe("ProportionalHubAssignment", func() {
+		It("Only assigns stops to hubs with a positive weight", func() {
+			assignment, err := burrow.ProportionalHubAssignment([]float64{0, 1, 3})
+			Expect(err).NotTo(HaveOccurred())
+
+			cfg.StopNodes = 200
+			cfg.Assignment = assignment
+
+			G, err := burrow.MakeDeliveryNetwork(cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Hubs get IDs 1 to 3 in generation order.
+			perHub := map[int64]int{}
+			for id := range G.Stops {
+				hubs := hubsOf(G, id)
+				Expect(hubs).To(HaveLen(1))
+				perHub[hubs[0]]++
+			}
+
+			Expect(perHub).NotTo(HaveKey(int64(1)))
+			Expect(perHub[3]).To(BeNumerically(">", perHub[2]))
+			Expect(G.DEdges[1]).To(BeEmpty())
+		})
+
+		It("Rejects unusable weights", func() {
+			_, err := burrow.ProportionalHubAssignment([]float64{1, -1})
+			Expect(err).To(HaveOccurred())
+
+			_, err = burrow.ProportionalHubAssignment([]float64{0, 0})
+			Expect(err).To(HaveOccurred())
+		})
+
+		It("Fails when the weights don't match the hubs", func() {
+			assignment, err := burrow.ProportionalHubAssignment([]float64{1, 1})
+			Expect(err).NotTo(HaveOccurred())
+
+			cfg.Assignment = assignment
+			_, err = burrow.MakeDeliveryNetwork(cfg)
+			Expect(err).To(MatchError(ContainSubstring("Expected 3 hub weights")))
+		})
+	})
+
+	Describe("ExplicitHubAssignment", func() {
+		It("Links stops to the hubs listing them as members", func() {
+			cfg.StopNodes = 3
+			cfg.Assignment = burrow.ExplicitHubAssignment(map[int][]int{
+				0: {0, 1},
+				2: {1},
+			})
+
+			G, err := burrow.MakeDeliveryNetwork(cfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			// Stops get IDs 4 to 6 in generation order.
+			Expect(hubsOf(G, 4)).To(Equal([]int64{1}))
+			Expect(hubsOf(G, 5)).To(Equal([]int64{1, 3}))
+			Expect(hubsOf(G, 6)).To(BeEmpty())
+			Expect(G.DEdges[2]).To(BeEmpty())
+		})
+
+		It("Fails on an out-of-range hub", func() {
+			cfg.Assignment = burrow.ExplicitHubAssignment(map[int][]int{5: {0}})
+
+			_, err := burrow.MakeDeliveryNetwork(cfg)
+			Expect(err).To(MatchError(ContainSubstring("out of range")))
+		})
+	})
+
+	It("Is rejected by the implicit generator", func() {
+		cfg.Assignment = burrow.NearestHubAssignment()
+
+		_, err := burrow.MakeImplicitDeliveryNetwork(cfg)
+		Expect(err).To(HaveOccurred())
+	})
+
+	Describe("Spec parsing", func() {
+		var spec *burrow.NetworkSpec
+
+		BeforeEach(func() {
+			spec = &burrow.NetworkSpec{
+				Hubs:         2,
+				Stops:        30,
+				Start:        timestamppb.New(today),
+				End:          timestamppb.New(today.Add(window)),
+				ShortEdge:    durationpb.New(0),
+				LongEdge:     durationpb.New(window),
+				Distribution: &burrow.NetworkSpec_Uniform{Uniform: &burrow.NetworkSpec_UniformDistro{}},
+			}
+		})
+
+		It("Builds a nearest-hub config from an area", func() {
+			spec.Area = &burrow.NetworkSpec_LocationBox{South: 40.6, West: -74.1, North: 40.9, East: -73.8}
+			spec.Assignment = &burrow.NetworkSpec_HubAssignment{
+				Mode: &burrow.NetworkSpec_HubAssignment_Nearest{Nearest: &burrow.NetworkSpec_HubAssignment_NearestHub{}},
+			}
+
+			netCfg, err := burrow.NewSeededNetworkConfig(spec, rand.New(rand.NewSource(7)))
+			Expect(err).NotTo(HaveOccurred())
+
+			G, err := burrow.MakeDeliveryNetwork(*netCfg)
+			Expect(err).NotTo(HaveOccurred())
+
+			for id, stop := range G.Stops {
+				Expect(stop.Loc.Lat).To(BeNumerically("~", 40.75, 0.15))
+				Expect(stop.Loc.Lon).To(BeNumerically("~", -73.95, 0.15))
+				Expect(hubsOf(G, id)).To(HaveLen(1))
+			}
+		})
+
+		It("Requires an area for nearest-hub assignment", func() {
+			spec.Assignment = &burrow.NetworkSpec_HubAssignment{
+				Mode: &burrow.NetworkSpec_HubAssignment_Nearest{Nearest: &burrow.NetworkSpec_HubAssignment_NearestHub{}},
+			}
+
+			_, err := burrow.NewNetworkConfigFrom(spec)
+			Expect(err).To(MatchError(ContainSubstring("requires an area")))
+		})
+
+		It("Builds a proportional config with one weight per hub", func() {
+			spec.Assignment = &burrow.NetworkSpec_HubAssignment{
+				Mode: &burrow.NetworkSpec_HubAssignment_Proportional{
+					Proportional: &burrow.NetworkSpec_HubAssignment_ProportionalHubs{Weights: []float64{1}},
+				},
+			}
+
+			_, err := burrow.NewNetworkConfigFrom(spec)
+			Expect(err).To(MatchError(ContainSubstring("Expected 2 hub weights")))
+
+			spec.Assignment.GetProportional().Weights = []float64{0, 1}
+			netCfg, err := burrow.NewNetworkConfigFrom(spec)
+			Expect(err).NotTo(HaveOccurred())
+
+			G, err := burrow.MakeDeliveryNetwork(*netCfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(G.DEdges[1]).To(BeEmpty())
+		})
+
+		It("Builds an explicit config from memberships", func() {
+			spec.Stops = 2
+			spec.Assignment = &burrow.NetworkSpec_HubAssignment{
+				Mode: &burrow.NetworkSpec_HubAssignment_Explicit{
+					Explicit: &burrow.NetworkSpec_HubAssignment_ExplicitHubs{
+						Members: []*burrow.NetworkSpec_HubAssignment_Membership{
+							{Hub: 1, Stops: []uint32{0, 1}},
+						},
+					},
+				},
+			}
+
+			netCfg, err := burrow.NewNetworkConfigFrom(spec)
+			Expect(err).NotTo(HaveOccurred())
+
+			G, err := burrow.MakeDeliveryNetwork(*netCfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(G.DEdges[1]).To(BeEmpty())
+			Expect(G.DEdges[2]).To(HaveLen(2))
+		})
+	})
+})
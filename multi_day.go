@@ -0,0 +1,275 @@
+package burrow
+
+import (
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+// DayConfig describes the stops generated for a single day of a multi-day network.
+type DayConfig struct {
+	StopNodes uint
+	Distro    SampleDistribution[time.Time]
+
+	// ActiveHubs holds the 0-based indices, in generation order, of the hubs operating this day. If empty, every hub operates.
+	ActiveHubs []int
+}
+
+// MultiDayNetworkConfig describes a network spanning a sequence of days, each with its own stop volume, distribution and hubs.
+type MultiDayNetworkConfig struct {
+	HubNodes   uint
+	Days       []DayConfig
+	EdgeBounds *TimeBox
+
+	// DayBoundary is the time of day, as an offset from midnight in Location, that separates consecutive days. Every stop of a day must fall between the same pair of boundaries, so no stop-to-stop edge crosses one.
+	DayBoundary time.Duration
+
+	// Location is the time zone in which day boundaries fall. Defaults to UTC.
+	Location *time.Location
+
+	// Workers sets the number of goroutines used to build stop-to-stop edges, as in DeliveryNetworkConfig.
+	Workers uint
+
+	// LineHaul, if positive, links every pair of hubs both ways with transfer edges of this weight, as in DeliveryNetworkConfig. Transfers don't depend on which hubs are active on a given day.
+	LineHaul time.Duration
+
+	// HubConfigs, Assignment, Locations, Speed and Demand work as in DeliveryNetworkConfig. The assignment sees every hub, and stops are indexed across days in generation order; hubs it picks that aren't active on the stop's day are dropped.
+	HubConfigs []HubConfig
+	Assignment HubAssignment
+	Locations  LocationDistribution
+	Speed      float64
+	Demand     SampleDistribution[float64]
+}
+
+// NewMultiDayNetworkConfig generates a MultiDayNetworkConfig from a NetworkSpec with a non-empty Days list. Day boundaries are taken in UTC.
+// Multi-day networks have no pickup-and-delivery pairs, so specs asking for them are rejected.
+func NewMultiDayNetworkConfig(spec *NetworkSpec) (*MultiDayNetworkConfig, error) {
+	return newMultiDayNetworkConfig(spec, globalRand{})
+}
+
+// NewSeededMultiDayNetworkConfig works like NewMultiDayNetworkConfig, except that every day's distribution draws from rng.
+func NewSeededMultiDayNetworkConfig(spec *NetworkSpec, rng *rand.Rand) (*MultiDayNetworkConfig, error) {
+	if rng == nil {
+		return nil, fmt.Errorf("Must receive a non-null random source.")
+	}
+
+	return newMultiDayNetworkConfig(spec, rng)
+}
+
+func newMultiDayNetworkConfig(spec *NetworkSpec, rng randSource) (*MultiDayNetworkConfig, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("No network spec provided")
+	}
+
+	if len(spec.Days) == 0 {
+		return nil, fmt.Errorf("Multi-day spec requires at least one day")
+	}
+
+	if spec.GetPairs().GetCount() > 0 {
+		return nil, fmt.Errorf("Multi-day specs do not support pickup-and-delivery pairs")
+	}
+
+	assignment, locations, err := spec.parseHubAssignment(rng)
+	if err != nil {
+		return nil, err
+	}
+
+	demand, err := spec.parseDemandDistribution(rng)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &MultiDayNetworkConfig{
+		HubNodes:    uint(spec.Hubs),
+		Days:        make([]DayConfig, 0, len(spec.Days)),
+		EdgeBounds:  &TimeBox{spec.ShortEdge.AsDuration(), spec.LongEdge.AsDuration()},
+		DayBoundary: spec.DayBoundary.AsDuration(),
+		Location:    time.UTC,
+		LineHaul:    spec.LineHaul.AsDuration(),
+		HubConfigs:  spec.parseHubConfigs(),
+		Assignment:  assignment,
+		Locations:   locations,
+		Demand:      demand,
+	}
+
+	for i, day := range spec.Days {
+		distro, err := parseTimestampDistribution(day.GetUniform(), day.GetGaussian(), day.Start, day.End, spec.ClampToWindow, rng)
+		if err != nil {
+			return nil, fmt.Errorf("Day %d: %w", i, err)
+		}
+
+		if distro == nil {
+			return nil, fmt.Errorf("Day %d: No distribution provided", i)
+		}
+
+		activeHubs := make([]int, 0, len(day.ActiveHubs))
+		for _, hub := range day.ActiveHubs {
+			activeHubs = append(activeHubs, int(hub))
+		}
+
+		cfg.Days = append(cfg.Days, DayConfig{
+			StopNodes:  uint(day.Stops),
+			Distro:     distro,
+			ActiveHubs: activeHubs,
+		})
+	}
+
+	return cfg, nil
+}
+
+func (cfg MultiDayNetworkConfig) validate() error {
+	if len(cfg.Days) == 0 {
+		return fmt.Errorf("Must receive at least one day.")
+	}
+
+	if cfg.DayBoundary < 0 || cfg.DayBoundary >= 24*time.Hour {
+		return fmt.Errorf("Day boundary must fall within a single day.")
+	}
+
+	if cfg.LineHaul < 0 {
+		return fmt.Errorf("Line-haul duration cannot be negative.")
+	}
+
+	if err := validateHubConfigs(cfg.HubConfigs, cfg.HubNodes); err != nil {
+		return err
+	}
+
+	for i, day := range cfg.Days {
+		dayCfg := DeliveryNetworkConfig{Distro: day.Distro, EdgeBounds: cfg.EdgeBounds}
+		if err := dayCfg.validate(); err != nil {
+			return fmt.Errorf("Day %d: %w", i, err)
+		}
+
+		for _, hub := range day.ActiveHubs {
+			if hub < 0 || uint(hub) >= cfg.HubNodes {
+				return fmt.Errorf("Day %d: Active hub index %d is out of range.", i, hub)
+			}
+		}
+	}
+
+	return nil
+}
+
+// serviceDate returns the calendar date of the service day containing t, where service days start at boundary past midnight in loc.
+func serviceDate(t time.Time, boundary time.Duration, loc *time.Location) time.Time {
+	year, month, day := t.In(loc).Add(-boundary).Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, loc)
+}
+
+// baseConfig returns the single-day config sharing cfg's hubs, edge bounds and stop attributes. It has no distribution of its own.
+func (cfg MultiDayNetworkConfig) baseConfig() DeliveryNetworkConfig {
+	return DeliveryNetworkConfig{
+		HubNodes:   cfg.HubNodes,
+		EdgeBounds: cfg.EdgeBounds,
+		Workers:    cfg.Workers,
+		Assignment: cfg.Assignment,
+		Locations:  cfg.Locations,
+		Speed:      cfg.Speed,
+		HubConfigs: cfg.HubConfigs,
+		Demand:     cfg.Demand,
+		LineHaul:   cfg.LineHaul,
+	}
+}
+
+// activeAssignment restricts assignment, or every hub if it is nil, to the hubs in active.
+func activeAssignment(assignment HubAssignment, active map[int64]bool) HubAssignment {
+	return func(hubs []*network.HubNode, stop *network.StopNode, idx int) ([]*network.HubNode, error) {
+		assigned := hubs
+		if assignment != nil {
+			var err error
+			if assigned, err = assignment(hubs, stop, idx); err != nil {
+				return nil, err
+			}
+		}
+
+		kept := make([]*network.HubNode, 0, len(assigned))
+		for _, hub := range assigned {
+			if active[hub.ID()] {
+				kept = append(kept, hub)
+			}
+		}
+
+		return kept, nil
+	}
+}
+
+// MakeMultiDayNetwork creates a single delivery network covering every day in the config.
+//
+// Each day's stops are sampled from its own distribution and tagged with the day's index. Hubs and stops get the same attributes as in MakeDeliveryNetwork, and each stop is linked in both directions to the hubs assigned to it among those active that day. Stop-to-stop edges follow the same rules as in MakeDeliveryNetwork, except that they only join stops of the same day.
+// Returns an error if a day's stops don't all fall between the same pair of day boundaries, which can happen when a day's distribution isn't confined to its window.
+func MakeMultiDayNetwork(cfg MultiDayNetworkConfig) (*network.DeliveryNetwork, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+
+	loc := cfg.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	G := network.NewDeliveryNetwork()
+	nFactory := NewNodeFactory()
+	base := cfg.baseConfig()
+
+	hubList := make([]*network.HubNode, 0, cfg.HubNodes)
+	for i := 0; uint(i) < cfg.HubNodes; i++ {
+		newHub := base.makeHub(nFactory, i)
+		G.Hubs[newHub.ID()] = newHub
+		G.DEdges[newHub.ID()] = make([]*network.DeliveryEdge, 0)
+		hubList = append(hubList, newHub)
+	}
+
+	linkTransfers(G, hubList, cfg.LineHaul, base.speed())
+
+	// Stops are indexed across days, as hub assignments expect.
+	idx := 0
+
+	for d, day := range cfg.Days {
+		dayCfg := base
+		dayCfg.Distro = day.Distro
+
+		if len(day.ActiveHubs) > 0 {
+			active := make(map[int64]bool, len(day.ActiveHubs))
+			for _, h := range day.ActiveHubs {
+				active[hubList[h].ID()] = true
+			}
+			dayCfg.Assignment = activeAssignment(base.Assignment, active)
+		}
+
+		nodeList := make([]*network.StopNode, 0, day.StopNodes)
+		var date time.Time
+
+		for i := 0; uint(i) < day.StopNodes; i++ {
+			newStop := dayCfg.makeStop(nFactory)
+			newStop.Day = d
+
+			stopDate := serviceDate(newStop.Timestamp, cfg.DayBoundary, loc)
+			if i == 0 {
+				date = stopDate
+			} else if !stopDate.Equal(date) {
+				return nil, fmt.Errorf("Day %d: Stop at %s crosses the day boundary; confine the day's distribution to its window.", d, newStop.Timestamp.Format(time.RFC3339))
+			}
+
+			G.DEdges[newStop.ID()] = make([]*network.DeliveryEdge, 0, len(hubList))
+			if err := dayCfg.linkHubs(G, hubList, newStop, idx, true, true); err != nil {
+				return nil, err
+			}
+
+			nodeList = append(nodeList, newStop)
+			idx++
+		}
+
+		SortInPlace(nodeList)
+
+		stopEdges := linkAllStops(nodeList, cfg.EdgeBounds, base.speed(), cfg.Workers)
+
+		for i, stop := range nodeList {
+			G.DEdges[stop.ID()] = append(G.DEdges[stop.ID()], stopEdges[i]...)
+			G.Stops[stop.ID()] = stop
+		}
+	}
+
+	return G, nil
+}
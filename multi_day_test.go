@@ -0,0 +1,279 @@
+package burrow_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bdshroyer/burrow"
+	"github.com/bdshroyer/burrow/network"
+)
+
+var _ = Describe("MultiDayNetwork", func() {
+	var (
+		monday time.Time
+		cfg    burrow.MultiDayNetworkConfig
+	)
+
+	BeforeEach(func() {
+		monday = time.Date(2022, 3, 28, 0, 0, 0, 0, time.UTC)
+
+		// Each day's stops are drawn from 06:00 to 22:00, and the boundary sits at 03:00.
+		days := make([]burrow.DayConfig, 0, 3)
+		for d, stops := range []uint{10, 4, 7} {
+			days = append(days, burrow.DayConfig{
+				StopNodes: stops,
+				Distro:    testTimeDist(monday.Add(time.Duration(24*d+6)*time.Hour), 16*time.Hour),
+			})
+		}
+		days[1].ActiveHubs = []int{1}
+
+		cfg = burrow.MultiDayNetworkConfig{
+			HubNodes:    2,
+			Days:        days,
+			DayBoundary: 3 * time.Hour,
+		}
+	})
+
+	It("Generates every day's stops and tags them with their day", func() {
+		G, err := burrow.MakeMultiDayNetwork(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(G.Hubs).To(HaveLen(2))
+		Expect(G.Stops).To(HaveLen(21))
+
+		perDay := map[int]int{}
+		for _, stop := range G.Stops {
+			perDay[stop.Day]++
+			Expect(stop.Timestamp.Day()).To(Equal(28 + stop.Day))
+		}
+
+		Expect(perDay).To(Equal(map[int]int{0: 10, 1: 4, 2: 7}))
+	})
+
+	It("Links each stop only to the hubs active on its day", func() {
+		G, err := burrow.MakeMultiDayNetwork(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Hub 1 is generated first and idles on day 1; hub 2 runs every day.
+		Expect(G.DEdges[1]).To(HaveLen(17))
+		Expect(G.DEdges[2]).To(HaveLen(21))
+
+		for _, edge := range G.DEdges[1] {
+			Expect(edge.Dst.(*network.StopNode).Day).NotTo(Equal(1))
+		}
+	})
+
+	It("Links hubs with transfers whatever days they are active", func() {
+		cfg.LineHaul = 90 * time.Minute
+
+		G, err := burrow.MakeMultiDayNetwork(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(G.DEdges[1]).To(HaveLen(18))
+		Expect(G.HasEdgeFromTo(1, 2)).To(BeTrue())
+		Expect(G.HasEdgeFromTo(2, 1)).To(BeTrue())
+		Expect(G.GetHubGraph().Edges().Len()).To(Equal(2))
+	})
+
+	It("Never draws a stop-to-stop edge across the day boundary", func() {
+		G, err := burrow.MakeMultiDayNetwork(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		stopEdges := 0
+		for _, edges := range G.DEdges {
+			for _, edge := range edges {
+				if edge.Src.IsHub() || edge.Dst.IsHub() {
+					continue
+				}
+
+				stopEdges++
+				Expect(edge.Src.(*network.StopNode).Day).To(Equal(edge.Dst.(*network.StopNode).Day))
+				Expect(edge.Src.(*network.StopNode).Timestamp).To(BeTemporally("<", edge.Dst.(*network.StopNode).Timestamp))
+			}
+		}
+
+		// Without the boundary, stops on later days would all be linked to every earlier stop.
+		Expect(stopEdges).To(BeNumerically("<=", 10*9/2+4*3/2+7*6/2))
+		Expect(stopEdges).To(BeNumerically(">", 0))
+	})
+
+	It("Rejects a day whose stops straddle the day boundary", func() {
+		cfg.DayBoundary = 14 * time.Hour
+
+		_, err := burrow.MakeMultiDayNetwork(cfg)
+		Expect(err).To(MatchError(ContainSubstring("Day 0: Stop at")))
+		Expect(err).To(MatchError(ContainSubstring("crosses the day boundary")))
+	})
+
+	It("Gives hubs and stops the same attributes as single-day networks", func() {
+		hours := &network.OperatingHours{Open: 0, Close: 23 * time.Hour}
+		cfg.HubConfigs = []burrow.HubConfig{{Hours: hours, Fleet: 3, Capacity: 40}}
+		cfg.Locations = func() network.Location { return network.Location{Lat: 40.7, Lon: -74} }
+		cfg.Demand = func() float64 { return 2.5 }
+
+		G, err := burrow.MakeMultiDayNetwork(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(G.Hubs[1].Hours).To(Equal(hours))
+		Expect(G.Hubs[1].Fleet).To(BeEquivalentTo(3))
+		Expect(G.Hubs[1].Capacity).To(BeEquivalentTo(40))
+		Expect(G.Hubs[2].Loc).NotTo(BeNil())
+
+		for _, stop := range G.Stops {
+			Expect(stop.Loc).NotTo(BeNil())
+			Expect(stop.Demand).To(Equal(2.5))
+		}
+	})
+
+	It("Applies the hub assignment across days, keeping only the active hubs", func() {
+		// Stop indices run across days: 0-9 on day 0, 10-13 on day 1 and 14-20 on day 2.
+		cfg.Assignment = burrow.ExplicitHubAssignment(map[int][]int{0: {0, 10, 14}, 1: {0, 11}})
+
+		G, err := burrow.MakeMultiDayNetwork(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		// Hub 1 idles on day 1, so stop 10 loses its only hub.
+		Expect(G.DEdges[1]).To(HaveLen(2))
+		Expect(G.DEdges[2]).To(HaveLen(2))
+	})
+
+	It("Rejects invalid configs", func() {
+		bad := cfg
+		bad.Days = nil
+		_, err := burrow.MakeMultiDayNetwork(bad)
+		Expect(err).To(MatchError("Must receive at least one day."))
+
+		bad = cfg
+		bad.DayBoundary = 25 * time.Hour
+		_, err = burrow.MakeMultiDayNetwork(bad)
+		Expect(err).To(MatchError("Day boundary must fall within a single day."))
+
+		bad = cfg
+		bad.LineHaul = -time.Minute
+		_, err = burrow.MakeMultiDayNetwork(bad)
+		Expect(err).To(MatchError("Line-haul duration cannot be negative."))
+
+		bad = cfg
+		bad.Days = []burrow.DayConfig{{StopNodes: 2, Distro: cfg.Days[0].Distro, ActiveHubs: []int{2}}}
+		_, err = burrow.MakeMultiDayNetwork(bad)
+		Expect(err).To(MatchError("Day 0: Active hub index 2 is out of range."))
+
+		bad = cfg
+		bad.Days = []burrow.DayConfig{{StopNodes: 2}}
+		_, err = burrow.MakeMultiDayNetwork(bad)
+		Expect(err).To(MatchError("Day 0: Must receive a non-null sample distribution."))
+	})
+
+	Describe("NewMultiDayNetworkConfig", func() {
+		It("Builds a config from the spec's days", func() {
+			uniform := &burrow.NetworkSpec_DaySpec_Uniform{Uniform: &burrow.NetworkSpec_UniformDistro{}}
+
+			spec := &burrow.NetworkSpec{
+				Hubs:        3,
+				ShortEdge:   durationpb.New(0),
+				LongEdge:    durationpb.New(4 * time.Hour),
+				DayBoundary: durationpb.New(2 * time.Hour),
+				Days: []*burrow.NetworkSpec_DaySpec{
+					{
+						Stops:        5,
+						Distribution: uniform,
+						Start:        timestamppb.New(monday.Add(8 * time.Hour)),
+						End:          timestamppb.New(monday.Add(18 * time.Hour)),
+					},
+					{
+						Stops:        8,
+						Distribution: uniform,
+						Start:        timestamppb.New(monday.Add(32 * time.Hour)),
+						End:          timestamppb.New(monday.Add(42 * time.Hour)),
+						ActiveHubs:   []uint32{0, 2},
+					},
+				},
+			}
+
+			cfg, err := burrow.NewMultiDayNetworkConfig(spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(cfg.HubNodes).To(BeEquivalentTo(3))
+			Expect(cfg.DayBoundary).To(Equal(2 * time.Hour))
+			Expect(cfg.Days).To(HaveLen(2))
+			Expect(cfg.Days[0].StopNodes).To(BeEquivalentTo(5))
+			Expect(cfg.Days[1].ActiveHubs).To(Equal([]int{0, 2}))
+
+			sample := cfg.Days[1].Distro()
+			Expect(sample).To(BeTemporally(">=", monday.Add(32*time.Hour)))
+			Expect(sample).To(BeTemporally("<", monday.Add(42*time.Hour)))
+
+			G, err := burrow.MakeMultiDayNetwork(*cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(G.Stops).To(HaveLen(13))
+		})
+
+		It("Carries the spec's hub attributes, area and demand", func() {
+			spec := &burrow.NetworkSpec{
+				Hubs:          2,
+				Area:          &burrow.NetworkSpec_LocationBox{South: 40, West: -75, North: 41, East: -74},
+				Assignment:    &burrow.NetworkSpec_HubAssignment{Mode: &burrow.NetworkSpec_HubAssignment_Nearest{Nearest: &burrow.NetworkSpec_HubAssignment_NearestHub{}}},
+				HubAttributes: []*burrow.NetworkSpec_HubSpec{{Fleet: 4}},
+				Demand:        &burrow.NetworkSpec_DemandSpec{Distribution: &burrow.NetworkSpec_DemandSpec_Fixed{Fixed: &burrow.NetworkSpec_DemandSpec_FixedDemand{Value: 1.5}}},
+				Days: []*burrow.NetworkSpec_DaySpec{{
+					Stops:        6,
+					Distribution: &burrow.NetworkSpec_DaySpec_Uniform{Uniform: &burrow.NetworkSpec_UniformDistro{}},
+					Start:        timestamppb.New(monday.Add(8 * time.Hour)),
+					End:          timestamppb.New(monday.Add(18 * time.Hour)),
+				}},
+			}
+
+			cfg, err := burrow.NewMultiDayNetworkConfig(spec)
+			Expect(err).NotTo(HaveOccurred())
+
+			G, err := burrow.MakeMultiDayNetwork(*cfg)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(G.Hubs[1].Fleet).To(BeEquivalentTo(4))
+
+			// Nearest-hub assignment links every stop to exactly one hub, and back.
+			for id, stop := range G.Stops {
+				Expect(stop.Demand).To(Equal(1.5))
+				Expect(G.DEdges[id]).To(ContainElement(WithTransform(func(e *network.DeliveryEdge) bool { return e.Dst.IsHub() }, BeTrue())))
+			}
+			Expect(len(G.DEdges[1]) + len(G.DEdges[2])).To(Equal(6))
+		})
+
+		It("Is reached through MakeNetworkFromSpec, but not NewNetworkConfig", func() {
+			spec := &burrow.NetworkSpec{
+				Hubs: 1,
+				Days: []*burrow.NetworkSpec_DaySpec{{
+					Stops:        3,
+					Distribution: &burrow.NetworkSpec_DaySpec_Uniform{Uniform: &burrow.NetworkSpec_UniformDistro{}},
+					Start:        timestamppb.New(monday.Add(8 * time.Hour)),
+					End:          timestamppb.New(monday.Add(18 * time.Hour)),
+				}},
+			}
+
+			G, err := burrow.MakeNetworkFromSpec(spec, nil)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(G.Stops).To(HaveLen(3))
+
+			_, err = burrow.NewNetworkConfigFrom(spec)
+			Expect(err).To(MatchError("Spec describes a multi-day network; use NewMultiDayNetworkConfig"))
+		})
+
+		It("Rejects specs without days or with a day missing its distribution", func() {
+			_, err := burrow.NewMultiDayNetworkConfig(&burrow.NetworkSpec{Hubs: 1})
+			Expect(err).To(MatchError("Multi-day spec requires at least one day"))
+
+			_, err = burrow.NewMultiDayNetworkConfig(&burrow.NetworkSpec{
+				Days: []*burrow.NetworkSpec_DaySpec{{Stops: 1}},
+			})
+			Expect(err).To(MatchError("Day 0: No distribution provided"))
+
+			_, err = burrow.NewMultiDayNetworkConfig(&burrow.NetworkSpec{
+				Days:  []*burrow.NetworkSpec_DaySpec{{Stops: 1}},
+				Pairs: &burrow.NetworkSpec_PairSpec{Count: 2},
+			})
+			Expect(err).To(MatchError("Multi-day specs do not support pickup-and-delivery pairs"))
+		})
+	})
+})
@@ -0,0 +1,115 @@
+package network
+
+import "fmt"
+
+// CSR is a compressed sparse row view of a delivery network: nodes are numbered 0 to Len()-1, and the out-edges of node i are Targets[Offsets[i]:Offsets[i+1]], with matching Weights. It holds no node or edge structs, so it suits algorithms that sweep very large networks.
+//
+// IDs maps each index back to its node ID and Index does the reverse. Hub[i] is true if node i is a hub.
+type CSR struct {
+	IDs   []int64
+	Index map[int64]int
+	Hub   []bool
+
+	Offsets []int
+	Targets []int
+	Weights []float64
+}
+
+// NewCSR builds a CSR view of G. Nodes are numbered hubs first, then stops, each in ID order, and each node's edges keep their order in G. Edges to or from nodes that G doesn't hold are left out.
+func NewCSR(G *DeliveryNetwork) *CSR {
+	b := newCSRBuilder(len(G.Hubs) + len(G.Stops))
+
+	for _, id := range sortedIDs(G.Hubs) {
+		b.addNode(id, true)
+	}
+	for _, id := range sortedIDs(G.Stops) {
+		b.addNode(id, false)
+	}
+
+	for _, src := range sortedIDs(G.DEdges) {
+		for _, edge := range G.DEdges[src] {
+			_ = b.addEdge(edge.Src.ID(), edge.Dst.ID(), edge.Wgt)
+		}
+	}
+
+	return b.build()
+}
+
+// Len returns the number of nodes.
+func (C *CSR) Len() int {
+	return len(C.IDs)
+}
+
+// Edges returns the number of edges.
+func (C *CSR) Edges() int {
+	return len(C.Targets)
+}
+
+// From returns the indices of the nodes node i has edges to, and the weights of those edges. The slices share the CSR's storage and must not be modified.
+func (C *CSR) From(i int) ([]int, []float64) {
+	lo, hi := C.Offsets[i], C.Offsets[i+1]
+	return C.Targets[lo:hi], C.Weights[lo:hi]
+}
+
+// csrBuilder collects edges in any order, as coordinate lists, and sorts them into rows with a counting sort once every edge is in.
+type csrBuilder struct {
+	C        *CSR
+	src, dst []int
+	wgt      []float64
+}
+
+func newCSRBuilder(nodes int) *csrBuilder {
+	return &csrBuilder{
+		C: &CSR{
+			IDs:   make([]int64, 0, nodes),
+			Index: make(map[int64]int, nodes),
+			Hub:   make([]bool, 0, nodes),
+		},
+	}
+}
+
+func (b *csrBuilder) addNode(id int64, hub bool) {
+	b.C.Index[id] = len(b.C.IDs)
+	b.C.IDs = append(b.C.IDs, id)
+	b.C.Hub = append(b.C.Hub, hub)
+}
+
+func (b *csrBuilder) addEdge(src, dst int64, wgt float64) error {
+	u, ok := b.C.Index[src]
+	if !ok {
+		return fmt.Errorf("Edge refers to unknown node %d.", src)
+	}
+
+	v, ok := b.C.Index[dst]
+	if !ok {
+		return fmt.Errorf("Edge refers to unknown node %d.", dst)
+	}
+
+	b.src, b.dst, b.wgt = append(b.src, u), append(b.dst, v), append(b.wgt, wgt)
+	return nil
+}
+
+// build lays the collected edges out by source. Edges from the same node keep the order they were added in.
+func (b *csrBuilder) build() *CSR {
+	C := b.C
+	C.Offsets = make([]int, len(C.IDs)+1)
+	for _, u := range b.src {
+		C.Offsets[u+1]++
+	}
+	for i := 1; i < len(C.Offsets); i++ {
+		C.Offsets[i] += C.Offsets[i-1]
+	}
+
+	C.Targets = make([]int, len(b.src))
+	C.Weights = make([]float64, len(b.src))
+
+	next := make([]int, len(C.IDs))
+	copy(next, C.Offsets)
+	for k, u := range b.src {
+		C.Targets[next[u]], C.Weights[next[u]] = b.dst[k], b.wgt[k]
+		next[u]++
+	}
+
+	b.src, b.dst, b.wgt = nil, nil, nil
+	return C
+}
@@ -0,0 +1,52 @@
+package network_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+var _ = Describe("CSR", func() {
+	It("Lays out each node's edges by index, hubs first", func() {
+		t0 := time.Date(2022, 3, 28, 8, 0, 0, 0, time.UTC)
+
+		G := network.NewDeliveryNetwork()
+		hub := &network.HubNode{Val: 7}
+		a := &network.StopNode{Val: 2, Timestamp: t0}
+		b := &network.StopNode{Val: 3, Timestamp: t0.Add(time.Minute)}
+
+		G.Hubs[7] = hub
+		G.Stops[2], G.Stops[3] = a, b
+		G.DEdges[7] = []*network.DeliveryEdge{{Src: hub, Dst: b, Wgt: 5}, {Src: hub, Dst: a, Wgt: 4}}
+		G.DEdges[2] = []*network.DeliveryEdge{{Src: a, Dst: b, Wgt: 1}}
+		G.DEdges[3] = []*network.DeliveryEdge{{Src: b, Dst: hub, Wgt: 6}}
+
+		C := network.NewCSR(G)
+		Expect(C.Len()).To(Equal(3))
+		Expect(C.Edges()).To(Equal(4))
+		Expect(C.IDs).To(Equal([]int64{7, 2, 3}))
+		Expect(C.Hub).To(Equal([]bool{true, false, false}))
+		Expect(C.Offsets).To(Equal([]int{0, 2, 3, 4}))
+
+		targets, weights := C.From(C.Index[7])
+		Expect(targets).To(Equal([]int{2, 1}))
+		Expect(weights).To(Equal([]float64{5, 4}))
+
+		targets, weights = C.From(C.Index[3])
+		Expect(targets).To(Equal([]int{0}))
+		Expect(weights).To(Equal([]float64{6}))
+	})
+
+	It("Handles networks without edges", func() {
+		G := network.NewDeliveryNetwork()
+		G.Hubs[1] = &network.HubNode{Val: 1}
+
+		C := network.NewCSR(G)
+		targets, _ := C.From(0)
+		Expect(targets).To(BeEmpty())
+		Expect(C.Offsets).To(Equal([]int{0, 0}))
+	})
+})
@@ -0,0 +1,276 @@
+package network
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TimestampFormat selects how timestamps are written to CSV.
+type TimestampFormat int
+
+const (
+	// ISO8601 writes timestamps in RFC 3339 form with nanoseconds, e.g. 2022-03-28T08:00:00Z.
+	ISO8601 TimestampFormat = iota
+
+	// UnixNanos writes timestamps as integer nanoseconds since the Unix epoch.
+	UnixNanos
+)
+
+// WeightUnit selects the unit edge weights are written to CSV in.
+type WeightUnit int
+
+const (
+	// Nanoseconds writes weights as they are stored.
+	Nanoseconds WeightUnit = iota
+
+	// Minutes divides weights by the number of nanoseconds in a minute.
+	Minutes
+)
+
+// CSVOptions controls how a network is written to and read from CSV.
+type CSVOptions struct {
+	// StopsOnly leaves out hubs and every edge into or out of one, as GetStopGraph does.
+	StopsOnly bool
+
+	Timestamps TimestampFormat
+	Weights    WeightUnit
+}
+
+var (
+	nodesCSVHeader = []string{"id", "kind", "timestamp"}
+	edgesCSVHeader = []string{"src", "dst", "weight", "kind"}
+)
+
+// Node and edge kinds as written to CSV.
+const (
+	hubKind  = "hub"
+	stopKind = "stop"
+)
+
+// WriteNodesCSV writes one row per node of G: its ID, its kind ("hub" or "stop") and, for stops, its timestamp. Hubs come first, then stops, each in ID order.
+func (G *DeliveryNetwork) WriteNodesCSV(w io.Writer, opts CSVOptions) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(nodesCSVHeader); err != nil {
+		return err
+	}
+
+	if !opts.StopsOnly {
+		for _, id := range sortedIDs(G.Hubs) {
+			if err := out.Write([]string{strconv.FormatInt(id, 10), hubKind, ""}); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, id := range sortedIDs(G.Stops) {
+		row := []string{strconv.FormatInt(id, 10), stopKind, opts.formatTimestamp(G.Stops[id].Timestamp)}
+		if err := out.Write(row); err != nil {
+			return err
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// WriteEdgesCSV writes one row per edge of G: its source and destination IDs, its weight and its kind, as named by EdgeKind. Edges are ordered by source ID.
+func (G *DeliveryNetwork) WriteEdgesCSV(w io.Writer, opts CSVOptions) error {
+	out := csv.NewWriter(w)
+	if err := out.Write(edgesCSVHeader); err != nil {
+		return err
+	}
+
+	for _, src := range sortedIDs(G.DEdges) {
+		for _, edge := range G.DEdges[src] {
+			if opts.StopsOnly && edge.Kind() != StopLinkEdge {
+				continue
+			}
+
+			row := []string{
+				strconv.FormatInt(edge.Src.ID(), 10),
+				strconv.FormatInt(edge.Dst.ID(), 10),
+				opts.formatWeight(edge.Wgt),
+				edge.Kind().String(),
+			}
+
+			if err := out.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+
+	out.Flush()
+	return out.Error()
+}
+
+// ReadCSV rebuilds a network from the node and edge files written by WriteNodesCSV and WriteEdgesCSV with the same options. Weights in minutes are rounded to the nearest nanosecond.
+//
+// Errors in either file are reported with their line number.
+func ReadCSV(nodes, edges io.Reader, opts CSVOptions) (*DeliveryNetwork, error) {
+	G := NewDeliveryNetwork()
+
+	err := readCSVRows(nodes, nodesCSVHeader, func(row []string) error {
+		id, err := strconv.ParseInt(row[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("Node ID %q is not an integer.", row[0])
+		}
+
+		if G.Node(id) != nil {
+			return fmt.Errorf("Node %d is listed twice.", id)
+		}
+
+		switch row[1] {
+		case hubKind:
+			G.Hubs[id] = &HubNode{Val: id}
+		case stopKind:
+			ts, err := opts.parseTimestamp(row[2])
+			if err != nil {
+				return err
+			}
+			G.Stops[id] = &StopNode{Val: id, Timestamp: ts}
+		default:
+			return fmt.Errorf("Unknown node kind %q.", row[1])
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Nodes: %w", err)
+	}
+
+	seen := make(edgeSet, len(G.Hubs)+len(G.Stops))
+	err = readCSVRows(edges, edgesCSVHeader, func(row []string) error {
+		ends := make([]DeliveryNode, 2)
+		for i, field := range row[:2] {
+			id, err := strconv.ParseInt(field, 10, 64)
+			if err != nil {
+				return fmt.Errorf("Node ID %q is not an integer.", field)
+			}
+
+			node, ok := G.Node(id).(DeliveryNode)
+			if !ok {
+				return fmt.Errorf("Edge refers to unknown node %d.", id)
+			}
+			ends[i] = node
+		}
+
+		wgt, err := opts.parseWeight(row[2])
+		if err != nil {
+			return err
+		}
+
+		kind, err := ParseEdgeKind(row[3])
+		if err != nil {
+			return err
+		}
+
+		if err := checkEdgeKind(kind, ends[0], ends[1]); err != nil {
+			return err
+		}
+
+		src, dst := ends[0].ID(), ends[1].ID()
+		if err := seen.add(src, dst); err != nil {
+			return err
+		}
+
+		G.DEdges[src] = append(G.DEdges[src], &DeliveryEdge{Src: ends[0], Dst: ends[1], Wgt: wgt, Knd: kind})
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("Edges: %w", err)
+	}
+
+	return G, nil
+}
+
+// readCSVRows checks that a file starts with the expected header and passes each following row to parse, prefixing any error with the row's line number.
+func readCSVRows(r io.Reader, header []string, parse func(row []string) error) error {
+	in := csv.NewReader(r)
+
+	first, err := in.Read()
+	if err == io.EOF {
+		return fmt.Errorf("File is empty.")
+	} else if err != nil {
+		return err
+	}
+
+	if strings.Join(first, ",") != strings.Join(header, ",") {
+		return fmt.Errorf("Expected header %q, got %q.", strings.Join(header, ","), strings.Join(first, ","))
+	}
+
+	for {
+		row, err := in.Read()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+
+		if err := parse(row); err != nil {
+			line, _ := in.FieldPos(0)
+			return fmt.Errorf("Line %d: %w", line, err)
+		}
+	}
+}
+
+func (opts CSVOptions) formatTimestamp(t time.Time) string {
+	if opts.Timestamps == UnixNanos {
+		return strconv.FormatInt(t.UnixNano(), 10)
+	}
+
+	return t.Format(time.RFC3339Nano)
+}
+
+func (opts CSVOptions) parseTimestamp(field string) (time.Time, error) {
+	if opts.Timestamps == UnixNanos {
+		ns, err := strconv.ParseInt(field, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("Timestamp %q is not an integer number of nanoseconds.", field)
+		}
+		return time.Unix(0, ns).UTC(), nil
+	}
+
+	ts, err := time.Parse(time.RFC3339Nano, field)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("Timestamp %q is not in ISO 8601 form.", field)
+	}
+	return ts, nil
+}
+
+func (opts CSVOptions) formatWeight(w float64) string {
+	if opts.Weights == Minutes {
+		w /= float64(time.Minute)
+	}
+
+	return strconv.FormatFloat(w, 'f', -1, 64)
+}
+
+func (opts CSVOptions) parseWeight(field string) (float64, error) {
+	w, err := strconv.ParseFloat(field, 64)
+	if err != nil {
+		return 0.0, fmt.Errorf("Weight %q is not a number.", field)
+	}
+
+	if opts.Weights == Minutes {
+		w = math.Round(w * float64(time.Minute))
+	}
+
+	return w, nil
+}
+
+// sortedIDs returns the keys of a map indexed by node ID in ascending order.
+func sortedIDs[N any](nodes map[int64]N) []int64 {
+	ids := make([]int64, 0, len(nodes))
+	for id := range nodes {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	return ids
+}
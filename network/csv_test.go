@@ -0,0 +1,105 @@
+package network_test
+
+import (
+	"bytes"
+	"strings"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+var _ = Describe("CSV", func() {
+	var (
+		t0 time.Time
+		G  *network.DeliveryNetwork
+	)
+
+	BeforeEach(func() {
+		t0 = time.Date(2022, 3, 28, 8, 0, 0, 0, time.UTC)
+
+		G = network.NewDeliveryNetwork()
+		hub := &network.HubNode{Val: 1}
+		a := &network.StopNode{Val: 2, Timestamp: t0}
+		b := &network.StopNode{Val: 3, Timestamp: t0.Add(90 * time.Second)}
+
+		G.Hubs[1] = hub
+		G.Stops[2], G.Stops[3] = a, b
+		G.DEdges[1] = []*network.DeliveryEdge{{Src: hub, Dst: a, Wgt: float64(time.Hour)}}
+		G.DEdges[2] = []*network.DeliveryEdge{{Src: a, Dst: b, Wgt: float64(90 * time.Second)}}
+		G.DEdges[3] = []*network.DeliveryEdge{{Src: b, Dst: hub, Wgt: float64(time.Hour)}}
+	})
+
+	write := func(opts network.CSVOptions) (string, string) {
+		var nodes, edges bytes.Buffer
+		Expect(G.WriteNodesCSV(&nodes, opts)).To(Succeed())
+		Expect(G.WriteEdgesCSV(&edges, opts)).To(Succeed())
+		return nodes.String(), edges.String()
+	}
+
+	It("Writes nodes and edges with their kinds", func() {
+		nodes, edges := write(network.CSVOptions{})
+
+		Expect(nodes).To(Equal("id,kind,timestamp\n1,hub,\n2,stop,2022-03-28T08:00:00Z\n3,stop,2022-03-28T08:01:30Z\n"))
+		Expect(edges).To(Equal("src,dst,weight,kind\n1,2,3600000000000,dispatch\n2,3,90000000000,stop-link\n3,1,3600000000000,return\n"))
+	})
+
+	It("Writes Unix timestamps, weights in minutes and stop-only graphs", func() {
+		nodes, edges := write(network.CSVOptions{StopsOnly: true, Timestamps: network.UnixNanos, Weights: network.Minutes})
+
+		Expect(nodes).To(Equal("id,kind,timestamp\n2,stop,1648454400000000000\n3,stop,1648454490000000000\n"))
+		Expect(edges).To(Equal("src,dst,weight,kind\n2,3,1.5,stop-link\n"))
+	})
+
+	DescribeTable("Round-trips through the reader",
+		func(opts network.CSVOptions) {
+			nodes, edges := write(opts)
+
+			H, err := network.ReadCSV(strings.NewReader(nodes), strings.NewReader(edges), opts)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(H.Hubs).To(HaveLen(len(G.Hubs)))
+			Expect(H.Stops).To(HaveLen(len(G.Stops)))
+			for id, stop := range G.Stops {
+				Expect(H.Stops[id].Timestamp.Equal(stop.Timestamp)).To(BeTrue())
+			}
+
+			for _, edge := range G.Edges().(*network.DeliveryEdges).Payload {
+				w, ok := H.Weight(edge.Src.ID(), edge.Dst.ID())
+				Expect(ok).To(BeTrue())
+				Expect(w).To(Equal(edge.Wgt))
+			}
+		},
+		Entry("with the defaults", network.CSVOptions{}),
+		Entry("with Unix timestamps and minutes", network.CSVOptions{Timestamps: network.UnixNanos, Weights: network.Minutes}),
+	)
+
+	It("Reports bad rows with their line numbers", func() {
+		edges := "src,dst,weight,kind\n1,2,3600000000000,dispatch\n2,9,1,stop-link\n"
+		nodes, _ := write(network.CSVOptions{})
+
+		_, err := network.ReadCSV(strings.NewReader(nodes), strings.NewReader(edges), network.CSVOptions{})
+		Expect(err).To(MatchError("Edges: Line 3: Edge refers to unknown node 9."))
+
+		edges = "src,dst,weight,kind\n1,2,1,dispatch\n1,2,2,dispatch\n"
+		_, err = network.ReadCSV(strings.NewReader(nodes), strings.NewReader(edges), network.CSVOptions{})
+		Expect(err).To(MatchError("Edges: Line 3: Edge from 1 to 2 is listed twice."))
+
+		edges = "src,dst,weight,kind\n1,2,1,wait\n"
+		_, err = network.ReadCSV(strings.NewReader(nodes), strings.NewReader(edges), network.CSVOptions{})
+		Expect(err).To(MatchError(`Edges: Line 2: Edge kind "wait" does not match its nodes, which make it "dispatch".`))
+
+		edges = "src,dst,weight,kind\n2,1,1,dispatch\n"
+		_, err = network.ReadCSV(strings.NewReader(nodes), strings.NewReader(edges), network.CSVOptions{})
+		Expect(err).To(MatchError(`Edges: Line 2: Edge kind "dispatch" does not match its nodes, which make it "return".`))
+
+		nodes = "id,kind,timestamp\n1,hub,\n2,stop,yesterday\n"
+		_, err = network.ReadCSV(strings.NewReader(nodes), strings.NewReader(edges), network.CSVOptions{})
+		Expect(err).To(MatchError(`Nodes: Line 3: Timestamp "yesterday" is not in ISO 8601 form.`))
+
+		_, err = network.ReadCSV(strings.NewReader("id,type\n"), strings.NewReader(edges), network.CSVOptions{})
+		Expect(err).To(MatchError(`Nodes: Expected header "id,kind,timestamp", got "id,type".`))
+	})
+})
@@ -12,6 +12,8 @@ All node, edge and graph structures in burrow implement the corresponding interf
 	* DeliveryEdge -> gonum/graph.{Edge, WeightedEdge}
 	* DeliveryEdges -> gonum/graph.{Edges, WeightedEdges}
 	* DeliveryNetwork -> gonum/graph.{Graph, Directed, Weighted}
+	* ImplicitDeliveryNetwork -> gonum/graph.{Graph, Directed, Weighted}
+	* TimeExpandedNetwork -> gonum/graph.{Graph, Directed, Weighted}
 */
 package network
 
@@ -22,10 +24,13 @@ import (
 // DeliveryNetwork implements a two-type DAG structure for networks consisting of delivery hubs and stops for vehicles. This network implements the Graph interface from gonum/graph.
 //
 // The DeliveryNetwork struct stores nodes and edges internally using maps. This decision was made to make accessing structures by index fast and easy; the tradeoff is that it requires a little more work to marshal member structures into collections.
+//
+// Pairs indexes the stops that belong to pickup-and-delivery pairs by stop ID. Each paired stop also appears in Stops, as the StopNode embedded in its PairedStopNode.
 type DeliveryNetwork struct {
 	Stops  map[int64]*StopNode
 	Hubs   map[int64]*HubNode
 	DEdges map[int64][]*DeliveryEdge
+	Pairs  map[int64]*PairedStopNode
 }
 
 // NewDeliveryNetwork() bootstraps an empty delivery network, initializing all internal containers.
@@ -34,11 +39,24 @@ func NewDeliveryNetwork() *DeliveryNetwork {
 		Stops:  make(map[int64]*StopNode),
 		Hubs:   make(map[int64]*HubNode),
 		DEdges: make(map[int64][]*DeliveryEdge),
+		Pairs:  make(map[int64]*PairedStopNode),
 	}
 
 	return G
 }
 
+// AddPair adds both halves of the pickup-and-delivery pair starting at pickup to the network's stops and its pair index. It adds no edges.
+func (G *DeliveryNetwork) AddPair(pickup *PairedStopNode) {
+	if G.Pairs == nil {
+		G.Pairs = make(map[int64]*PairedStopNode)
+	}
+
+	for _, s := range []*PairedStopNode{pickup, pickup.Partner} {
+		G.Stops[s.ID()] = &s.StopNode
+		G.Pairs[s.ID()] = s
+	}
+}
+
 // Node(int) returns the node referenced by the given index, or nil if the index can't be found in the network.
 //
 // Note that this function does not distinguish between hub or stop nodes.
@@ -213,6 +231,21 @@ func (G *DeliveryNetwork) Edges() graph.WeightedEdges {
 	return edges
 }
 
+// EdgesOfKind returns an iterator over the network's edges of the given kind, ordered by source ID and then as stored.
+func (G *DeliveryNetwork) EdgesOfKind(kind EdgeKind) *DeliveryEdges {
+	edges := NewDeliveryEdges()
+
+	for _, src := range sortedIDs(G.DEdges) {
+		for _, edge := range G.DEdges[src] {
+			if edge.Kind() == kind {
+				edges.Payload = append(edges.Payload, edge)
+			}
+		}
+	}
+
+	return edges
+}
+
 // Returns the weight of the edge specified, as well a hash-style ok variable. If no edge exists between the specified vertices, then it returns a 0 value for the edge weight, as well as a success value of false.
 //
 // Note that Weight()'s ok return value will be set to true if uid == vid, even though the weight return will be the default option.
@@ -241,7 +274,19 @@ func (G *DeliveryNetwork) GetStopGraph() *DeliveryNetwork {
 	H := NewDeliveryNetwork()
 
 	for k, v := range G.Stops {
-		H.Stops[k] = &StopNode{Val: v.Val, Timestamp: v.Timestamp}
+		if _, paired := G.Pairs[k]; paired {
+			continue
+		}
+
+		stop := *v
+		H.Stops[k] = &stop
+	}
+
+	for _, v := range G.Pairs {
+		if v.IsPickup() {
+			pickup, _ := NewPair(v.StopNode, v.Partner.StopNode)
+			H.AddPair(pickup)
+		}
 	}
 
 	for stop, stopNode := range H.Stops {
@@ -260,6 +305,8 @@ func (G *DeliveryNetwork) GetStopGraph() *DeliveryNetwork {
 					Src: src,
 					Dst: dst,
 					Wgt: edges[i].Wgt,
+					Knd: edges[i].Knd,
+					Meta: edges[i].copyMeta(),
 				}
 
 				H.DEdges[stop] = append(H.DEdges[stop], newEdge)
@@ -269,3 +316,34 @@ func (G *DeliveryNetwork) GetStopGraph() *DeliveryNetwork {
 
 	return H
 }
+
+// GetHubGraph() extracts the subgraph formed by the hub nodes and hub-to-hub edges of G: transfers, and the waiting arcs of a time-expanded network. It is the companion to GetStopGraph(): between them, the two subgraphs hold every edge of G except those linking hubs to stops.
+// Like GetStopGraph(), this is a copying operation.
+func (G *DeliveryNetwork) GetHubGraph() *DeliveryNetwork {
+	H := NewDeliveryNetwork()
+
+	for k, v := range G.Hubs {
+		hub := *v
+		H.Hubs[k] = &hub
+	}
+
+	for hub, hubNode := range H.Hubs {
+		for _, edge := range G.DEdges[hub] {
+			if kind := edge.Kind(); kind != TransferEdge && kind != WaitEdge {
+				continue
+			}
+
+			newEdge := &DeliveryEdge{
+				Src:  hubNode,
+				Dst:  H.Hubs[edge.To().ID()],
+				Wgt:  edge.Wgt,
+				Knd:  edge.Knd,
+				Meta: edge.copyMeta(),
+			}
+
+			H.DEdges[hub] = append(H.DEdges[hub], newEdge)
+		}
+	}
+
+	return H
+}
@@ -43,7 +43,7 @@ func edgeFromPair(src, dst int) [2]int64 {
 
 func hubToStop(src, dst int) *network.DeliveryEdge {
 	return &network.DeliveryEdge{
-		Src: &network.HubNode{int64(src)},
+		Src: &network.HubNode{Val: int64(src)},
 		Dst: dummyStop(int64(dst)),
 		Wgt: 1.0,
 	}
@@ -60,7 +60,7 @@ func stopToStop(src, dst int) *network.DeliveryEdge {
 func stopToHub(src, dst int) *network.DeliveryEdge {
 	return &network.DeliveryEdge{
 		Src: dummyStop(int64(src)),
-		Dst: &network.HubNode{int64(dst)},
+		Dst: &network.HubNode{Val: int64(dst)},
 		Wgt: 1.0,
 	}
 }
@@ -170,7 +170,7 @@ var _ = Describe("DeliveryNetwork functionality", func() {
 
 						Expect(nodes).To(ContainElements(
 							matchers.MatchNode(dummyStop(3)),
-							matchers.MatchNode(&network.HubNode{1}),
+							matchers.MatchNode(&network.HubNode{Val: 1}),
 							matchers.MatchNode(dummyStop(4)),
 						))
 					})
@@ -220,7 +220,7 @@ var _ = Describe("DeliveryNetwork functionality", func() {
 
 						Expect(nodes).To(ContainElements(
 							matchers.MatchNode(dummyStop(3)),
-							matchers.MatchNode(&network.HubNode{1}),
+							matchers.MatchNode(&network.HubNode{Val: 1}),
 						))
 					})
 
@@ -412,6 +412,30 @@ var _ = Describe("DeliveryNetwork functionality", func() {
 			))
 		})
 
+		It("Carries pickup-and-delivery pairs over to the subgraph", func() {
+			t0 := time.Now()
+			G := network.NewDeliveryNetwork()
+			G.Hubs[1] = &network.HubNode{Val: 1}
+
+			pickup, dropoff := network.NewPair(
+				network.StopNode{Val: 2, Timestamp: t0},
+				network.StopNode{Val: 3, Timestamp: t0.Add(time.Hour)},
+			)
+			G.AddPair(pickup)
+			G.DEdges[2] = append(G.DEdges[2], &network.DeliveryEdge{Src: G.Stops[2], Dst: G.Stops[3], Wgt: 1.0})
+
+			Expect(G.Stops[2]).To(BeIdenticalTo(&pickup.StopNode))
+			Expect(G.Pairs[3]).To(BeIdenticalTo(dropoff))
+
+			H := G.GetStopGraph()
+			Expect(H.Stops).To(HaveLen(2))
+			Expect(H.Pairs).To(HaveLen(2))
+			Expect(H.Pairs[2].Partner).To(BeIdenticalTo(H.Pairs[3]))
+			Expect(H.Stops[3]).To(BeIdenticalTo(&H.Pairs[3].StopNode))
+			Expect(H.Stops[3]).NotTo(BeIdenticalTo(G.Stops[3]))
+			Expect(H.HasEdgeFromTo(2, 3)).To(BeTrue())
+		})
+
 		It("Returns an empty graph if there are no stop nodes", func() {
 			G := &network.DeliveryNetwork{
 				Stops: map[int64]*network.StopNode{},
@@ -431,4 +455,61 @@ var _ = Describe("DeliveryNetwork functionality", func() {
 			Expect(H.DEdges).To(BeEmpty())
 		})
 	})
+
+	Context("EdgesOfKind", func() {
+		It("Returns the edges of one kind, ordered by source ID", func() {
+			G := MakeTestDeliveryNetwork([]int64{3, 4}, []int64{1, 2}, [][2]int64{
+				edgeFromPair(2, 1), edgeFromPair(2, 4), edgeFromPair(1, 3),
+				edgeFromPair(4, 2), edgeFromPair(3, 4), edgeFromPair(3, 1),
+			})
+
+			ends := func(kind network.EdgeKind) [][2]int64 {
+				out := make([][2]int64, 0)
+				for _, edge := range collect[graph.WeightedEdge](G.EdgesOfKind(kind)) {
+					out = append(out, [2]int64{edge.From().ID(), edge.To().ID()})
+				}
+				return out
+			}
+
+			Expect(ends(network.DispatchEdge)).To(Equal([][2]int64{{1, 3}, {2, 4}}))
+			Expect(ends(network.ReturnEdge)).To(Equal([][2]int64{{3, 1}, {4, 2}}))
+			Expect(ends(network.StopLinkEdge)).To(Equal([][2]int64{{3, 4}}))
+			Expect(ends(network.TransferEdge)).To(Equal([][2]int64{{2, 1}}))
+		})
+
+		It("Returns an empty iterator if there are no edges of the kind", func() {
+			G := MakeTestDeliveryNetwork([]int64{3}, []int64{1}, [][2]int64{edgeFromPair(1, 3)})
+			Expect(G.EdgesOfKind(network.TransferEdge).Len()).To(BeZero())
+		})
+	})
+
+	Context("GetHubGraph", func() {
+		It("Returns the subgraph formed by the hubs and the transfers between them", func() {
+			G := MakeTestDeliveryNetwork([]int64{3, 4}, []int64{1, 2}, [][2]int64{
+				edgeFromPair(1, 2), edgeFromPair(2, 1),
+				edgeFromPair(1, 3), edgeFromPair(3, 4), edgeFromPair(4, 2),
+			})
+
+			H := G.GetHubGraph()
+			Expect(H.Stops).To(BeEmpty())
+			Expect(H.Hubs).To(HaveLen(2))
+			Expect(H.Hubs[1]).NotTo(BeIdenticalTo(G.Hubs[1]))
+
+			edgeIter, ok := H.Edges().(*network.DeliveryEdges)
+			Expect(ok).To(BeTrue())
+			Expect(collect[graph.WeightedEdge](edgeIter)).To(ConsistOf(
+				matchers.MatchEdge(&network.DeliveryEdge{Src: G.Hubs[1], Dst: G.Hubs[2], Wgt: 1.0}),
+				matchers.MatchEdge(&network.DeliveryEdge{Src: G.Hubs[2], Dst: G.Hubs[1], Wgt: 1.0}),
+			))
+			Expect(H.DEdges[1][0].Dst).To(BeIdenticalTo(H.Hubs[2]))
+		})
+
+		It("Returns hubs without edges if there are no transfers", func() {
+			G := MakeTestDeliveryNetwork([]int64{3}, []int64{1, 2}, [][2]int64{edgeFromPair(1, 3), edgeFromPair(3, 2)})
+
+			H := G.GetHubGraph()
+			Expect(H.Hubs).To(HaveLen(2))
+			Expect(H.DEdges).To(BeEmpty())
+		})
+	})
 })
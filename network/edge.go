@@ -1,12 +1,133 @@
 package network
 
-import "gonum.org/v1/gonum/graph"
+import (
+	"fmt"
+	"time"
+
+	"gonum.org/v1/gonum/graph"
+)
 
 // DeliveryEdge is a directional edge connecting two DeliveryNodes. It implements the standard gonum Edge interface.
+//
+// Knd is the edge's kind, as returned by Kind(). Generated and loaded edges always set it; edges that leave it zero take the kind that follows from the nodes they join.
+//
+// Meta optionally carries quantities beyond the weight, such as distance or cost, for analyses that weigh more than one objective. Generated edges between located nodes carry their distance and travel time (see TravelMeta); most other edges leave it nil.
 type DeliveryEdge struct {
-	Src DeliveryNode
-	Dst DeliveryNode
-	Wgt float64
+	Src  DeliveryNode
+	Dst  DeliveryNode
+	Wgt  float64
+	Knd  EdgeKind
+	Meta *EdgeMeta
+}
+
+// EdgeMeta holds the optional attributes of an edge. Zero fields are unknown rather than zero.
+//
+// Travel and Slack split the time between leaving an edge's source and being due at its destination into driving and waiting. Without them, a stop-to-stop edge's weight is read as all travel time.
+type EdgeMeta struct {
+	// Distance is in kilometres, as returned by Distance.
+	Distance float64
+	Travel   time.Duration
+	Slack    time.Duration
+	Cost     float64
+}
+
+// EdgeKind classifies an edge by the kinds of node it joins and, for edges between hubs, by whether it moves vehicles between depots or holds them at one.
+type EdgeKind int
+
+const (
+	// DispatchEdge runs from a hub out to a stop.
+	DispatchEdge EdgeKind = iota + 1
+	// ReturnEdge runs from a stop back to a hub.
+	ReturnEdge
+	// StopLinkEdge runs between two stops.
+	StopLinkEdge
+	// TransferEdge runs between two hubs.
+	TransferEdge
+	// WaitEdge runs from one copy of a hub in a time-expanded network to the next, and stands for time spent at the hub rather than travel. It joins two hubs like a transfer, so an edge is only ever this kind if its Knd says so.
+	WaitEdge
+)
+
+var edgeKindNames = map[EdgeKind]string{
+	DispatchEdge: "dispatch",
+	ReturnEdge:   "return",
+	StopLinkEdge: "stop-link",
+	TransferEdge: "transfer",
+	WaitEdge:     "wait",
+}
+
+// String() returns the kind's name, as written to CSV and JSON: "dispatch", "return", "stop-link", "transfer" or "wait".
+func (k EdgeKind) String() string {
+	if name, ok := edgeKindNames[k]; ok {
+		return name
+	}
+
+	return fmt.Sprintf("EdgeKind(%d)", int(k))
+}
+
+// ParseEdgeKind returns the kind with the given name, as produced by EdgeKind.String().
+func ParseEdgeKind(name string) (EdgeKind, error) {
+	for kind, kindName := range edgeKindNames {
+		if kindName == name {
+			return kind, nil
+		}
+	}
+
+	return 0, fmt.Errorf("Unknown edge kind %q.", name)
+}
+
+// reversed returns the kind of an edge running the other way: dispatch and return edges swap, and every other kind stays the same.
+func (k EdgeKind) reversed() EdgeKind {
+	switch k {
+	case DispatchEdge:
+		return ReturnEdge
+	case ReturnEdge:
+		return DispatchEdge
+	default:
+		return k
+	}
+}
+
+// checkEdgeKind returns an error unless kind fits an edge from src to dst. Waiting edges join two hubs just as transfers do, so either kind fits a hub-to-hub edge.
+func checkEdgeKind(kind EdgeKind, src, dst DeliveryNode) error {
+	derived := edgeKindOf(src, dst)
+	if kind == derived || (kind == WaitEdge && derived == TransferEdge) {
+		return nil
+	}
+
+	return fmt.Errorf("Edge kind %q does not match its nodes, which make it %q.", kind, derived)
+}
+
+// edgeSet records the edges a reader has added, by source and then destination ID, so that repeated edges are caught without scanning each source's edge list.
+type edgeSet map[int64]map[int64]struct{}
+
+// add records the edge from src to dst, and returns an error if it was already recorded.
+func (s edgeSet) add(src, dst int64) error {
+	dsts, ok := s[src]
+	if !ok {
+		dsts = make(map[int64]struct{})
+		s[src] = dsts
+	}
+
+	if _, ok := dsts[dst]; ok {
+		return fmt.Errorf("Edge from %d to %d is listed twice.", src, dst)
+	}
+
+	dsts[dst] = struct{}{}
+	return nil
+}
+
+// edgeKindOf returns the kind of an edge from src to dst. Hub-to-hub edges are taken to be transfers.
+func edgeKindOf(src, dst DeliveryNode) EdgeKind {
+	switch srcHub, dstHub := src.IsHub(), dst.IsHub(); {
+	case srcHub && dstHub:
+		return TransferEdge
+	case srcHub:
+		return DispatchEdge
+	case dstHub:
+		return ReturnEdge
+	default:
+		return StopLinkEdge
+	}
 }
 
 // From() returns the edge's source node.
@@ -20,15 +141,41 @@ func (e *DeliveryEdge) To() graph.Node {
 }
 
 // ReversedEdge() returns a DeliveryEdge struct with the same source and destination as the receiver, but reversed.
-// In this implementation, a reversed edge has the same weight as the original edge. This reflects the idea that the weights represent travel duration.
+// In this implementation, a reversed edge has the same weight as the original edge. This reflects the idea that the weights represent travel duration. A reversed dispatch edge is a return edge and vice versa, and the reversed edge gets its own copy of any metadata.
 func (e *DeliveryEdge) ReversedEdge() graph.Edge {
 	return &DeliveryEdge{
-		Src: e.Dst,
-		Dst: e.Src,
-		Wgt: e.Wgt,
+		Src:  e.Dst,
+		Dst:  e.Src,
+		Wgt:  e.Wgt,
+		Knd:  e.Knd.reversed(),
+		Meta: e.copyMeta(),
 	}
 }
 
+// copyMeta returns a copy of the edge's metadata, or nil if it has none.
+func (e *DeliveryEdge) copyMeta() *EdgeMeta {
+	if e.Meta == nil {
+		return nil
+	}
+
+	meta := *e.Meta
+	return &meta
+}
+
+// Kind() returns the edge's kind: Knd if it is set, or else the kind that follows from the nodes it joins.
+func (e *DeliveryEdge) Kind() EdgeKind {
+	if e.Knd != 0 {
+		return e.Knd
+	}
+
+	return edgeKindOf(e.Src, e.Dst)
+}
+
+// IsTransfer() returns true for hub-to-hub transfer edges, which carry parcels between depots rather than to or from stops. Waiting edges in a time-expanded network are not transfers.
+func (e *DeliveryEdge) IsTransfer() bool {
+	return e.Kind() == TransferEdge
+}
+
 // Weight() returns the weight of the given edge as a float.
 func (e *DeliveryEdge) Weight() float64 {
 	return e.Wgt
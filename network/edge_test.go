@@ -40,6 +40,65 @@ var _ = Describe("DeliveryEdge", func() {
 		})
 	})
 
+	Describe("Kind", func() {
+		It("Follows from the kinds of node the edge joins", func() {
+			Expect(hubToStop(1, 2).Kind()).To(Equal(network.DispatchEdge))
+			Expect(stopToHub(2, 1).Kind()).To(Equal(network.ReturnEdge))
+			Expect(stopToStop(2, 3).Kind()).To(Equal(network.StopLinkEdge))
+
+			transfer := &network.DeliveryEdge{Src: &network.HubNode{Val: 1}, Dst: &network.HubNode{Val: 2}}
+			Expect(transfer.Kind()).To(Equal(network.TransferEdge))
+		})
+
+		It("Prefers a stored kind, and swaps dispatch and return when reversed", func() {
+			e := hubToStop(1, 2)
+			e.Knd = network.DispatchEdge
+			Expect(e.ReversedEdge().(*network.DeliveryEdge).Knd).To(Equal(network.ReturnEdge))
+			Expect(hubToStop(1, 2).ReversedEdge().(*network.DeliveryEdge).Knd).To(BeZero())
+
+			transfer := &network.DeliveryEdge{Src: &network.HubNode{Val: 1}, Dst: &network.HubNode{Val: 2}, Knd: network.DispatchEdge}
+			Expect(transfer.Kind()).To(Equal(network.DispatchEdge))
+		})
+
+		It("Round-trips through its name", func() {
+			for _, kind := range []network.EdgeKind{network.DispatchEdge, network.ReturnEdge, network.StopLinkEdge, network.TransferEdge} {
+				parsed, err := network.ParseEdgeKind(kind.String())
+				Expect(err).NotTo(HaveOccurred())
+				Expect(parsed).To(Equal(kind))
+			}
+
+			Expect(network.StopLinkEdge.String()).To(Equal("stop-link"))
+			Expect(network.EdgeKind(0).String()).To(Equal("EdgeKind(0)"))
+
+			_, err := network.ParseEdgeKind("detour")
+			Expect(err).To(MatchError(`Unknown edge kind "detour".`))
+		})
+	})
+
+	Describe("Meta", func() {
+		It("Is copied, not shared, by ReversedEdge", func() {
+			e := stopToStop(2, 3)
+			e.Meta = &network.EdgeMeta{Distance: 4.5, Cost: 2}
+
+			back := e.ReversedEdge().(*network.DeliveryEdge)
+			Expect(back.Meta).To(Equal(e.Meta))
+			Expect(back.Meta).NotTo(BeIdenticalTo(e.Meta))
+
+			Expect(hubToStop(1, 2).ReversedEdge().(*network.DeliveryEdge).Meta).To(BeNil())
+		})
+	})
+
+	Describe("IsTransfer", func() {
+		It("Returns true only for hub-to-hub edges", func() {
+			Expect(hubToStop(1, 2).IsTransfer()).To(BeFalse())
+			Expect(stopToHub(2, 1).IsTransfer()).To(BeFalse())
+			Expect(stopToStop(2, 3).IsTransfer()).To(BeFalse())
+
+			transfer := &network.DeliveryEdge{Src: &network.HubNode{Val: 1}, Dst: &network.HubNode{Val: 2}, Wgt: 1.0}
+			Expect(transfer.IsTransfer()).To(BeTrue())
+		})
+	})
+
 	Describe("Weight", func() {
 		It("Returns the assigned edge weight", func() {
 			e := network.DeliveryEdge{
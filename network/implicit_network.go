@@ -0,0 +1,303 @@
+package network
+
+import (
+	"sort"
+	"time"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// ImplicitDeliveryNetwork is a delivery network whose edges are never stored. Every stop is linked to every hub, every hub is linked to each stop falling within its operating hours, and stop-to-stop edges run forward in time, exactly as in a generated DeliveryNetwork. Edge queries are answered from the stop timestamps, so memory use is linear in the number of nodes rather than quadratic in the number of stops.
+//
+// ImplicitDeliveryNetwork implements the Graph, Directed and Weighted interfaces from gonum/graph.
+type ImplicitDeliveryNetwork struct {
+	Hubs  []*HubNode
+	Stops []*StopNode
+
+	// Bounds, if set, restricts stop-to-stop edges to weights within the closed interval [Bounds[0], Bounds[1]].
+	Bounds *[2]time.Duration
+
+	// HubWeight is the weight assigned to every hub-to-stop and stop-to-hub edge.
+	HubWeight float64
+
+	// Speed, if positive, is the travel speed in kilometres per hour used to fill in the metadata of edges between located nodes; see TravelMeta.
+	Speed float64
+
+	hubIndex  map[int64]*HubNode
+	stopIndex map[int64]int
+}
+
+// NewImplicitDeliveryNetwork builds an implicit network over the given hubs and stops. The stop list is copied and sorted by timestamp; the caller's slice is left untouched.
+func NewImplicitDeliveryNetwork(hubs []*HubNode, stops []*StopNode, bounds *[2]time.Duration, hubWeight float64) *ImplicitDeliveryNetwork {
+	G := &ImplicitDeliveryNetwork{
+		Hubs:      make([]*HubNode, len(hubs)),
+		Stops:     make([]*StopNode, len(stops)),
+		Bounds:    bounds,
+		HubWeight: hubWeight,
+		hubIndex:  make(map[int64]*HubNode, len(hubs)),
+		stopIndex: make(map[int64]int, len(stops)),
+	}
+
+	copy(G.Hubs, hubs)
+	copy(G.Stops, stops)
+
+	sort.SliceStable(G.Stops, func(i, j int) bool {
+		return G.Stops[i].Timestamp.Before(G.Stops[j].Timestamp)
+	})
+
+	for _, hub := range G.Hubs {
+		G.hubIndex[hub.ID()] = hub
+	}
+
+	for i, stop := range G.Stops {
+		G.stopIndex[stop.ID()] = i
+	}
+
+	return G
+}
+
+// shortest and longest return the smallest and largest permissible stop-to-stop edge weights. Zero-weight edges are never permitted, since stops sharing a timestamp are not linked.
+func (G *ImplicitDeliveryNetwork) shortest() time.Duration {
+	if G.Bounds == nil || G.Bounds[0] < 1 {
+		return 1
+	}
+
+	return G.Bounds[0]
+}
+
+func (G *ImplicitDeliveryNetwork) longest() (time.Duration, bool) {
+	if G.Bounds == nil {
+		return 0, false
+	}
+
+	return G.Bounds[1], true
+}
+
+// successors returns the half-open index range [lo, hi) of stops reachable from the stop at index i.
+func (G *ImplicitDeliveryNetwork) successors(i int) (int, int) {
+	t := G.Stops[i].Timestamp
+	earliest := t.Add(G.shortest())
+
+	lo := sort.Search(len(G.Stops), func(k int) bool {
+		return !G.Stops[k].Timestamp.Before(earliest)
+	})
+
+	hi := len(G.Stops)
+	if longest, ok := G.longest(); ok {
+		latest := t.Add(longest)
+		hi = sort.Search(len(G.Stops), func(k int) bool {
+			return G.Stops[k].Timestamp.After(latest)
+		})
+	}
+
+	if hi < lo {
+		hi = lo
+	}
+
+	return lo, hi
+}
+
+// predecessors returns the half-open index range [lo, hi) of stops with an edge leading to the stop at index i.
+func (G *ImplicitDeliveryNetwork) predecessors(i int) (int, int) {
+	t := G.Stops[i].Timestamp
+	latest := t.Add(-G.shortest())
+
+	hi := sort.Search(len(G.Stops), func(k int) bool {
+		return G.Stops[k].Timestamp.After(latest)
+	})
+
+	lo := 0
+	if longest, ok := G.longest(); ok {
+		earliest := t.Add(-longest)
+		lo = sort.Search(len(G.Stops), func(k int) bool {
+			return !G.Stops[k].Timestamp.Before(earliest)
+		})
+	}
+
+	if hi < lo {
+		hi = lo
+	}
+
+	return lo, hi
+}
+
+// stopWeight returns the weight of the stop-to-stop edge running from u to v, and whether such an edge exists.
+func (G *ImplicitDeliveryNetwork) stopWeight(u, v *StopNode) (float64, bool) {
+	weight := v.Timestamp.Sub(u.Timestamp)
+	if weight < G.shortest() {
+		return 0.0, false
+	}
+
+	if longest, ok := G.longest(); ok && weight > longest {
+		return 0.0, false
+	}
+
+	return float64(weight), true
+}
+
+// Node returns the node referenced by the given ID, or nil if the ID can't be found in the network.
+func (G *ImplicitDeliveryNetwork) Node(id int64) graph.Node {
+	if hub, ok := G.hubIndex[id]; ok {
+		return hub
+	}
+
+	if i, ok := G.stopIndex[id]; ok {
+		return G.Stops[i]
+	}
+
+	return nil
+}
+
+// Nodes returns an iterator over every node in the network. Hubs come first, followed by stops in timestamp order.
+func (G *ImplicitDeliveryNetwork) Nodes() graph.Nodes {
+	dn := NewDeliveryNodes()
+	dn.Payload = make([]DeliveryNode, 0, len(G.Hubs)+len(G.Stops))
+
+	for _, hub := range G.Hubs {
+		dn.Payload = append(dn.Payload, hub)
+	}
+
+	for _, stop := range G.Stops {
+		dn.Payload = append(dn.Payload, stop)
+	}
+
+	return dn
+}
+
+// From returns an iterator over all nodes reached by id's outbound edges. Hubs reach every stop within their operating hours; stops reach every hub plus every later stop within the edge bounds.
+func (G *ImplicitDeliveryNetwork) From(id int64) graph.Nodes {
+	dn := NewDeliveryNodes()
+
+	if hub, ok := G.hubIndex[id]; ok {
+		dn.Payload = make([]DeliveryNode, 0, len(G.Stops))
+		for _, stop := range G.Stops {
+			if hub.IsOpen(stop.Timestamp) {
+				dn.Payload = append(dn.Payload, stop)
+			}
+		}
+
+		return dn
+	}
+
+	i, ok := G.stopIndex[id]
+	if !ok {
+		return dn
+	}
+
+	lo, hi := G.successors(i)
+	dn.Payload = make([]DeliveryNode, 0, len(G.Hubs)+hi-lo)
+
+	for _, hub := range G.Hubs {
+		dn.Payload = append(dn.Payload, hub)
+	}
+
+	for _, stop := range G.Stops[lo:hi] {
+		dn.Payload = append(dn.Payload, stop)
+	}
+
+	return dn
+}
+
+// To returns an iterator over all nodes with a direct hop to the node specified by id.
+func (G *ImplicitDeliveryNetwork) To(id int64) graph.Nodes {
+	dn := NewDeliveryNodes()
+
+	if _, ok := G.hubIndex[id]; ok {
+		dn.Payload = make([]DeliveryNode, 0, len(G.Stops))
+		for _, stop := range G.Stops {
+			dn.Payload = append(dn.Payload, stop)
+		}
+
+		return dn
+	}
+
+	i, ok := G.stopIndex[id]
+	if !ok {
+		return dn
+	}
+
+	lo, hi := G.predecessors(i)
+	dn.Payload = make([]DeliveryNode, 0, len(G.Hubs)+hi-lo)
+
+	for _, hub := range G.Hubs {
+		if hub.IsOpen(G.Stops[i].Timestamp) {
+			dn.Payload = append(dn.Payload, hub)
+		}
+	}
+
+	for _, stop := range G.Stops[lo:hi] {
+		dn.Payload = append(dn.Payload, stop)
+	}
+
+	return dn
+}
+
+// Weight returns the weight of the edge running from uid to vid, along with a hash-style ok flag. As with DeliveryNetwork, Weight reports a zero weight and true when uid == vid.
+func (G *ImplicitDeliveryNetwork) Weight(uid, vid int64) (float64, bool) {
+	if uid == vid {
+		return 0.0, true
+	}
+
+	hub, uHub := G.hubIndex[uid]
+	_, vHub := G.hubIndex[vid]
+	ui, uStop := G.stopIndex[uid]
+	vi, vStop := G.stopIndex[vid]
+
+	switch {
+	case uHub && vStop:
+		if !hub.IsOpen(G.Stops[vi].Timestamp) {
+			return 0.0, false
+		}
+		return G.HubWeight, true
+	case uStop && vHub:
+		return G.HubWeight, true
+	case uStop && vStop:
+		return G.stopWeight(G.Stops[ui], G.Stops[vi])
+	}
+
+	return 0.0, false
+}
+
+// HasEdgeFromTo returns true if an edge runs from uid to vid.
+func (G *ImplicitDeliveryNetwork) HasEdgeFromTo(uid, vid int64) bool {
+	if uid == vid {
+		return false
+	}
+
+	_, ok := G.Weight(uid, vid)
+	return ok
+}
+
+// HasEdgeBetween returns true if an edge connects the two nodes in either direction.
+func (G *ImplicitDeliveryNetwork) HasEdgeBetween(xid, yid int64) bool {
+	return G.HasEdgeFromTo(xid, yid) || G.HasEdgeFromTo(yid, xid)
+}
+
+// WeightedEdge generates the edge running from uid to vid, or returns nil if no such edge exists.
+func (G *ImplicitDeliveryNetwork) WeightedEdge(uid, vid int64) graph.WeightedEdge {
+	if !G.HasEdgeFromTo(uid, vid) {
+		return nil
+	}
+
+	weight, _ := G.Weight(uid, vid)
+
+	src, dst := G.Node(uid).(DeliveryNode), G.Node(vid).(DeliveryNode)
+
+	return &DeliveryEdge{
+		Src:  src,
+		Dst:  dst,
+		Wgt:  weight,
+		Knd:  edgeKindOf(src, dst),
+		Meta: TravelMeta(src, dst, G.Speed),
+	}
+}
+
+// Edge generates the edge running from uid to vid, or returns nil if no such edge exists.
+func (G *ImplicitDeliveryNetwork) Edge(uid, vid int64) graph.Edge {
+	edge := G.WeightedEdge(uid, vid)
+	if edge == nil {
+		return nil
+	}
+
+	return edge
+}
@@ -0,0 +1,193 @@
+package network_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gonum.org/v1/gonum/graph"
+
+	"github.com/bdshroyer/burrow/matchers"
+	"github.com/bdshroyer/burrow/network"
+)
+
+var _ = Describe("ImplicitDeliveryNetwork", func() {
+	var (
+		t0    time.Time
+		hubs  []*network.HubNode
+		stops []*network.StopNode
+		G     *network.ImplicitDeliveryNetwork
+	)
+
+	BeforeEach(func() {
+		t0 = time.Date(2022, 3, 29, 8, 0, 0, 0, time.UTC)
+
+		hubs = []*network.HubNode{{Val: 1}, {Val: 2}}
+
+		// Deliberately out of order; stops 6 and 7 share a timestamp.
+		stops = []*network.StopNode{
+			{Val: 5, Timestamp: t0.Add(3 * time.Hour)},
+			{Val: 3, Timestamp: t0},
+			{Val: 4, Timestamp: t0.Add(1 * time.Hour)},
+			{Val: 6, Timestamp: t0.Add(4 * time.Hour)},
+			{Val: 7, Timestamp: t0.Add(4 * time.Hour)},
+		}
+
+		G = network.NewImplicitDeliveryNetwork(hubs, stops, nil, 10.0)
+	})
+
+	It("Implements the required Directed and Weighted interfaces", func() {
+		var _ graph.Directed = (*network.ImplicitDeliveryNetwork)(nil)
+		var _ graph.Weighted = (*network.ImplicitDeliveryNetwork)(nil)
+	})
+
+	Describe("NewImplicitDeliveryNetwork", func() {
+		It("Sorts its own copy of the stop list without reordering the caller's", func() {
+			Expect(stops[0].ID()).To(BeEquivalentTo(5))
+
+			for i := 1; i < len(G.Stops); i++ {
+				Expect(G.Stops[i].Timestamp).To(BeTemporally(">=", G.Stops[i-1].Timestamp))
+			}
+		})
+	})
+
+	Describe("Node", func() {
+		It("Returns hubs and stops by ID", func() {
+			Expect(G.Node(1)).To(matchers.MatchNode(&network.HubNode{Val: 1}))
+			Expect(G.Node(4)).To(matchers.MatchNode(&network.StopNode{Val: 4}))
+		})
+
+		It("Returns nil for unknown IDs", func() {
+			Expect(G.Node(42)).To(BeNil())
+		})
+	})
+
+	Describe("Nodes", func() {
+		It("Returns hubs first, then stops in timestamp order", func() {
+			nodes := collect[graph.Node](G.Nodes().(*network.DeliveryNodes))
+			Expect(nodes).To(HaveLen(7))
+			Expect(nodes[0].ID()).To(BeEquivalentTo(1))
+			Expect(nodes[1].ID()).To(BeEquivalentTo(2))
+			Expect(nodes[2].ID()).To(BeEquivalentTo(3))
+		})
+	})
+
+	Describe("From", func() {
+		It("Links hubs to every stop", func() {
+			Expect(G.From(1).Len()).To(Equal(5))
+		})
+
+		It("Links stops to every hub and every strictly later stop", func() {
+			nodes := collect[graph.Node](G.From(4).(*network.DeliveryNodes))
+			Expect(nodes).To(ConsistOf(
+				matchers.MatchNode(&network.HubNode{Val: 1}),
+				matchers.MatchNode(&network.HubNode{Val: 2}),
+				matchers.MatchNode(&network.StopNode{Val: 5}),
+				matchers.MatchNode(&network.StopNode{Val: 6}),
+				matchers.MatchNode(&network.StopNode{Val: 7}),
+			))
+		})
+
+		It("Does not link stops that share a timestamp", func() {
+			nodes := collect[graph.Node](G.From(6).(*network.DeliveryNodes))
+			Expect(nodes).To(HaveLen(2))
+			Expect(G.HasEdgeBetween(6, 7)).To(BeFalse())
+		})
+
+		It("Returns an empty collection for unknown IDs", func() {
+			Expect(G.From(42).Len()).To(BeZero())
+		})
+	})
+
+	Describe("To", func() {
+		It("Returns every hub and every strictly earlier stop", func() {
+			nodes := collect[graph.Node](G.To(5).(*network.DeliveryNodes))
+			Expect(nodes).To(ConsistOf(
+				matchers.MatchNode(&network.HubNode{Val: 1}),
+				matchers.MatchNode(&network.HubNode{Val: 2}),
+				matchers.MatchNode(&network.StopNode{Val: 3}),
+				matchers.MatchNode(&network.StopNode{Val: 4}),
+			))
+		})
+
+		It("Returns every stop for a hub", func() {
+			Expect(G.To(2).Len()).To(Equal(5))
+		})
+	})
+
+	Describe("Weight", func() {
+		It("Uses the hub weight for hub edges", func() {
+			w, ok := G.Weight(1, 3)
+			Expect(ok).To(BeTrue())
+			Expect(w).To(BeEquivalentTo(10.0))
+
+			w, ok = G.Weight(3, 2)
+			Expect(ok).To(BeTrue())
+			Expect(w).To(BeEquivalentTo(10.0))
+		})
+
+		It("Uses the timestamp difference for stop edges", func() {
+			w, ok := G.Weight(3, 5)
+			Expect(ok).To(BeTrue())
+			Expect(w).To(BeEquivalentTo(float64(3 * time.Hour)))
+		})
+
+		It("Reports missing edges", func() {
+			_, ok := G.Weight(5, 3)
+			Expect(ok).To(BeFalse())
+
+			_, ok = G.Weight(1, 2)
+			Expect(ok).To(BeFalse())
+		})
+
+		It("Returns with 0 weight and true if the source and dest are the same", func() {
+			w, ok := G.Weight(4, 4)
+			Expect(ok).To(BeTrue())
+			Expect(w).To(BeZero())
+		})
+	})
+
+	Describe("WeightedEdge", func() {
+		It("Generates the requested edge", func() {
+			e := G.WeightedEdge(3, 4)
+			Expect(e).To(matchers.MatchEdge(&network.DeliveryEdge{
+				Src: &network.StopNode{Val: 3},
+				Dst: &network.StopNode{Val: 4},
+				Wgt: float64(1 * time.Hour),
+			}))
+		})
+
+		It("Returns nil when the edge doesn't exist", func() {
+			Expect(G.WeightedEdge(4, 3)).To(BeNil())
+			Expect(G.Edge(4, 3)).To(BeNil())
+			Expect(G.Edge(4, 4)).To(BeNil())
+		})
+	})
+
+	When("Given edge bounds", func() {
+		BeforeEach(func() {
+			G = network.NewImplicitDeliveryNetwork(hubs, stops, &[2]time.Duration{90 * time.Minute, 3 * time.Hour}, 10.0)
+		})
+
+		It("Only generates stop edges within the bounds", func() {
+			Expect(G.HasEdgeFromTo(3, 4)).To(BeFalse()) // 1h, too short
+			Expect(G.HasEdgeFromTo(3, 5)).To(BeTrue())  // 3h, on the upper bound
+			Expect(G.HasEdgeFromTo(3, 6)).To(BeFalse()) // 4h, too long
+			Expect(G.HasEdgeFromTo(4, 6)).To(BeTrue())  // 3h
+		})
+
+		It("Applies the bounds to From and To", func() {
+			Expect(collect[graph.Node](G.From(3).(*network.DeliveryNodes))).To(ConsistOf(
+				matchers.MatchNode(&network.HubNode{Val: 1}),
+				matchers.MatchNode(&network.HubNode{Val: 2}),
+				matchers.MatchNode(&network.StopNode{Val: 5}),
+			))
+
+			Expect(collect[graph.Node](G.To(6).(*network.DeliveryNodes))).To(ConsistOf(
+				matchers.MatchNode(&network.HubNode{Val: 1}),
+				matchers.MatchNode(&network.HubNode{Val: 2}),
+				matchers.MatchNode(&network.StopNode{Val: 4}),
+			))
+		})
+	})
+})
@@ -0,0 +1,260 @@
+package network
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// nodeLinkGraph is the top level of the NetworkX node-link format, as read by networkx.node_link_graph and d3's force layouts.
+type nodeLinkGraph struct {
+	Directed   *bool          `json:"directed,omitempty"`
+	Multigraph bool           `json:"multigraph"`
+	Graph      map[string]any `json:"graph"`
+	Nodes      []nodeLinkNode `json:"nodes"`
+	Links      []nodeLinkEdge `json:"links"`
+}
+
+// nodeLinkNode holds a node's ID, its kind ("hub" or "stop") and whichever of its other fields are set. Durations and weights are in nanoseconds.
+type nodeLinkNode struct {
+	ID   int64  `json:"id"`
+	Kind string `json:"kind"`
+
+	Timestamp *time.Time `json:"timestamp,omitempty"`
+	Day       int        `json:"day,omitempty"`
+	Demand    float64    `json:"demand,omitempty"`
+	Partner   *int64     `json:"partner,omitempty"`
+	Pickup    bool       `json:"pickup,omitempty"`
+
+	Lat *float64 `json:"lat,omitempty"`
+	Lon *float64 `json:"lon,omitempty"`
+
+	Fleet    uint           `json:"fleet,omitempty"`
+	Capacity uint           `json:"capacity,omitempty"`
+	Open     *time.Duration `json:"open,omitempty"`
+	Close    *time.Duration `json:"close,omitempty"`
+}
+
+// nodeLinkEdge holds an edge's endpoints, weight and kind, as in WriteEdgesCSV, and whichever of its metadata fields are set. Travel and slack times are in nanoseconds.
+type nodeLinkEdge struct {
+	Source int64   `json:"source"`
+	Target int64   `json:"target"`
+	Weight float64 `json:"weight"`
+	Kind   string  `json:"kind,omitempty"`
+
+	Distance float64       `json:"distance,omitempty"`
+	Travel   time.Duration `json:"travel,omitempty"`
+	Slack    time.Duration `json:"slack,omitempty"`
+	Cost     float64       `json:"cost,omitempty"`
+}
+
+// MarshalJSON encodes the network in the node-link format used by NetworkX and d3. Every node has an id and a kind ("hub" or "stop"); stops carry their timestamp in RFC 3339 form, and every link its weight in nanoseconds and its kind as in WriteEdgesCSV. Other node fields, and any edge metadata, are written only when set.
+//
+// Nodes are listed hubs first, then stops, each in ID order; links are ordered by source ID.
+func (G *DeliveryNetwork) MarshalJSON() ([]byte, error) {
+	directed := true
+	out := nodeLinkGraph{
+		Directed: &directed,
+		Graph:    map[string]any{},
+		Nodes:    make([]nodeLinkNode, 0, len(G.Hubs)+len(G.Stops)),
+		Links:    make([]nodeLinkEdge, 0),
+	}
+
+	for _, id := range sortedIDs(G.Hubs) {
+		hub := G.Hubs[id]
+		node := nodeLinkNode{ID: id, Kind: hubKind, Fleet: hub.Fleet, Capacity: hub.Capacity}
+		node.setLocation(hub.Loc)
+
+		if hub.Hours != nil {
+			node.Open, node.Close = &hub.Hours.Open, &hub.Hours.Close
+		}
+
+		out.Nodes = append(out.Nodes, node)
+	}
+
+	for _, id := range sortedIDs(G.Stops) {
+		stop := G.Stops[id]
+		node := nodeLinkNode{ID: id, Kind: stopKind, Timestamp: &stop.Timestamp, Day: stop.Day, Demand: stop.Demand}
+		node.setLocation(stop.Loc)
+
+		if pair, ok := G.Pairs[id]; ok {
+			partner := pair.Partner.ID()
+			node.Partner, node.Pickup = &partner, pair.Pickup
+		}
+
+		out.Nodes = append(out.Nodes, node)
+	}
+
+	for _, src := range sortedIDs(G.DEdges) {
+		for _, edge := range G.DEdges[src] {
+			link := nodeLinkEdge{
+				Source: edge.Src.ID(),
+				Target: edge.Dst.ID(),
+				Weight: edge.Wgt,
+				Kind:   edge.Kind().String(),
+			}
+			if meta := edge.Meta; meta != nil {
+				link.Distance, link.Travel, link.Slack, link.Cost = meta.Distance, meta.Travel, meta.Slack, meta.Cost
+			}
+
+			out.Links = append(out.Links, link)
+		}
+	}
+
+	return json.Marshal(out)
+}
+
+// UnmarshalJSON replaces the network with one decoded from node-link JSON, building a HubNode or StopNode for each node according to its kind. Pickup-and-delivery pairs are rebuilt from the stops' partner fields.
+//
+// Link kinds are optional, so graphs laid out or edited in other tools can be read back, but a kind that is given must match the nodes the link joins. Undirected graphs and multigraphs are rejected.
+func (G *DeliveryNetwork) UnmarshalJSON(data []byte) error {
+	var in nodeLinkGraph
+	if err := json.Unmarshal(data, &in); err != nil {
+		return err
+	}
+
+	if in.Directed != nil && !*in.Directed {
+		return fmt.Errorf("Delivery networks are directed, but the graph is not.")
+	}
+	if in.Multigraph {
+		return fmt.Errorf("Delivery networks cannot have parallel edges, but the graph is a multigraph.")
+	}
+
+	H := NewDeliveryNetwork()
+	paired := make(map[int64]nodeLinkNode)
+
+	for i, node := range in.Nodes {
+		if err := H.addNodeLinkNode(node); err != nil {
+			return fmt.Errorf("Node %d: %w", i, err)
+		}
+
+		if node.Partner != nil {
+			paired[node.ID] = node
+		}
+	}
+
+	if err := H.addNodeLinkPairs(paired); err != nil {
+		return err
+	}
+
+	seen := make(edgeSet, len(in.Nodes))
+	for i, link := range in.Links {
+		if err := H.addNodeLinkEdge(link, seen); err != nil {
+			return fmt.Errorf("Link %d: %w", i, err)
+		}
+	}
+
+	*G = *H
+	return nil
+}
+
+// setLocation copies loc, if there is one, into the node's lat and lon.
+func (n *nodeLinkNode) setLocation(loc *Location) {
+	if loc != nil {
+		n.Lat, n.Lon = &loc.Lat, &loc.Lon
+	}
+}
+
+// location returns the node's position, or nil if it has none.
+func (n nodeLinkNode) location() (*Location, error) {
+	switch {
+	case n.Lat == nil && n.Lon == nil:
+		return nil, nil
+	case n.Lat == nil || n.Lon == nil:
+		return nil, fmt.Errorf("Node %d has only one of latitude and longitude.", n.ID)
+	}
+
+	return &Location{Lat: *n.Lat, Lon: *n.Lon}, nil
+}
+
+func (G *DeliveryNetwork) addNodeLinkNode(node nodeLinkNode) error {
+	if G.Node(node.ID) != nil {
+		return fmt.Errorf("Node %d is listed twice.", node.ID)
+	}
+
+	loc, err := node.location()
+	if err != nil {
+		return err
+	}
+
+	switch node.Kind {
+	case hubKind:
+		hub := &HubNode{Val: node.ID, Loc: loc, Fleet: node.Fleet, Capacity: node.Capacity}
+		if node.Open != nil || node.Close != nil {
+			if node.Open == nil || node.Close == nil {
+				return fmt.Errorf("Hub %d has only one of its opening and closing times.", node.ID)
+			}
+			hub.Hours = &OperatingHours{Open: *node.Open, Close: *node.Close}
+		}
+		G.Hubs[node.ID] = hub
+
+	case stopKind:
+		if node.Timestamp == nil {
+			return fmt.Errorf("Stop %d has no timestamp.", node.ID)
+		}
+		G.Stops[node.ID] = &StopNode{Val: node.ID, Timestamp: *node.Timestamp, Day: node.Day, Loc: loc, Demand: node.Demand}
+
+	default:
+		return fmt.Errorf("Unknown node kind %q.", node.Kind)
+	}
+
+	return nil
+}
+
+// addNodeLinkPairs pairs up the stops that name a partner, checking that each pickup and its dropoff name each other. Pairs are added in pickup ID order.
+func (G *DeliveryNetwork) addNodeLinkPairs(paired map[int64]nodeLinkNode) error {
+	for _, id := range sortedIDs(paired) {
+		node := paired[id]
+		if node.Kind != stopKind {
+			return fmt.Errorf("Hub %d cannot be part of a pickup-and-delivery pair.", id)
+		}
+
+		partner, ok := paired[*node.Partner]
+		if !ok || *partner.Partner != id || partner.Pickup == node.Pickup {
+			return fmt.Errorf("Stop %d names %d as its partner, but they are not a pickup and dropoff that name each other.", id, *node.Partner)
+		}
+
+		if node.Pickup {
+			p, _ := NewPair(*G.Stops[id], *G.Stops[partner.ID])
+			G.AddPair(p)
+		}
+	}
+
+	return nil
+}
+
+// addNodeLinkEdge adds a link to G, recording it in seen so that a link listed twice is rejected.
+func (G *DeliveryNetwork) addNodeLinkEdge(link nodeLinkEdge, seen edgeSet) error {
+	ends := make([]DeliveryNode, 2)
+	for i, id := range []int64{link.Source, link.Target} {
+		node, ok := G.Node(id).(DeliveryNode)
+		if !ok {
+			return fmt.Errorf("Edge refers to unknown node %d.", id)
+		}
+		ends[i] = node
+	}
+
+	if err := seen.add(link.Source, link.Target); err != nil {
+		return err
+	}
+
+	kind := edgeKindOf(ends[0], ends[1])
+	if link.Kind != "" {
+		var err error
+		if kind, err = ParseEdgeKind(link.Kind); err != nil {
+			return err
+		}
+
+		if err := checkEdgeKind(kind, ends[0], ends[1]); err != nil {
+			return err
+		}
+	}
+
+	edge := &DeliveryEdge{Src: ends[0], Dst: ends[1], Wgt: link.Weight, Knd: kind}
+	if meta := (EdgeMeta{Distance: link.Distance, Travel: link.Travel, Slack: link.Slack, Cost: link.Cost}); meta != (EdgeMeta{}) {
+		edge.Meta = &meta
+	}
+
+	G.DEdges[link.Source] = append(G.DEdges[link.Source], edge)
+	return nil
+}
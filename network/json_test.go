@@ -0,0 +1,160 @@
+package network_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+var _ = Describe("Node-link JSON", func() {
+	var (
+		t0 time.Time
+		G  *network.DeliveryNetwork
+	)
+
+	BeforeEach(func() {
+		t0 = time.Date(2022, 3, 28, 8, 0, 0, 0, time.UTC)
+
+		G = network.NewDeliveryNetwork()
+		hub := &network.HubNode{Val: 1}
+		a := &network.StopNode{Val: 2, Timestamp: t0}
+		b := &network.StopNode{Val: 3, Timestamp: t0.Add(90 * time.Second)}
+
+		G.Hubs[1] = hub
+		G.Stops[2], G.Stops[3] = a, b
+		G.DEdges[1] = []*network.DeliveryEdge{{Src: hub, Dst: a, Wgt: float64(time.Hour)}}
+		G.DEdges[2] = []*network.DeliveryEdge{{Src: a, Dst: b, Wgt: float64(90 * time.Second)}}
+		G.DEdges[3] = []*network.DeliveryEdge{{Src: b, Dst: hub, Wgt: float64(time.Hour)}}
+	})
+
+	It("Writes the node-link schema with kinds, timestamps and weights", func() {
+		data, err := json.Marshal(G)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(data).To(MatchJSON(`{
+			"directed": true,
+			"multigraph": false,
+			"graph": {},
+			"nodes": [
+				{"id": 1, "kind": "hub"},
+				{"id": 2, "kind": "stop", "timestamp": "2022-03-28T08:00:00Z"},
+				{"id": 3, "kind": "stop", "timestamp": "2022-03-28T08:01:30Z"}
+			],
+			"links": [
+				{"source": 1, "target": 2, "weight": 3600000000000, "kind": "dispatch"},
+				{"source": 2, "target": 3, "weight": 90000000000, "kind": "stop-link"},
+				{"source": 3, "target": 1, "weight": 3600000000000, "kind": "return"}
+			]
+		}`))
+	})
+
+	It("Round-trips hubs, stops, pairs and edges", func() {
+		G.Hubs[1].Loc = &network.Location{Lat: 40.7, Lon: -74.0}
+		G.Hubs[1].Hours = &network.OperatingHours{Open: 6 * time.Hour, Close: 22 * time.Hour}
+		G.Hubs[1].Fleet = 4
+		G.Stops[3].Demand = 2.5
+		G.Stops[3].Day = 1
+
+		p, d := network.NewPair(network.StopNode{Val: 4, Timestamp: t0}, network.StopNode{Val: 5, Timestamp: t0.Add(time.Hour)})
+		G.AddPair(p)
+		G.DEdges[4] = []*network.DeliveryEdge{{Src: &p.StopNode, Dst: &d.StopNode, Wgt: float64(time.Hour)}}
+
+		data, err := json.Marshal(G)
+		Expect(err).NotTo(HaveOccurred())
+
+		H := network.NewDeliveryNetwork()
+		Expect(json.Unmarshal(data, H)).To(Succeed())
+
+		Expect(H.Hubs).To(Equal(G.Hubs))
+		Expect(H.Stops).To(HaveLen(len(G.Stops)))
+		for id, stop := range G.Stops {
+			Expect(H.Stops[id].Timestamp.Equal(stop.Timestamp)).To(BeTrue())
+			Expect(H.Stops[id].Demand).To(Equal(stop.Demand))
+			Expect(H.Stops[id].Day).To(Equal(stop.Day))
+		}
+
+		Expect(H.Pairs).To(HaveLen(2))
+		Expect(H.Pairs[4].IsPickup()).To(BeTrue())
+		Expect(H.Pairs[4].Partner).To(BeIdenticalTo(H.Pairs[5]))
+		Expect(H.Stops[4]).To(BeIdenticalTo(&H.Pairs[4].StopNode))
+
+		for _, edge := range G.Edges().(*network.DeliveryEdges).Payload {
+			w, ok := H.Weight(edge.Src.ID(), edge.Dst.ID())
+			Expect(ok).To(BeTrue())
+			Expect(w).To(Equal(edge.Wgt))
+		}
+
+		_, ok := H.Node(1).(*network.HubNode)
+		Expect(ok).To(BeTrue())
+		_, ok = H.Node(2).(*network.StopNode)
+		Expect(ok).To(BeTrue())
+	})
+
+	It("Round-trips edge metadata, writing only the fields that are set", func() {
+		G.DEdges[2][0].Meta = &network.EdgeMeta{Distance: 1.2, Travel: time.Minute, Slack: 30 * time.Second}
+
+		data, err := json.Marshal(G)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(data)).To(ContainSubstring(`"kind":"stop-link","distance":1.2,"travel":60000000000,"slack":30000000000}`))
+		Expect(string(data)).NotTo(ContainSubstring(`"cost"`))
+
+		H := network.NewDeliveryNetwork()
+		Expect(json.Unmarshal(data, H)).To(Succeed())
+		Expect(H.DEdges[2][0].Meta).To(Equal(G.DEdges[2][0].Meta))
+		Expect(H.DEdges[1][0].Meta).To(BeNil())
+	})
+
+	It("Reads graphs without link kinds or a directed flag", func() {
+		data := `{"nodes": [{"id": 1, "kind": "hub"}, {"id": 2, "kind": "stop", "timestamp": "2022-03-28T08:00:00Z"}],
+			"links": [{"source": 1, "target": 2, "weight": 5}]}`
+
+		H := network.NewDeliveryNetwork()
+		Expect(json.Unmarshal([]byte(data), H)).To(Succeed())
+		Expect(H.HasEdgeFromTo(1, 2)).To(BeTrue())
+	})
+
+	It("Keeps waiting arcs apart from transfers", func() {
+		data := `{"nodes": [{"id": 1, "kind": "hub"}, {"id": 2, "kind": "hub"}],
+			"links": [{"source": 1, "target": 2, "weight": 0, "kind": "wait"}, {"source": 2, "target": 1, "weight": 5}]}`
+
+		H := network.NewDeliveryNetwork()
+		Expect(json.Unmarshal([]byte(data), H)).To(Succeed())
+		Expect(H.DEdges[1][0].Kind()).To(Equal(network.WaitEdge))
+		Expect(H.DEdges[2][0].Kind()).To(Equal(network.TransferEdge))
+
+		out, err := json.Marshal(H)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(string(out)).To(ContainSubstring(`"kind":"wait"`))
+	})
+
+	DescribeTable("Rejects malformed graphs",
+		func(data, message string) {
+			H := network.NewDeliveryNetwork()
+			Expect(json.Unmarshal([]byte(data), H)).To(MatchError(message))
+		},
+		Entry("undirected", `{"directed": false, "nodes": [], "links": []}`,
+			"Delivery networks are directed, but the graph is not."),
+		Entry("unknown kind", `{"nodes": [{"id": 1, "kind": "depot"}]}`,
+			`Node 0: Unknown node kind "depot".`),
+		Entry("stop without a timestamp", `{"nodes": [{"id": 1, "kind": "hub"}, {"id": 2, "kind": "stop"}]}`,
+			"Node 1: Stop 2 has no timestamp."),
+		Entry("repeated node", `{"nodes": [{"id": 1, "kind": "hub"}, {"id": 1, "kind": "hub"}]}`,
+			"Node 1: Node 1 is listed twice."),
+		Entry("one-sided pair", `{"nodes": [
+				{"id": 2, "kind": "stop", "timestamp": "2022-03-28T08:00:00Z", "partner": 3, "pickup": true},
+				{"id": 3, "kind": "stop", "timestamp": "2022-03-28T09:00:00Z"}]}`,
+			"Stop 2 names 3 as its partner, but they are not a pickup and dropoff that name each other."),
+		Entry("dangling link", `{"nodes": [{"id": 1, "kind": "hub"}], "links": [{"source": 1, "target": 9, "weight": 1}]}`,
+			"Link 0: Edge refers to unknown node 9."),
+		Entry("repeated link", `{"nodes": [{"id": 1, "kind": "hub"}, {"id": 2, "kind": "stop", "timestamp": "2022-03-28T08:00:00Z"}],
+				"links": [{"source": 1, "target": 2, "weight": 1}, {"source": 1, "target": 2, "weight": 2}]}`,
+			"Link 1: Edge from 1 to 2 is listed twice."),
+		Entry("mismatched link kind", `{"nodes": [{"id": 1, "kind": "hub"}, {"id": 2, "kind": "stop", "timestamp": "2022-03-28T08:00:00Z"}],
+				"links": [{"source": 2, "target": 1, "weight": 1, "kind": "dispatch"}]}`,
+			`Link 0: Edge kind "dispatch" does not match its nodes, which make it "return".`),
+	)
+})
@@ -0,0 +1,52 @@
+package network
+
+import (
+	"math"
+	"time"
+)
+
+// Mean radius of the Earth in kilometres, as used by Distance.
+const earthRadiusKm = 6371.0
+
+// Location is a point on the Earth's surface in decimal degrees.
+type Location struct {
+	Lat float64
+	Lon float64
+}
+
+// Distance returns the great-circle distance between two locations in kilometres, using the haversine formula.
+func Distance(a, b Location) float64 {
+	toRad := math.Pi / 180.0
+	dLat := (b.Lat - a.Lat) * toRad
+	dLon := (b.Lon - a.Lon) * toRad
+
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(a.Lat*toRad)*math.Cos(b.Lat*toRad)*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+// TravelMeta returns metadata for an edge from src to dst giving the distance between them and the time it takes to cover at speed, in kilometres per hour. Returns nil if either node has no location or speed isn't positive.
+func TravelMeta(src, dst DeliveryNode, speed float64) *EdgeMeta {
+	a, b := locationOf(src), locationOf(dst)
+	if a == nil || b == nil || speed <= 0 {
+		return nil
+	}
+
+	km := Distance(*a, *b)
+	return &EdgeMeta{Distance: km, Travel: time.Duration(km / speed * float64(time.Hour))}
+}
+
+// locationOf returns a node's location, or nil if it has none or isn't a hub or stop.
+func locationOf(n DeliveryNode) *Location {
+	switch node := n.(type) {
+	case *HubNode:
+		return node.Loc
+	case *StopNode:
+		return node.Loc
+	case *PairedStopNode:
+		return node.Loc
+	default:
+		return nil
+	}
+}
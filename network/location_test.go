@@ -0,0 +1,45 @@
+package network_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+var _ = Describe("Location", func() {
+	Describe("Distance", func() {
+		It("Returns the great-circle distance in kilometres", func() {
+			london := network.Location{Lat: 51.5074, Lon: -0.1278}
+			paris := network.Location{Lat: 48.8566, Lon: 2.3522}
+
+			Expect(network.Distance(london, paris)).To(BeNumerically("~", 343.5, 1.0))
+			Expect(network.Distance(paris, london)).To(BeNumerically("~", network.Distance(london, paris), 1e-9))
+		})
+
+		It("Returns zero for identical locations", func() {
+			loc := network.Location{Lat: 40.0, Lon: -75.0}
+			Expect(network.Distance(loc, loc)).To(BeZero())
+		})
+	})
+
+	Describe("TravelMeta", func() {
+		It("Gives the distance between located nodes and the time to cover it", func() {
+			london := &network.HubNode{Val: 1, Loc: &network.Location{Lat: 51.5074, Lon: -0.1278}}
+			paris := &network.StopNode{Val: 2, Loc: &network.Location{Lat: 48.8566, Lon: 2.3522}}
+
+			meta := network.TravelMeta(london, paris, 100)
+			Expect(meta.Distance).To(BeNumerically("~", 343.5, 1.0))
+			Expect(meta.Travel).To(BeNumerically("~", 206*time.Minute, time.Minute))
+		})
+
+		It("Returns nil without both locations or a speed", func() {
+			located := &network.HubNode{Val: 1, Loc: &network.Location{Lat: 40.0, Lon: -75.0}}
+
+			Expect(network.TravelMeta(located, &network.StopNode{Val: 2}, 40)).To(BeNil())
+			Expect(network.TravelMeta(located, located, 0)).To(BeNil())
+		})
+	})
+})
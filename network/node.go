@@ -12,9 +12,34 @@ type DeliveryNode interface {
 	IsHub() bool
 }
 
-// HubNode represents a location from which vehicles are dispatched.
+// HubNode represents a location from which vehicles are dispatched. Loc is optional, and nil if the hub has no known position.
+//
+// Hours, if set, is the daily window during which the hub dispatches vehicles. Fleet is the number of vehicles based at the hub, and Capacity the most routes it can dispatch; zero means unlimited for both.
 type HubNode struct {
-	Val int64
+	Val      int64
+	Loc      *Location
+	Hours    *OperatingHours
+	Fleet    uint
+	Capacity uint
+}
+
+// OperatingHours is a daily window given as offsets from midnight UTC. A window whose Close is earlier than its Open runs past midnight, and one whose Open and Close are equal never closes.
+type OperatingHours struct {
+	Open, Close time.Duration
+}
+
+// Contains returns true if t falls within the window. Open is inclusive and Close exclusive.
+func (h OperatingHours) Contains(t time.Time) bool {
+	offset := t.Sub(t.Truncate(24 * time.Hour))
+
+	switch {
+	case h.Open == h.Close:
+		return true
+	case h.Open < h.Close:
+		return offset >= h.Open && offset < h.Close
+	default:
+		return offset >= h.Open || offset < h.Close
+	}
 }
 
 // ID() is a Node interface implementer that returns the hub node's ID.
@@ -27,10 +52,34 @@ func (n *HubNode) IsHub() bool {
 	return true
 }
 
+// IsOpen returns true if the hub can dispatch a vehicle at time t. Hubs without operating hours are always open.
+func (n *HubNode) IsOpen(t time.Time) bool {
+	return n.Hours == nil || n.Hours.Contains(t)
+}
+
+// DispatchLimit returns the most routes the hub can dispatch, which is the smaller of its fleet size and dispatch capacity. The ok flag is false if the hub is unlimited.
+func (n *HubNode) DispatchLimit() (uint, bool) {
+	switch {
+	case n.Fleet == 0 && n.Capacity == 0:
+		return 0, false
+	case n.Fleet == 0:
+		return n.Capacity, true
+	case n.Capacity == 0 || n.Fleet < n.Capacity:
+		return n.Fleet, true
+	default:
+		return n.Capacity, true
+	}
+}
+
 // StopNode represents a delivery stop made by a vehicle. It is implicitly assumed that stops cannot be hubs.
+//
+// Day is the 0-based index of the day the stop belongs to in a multi-day network; it is always 0 in single-day networks. Loc is optional, and nil if the stop has no known position. Demand is the load, such as a parcel count or weight, that a vehicle takes on at the stop. A pair's dropoff has negative demand, since it unloads what its pickup loaded.
 type StopNode struct {
 	Val       int64
 	Timestamp time.Time
+	Day       int
+	Loc       *Location
+	Demand    float64
 }
 
 // ID() is a Node interface implementer that returns the stop node's ID.
@@ -42,3 +91,31 @@ func (s *StopNode) ID() int64 {
 func (s *StopNode) IsHub() bool {
 	return false
 }
+
+// PairedStopNode is one half of a pickup-and-delivery pair: a parcel collected at the pickup must be carried, on the same vehicle, to the dropoff. The pickup always precedes its dropoff in time.
+//
+// A network stores the embedded StopNode in its Stops map and edges, so code unaware of pairs sees an ordinary stop. The pairing itself is looked up through DeliveryNetwork.Pairs.
+type PairedStopNode struct {
+	StopNode
+	Partner *PairedStopNode
+	Pickup  bool
+}
+
+// NewPair links a pickup and a dropoff into a pickup-and-delivery pair.
+func NewPair(pickup, dropoff StopNode) (*PairedStopNode, *PairedStopNode) {
+	p := &PairedStopNode{StopNode: pickup, Pickup: true}
+	d := &PairedStopNode{StopNode: dropoff, Partner: p}
+	p.Partner = d
+
+	return p, d
+}
+
+// IsPickup returns true for the pickup half of a pair.
+func (s *PairedStopNode) IsPickup() bool {
+	return s.Pickup
+}
+
+// IsDropoff returns true for the dropoff half of a pair.
+func (s *PairedStopNode) IsDropoff() bool {
+	return !s.Pickup
+}
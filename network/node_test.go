@@ -20,14 +20,77 @@ func dummyStop(id int64) *network.StopNode {
 var _ = Describe("Node", func() {
 	Context("HubNode", func() {
 		It("Implements Node interface", func() {
-			hub := &network.HubNode{4}
+			hub := &network.HubNode{Val: 4}
 			Expect(hub.ID()).To(BeEquivalentTo(4))
 		})
 
 		It("Identifies as a hub node", func() {
-			hub := &network.HubNode{4}
+			hub := &network.HubNode{Val: 4}
 			Expect(hub.IsHub()).To(BeTrue())
 		})
+
+		It("Is always open without operating hours", func() {
+			hub := &network.HubNode{Val: 4}
+			Expect(hub.IsOpen(time.Date(2022, 3, 28, 3, 0, 0, 0, time.UTC))).To(BeTrue())
+		})
+
+		It("Is only open within its operating hours", func() {
+			hub := &network.HubNode{Val: 4, Hours: &network.OperatingHours{Open: 8 * time.Hour, Close: 18 * time.Hour}}
+
+			Expect(hub.IsOpen(time.Date(2022, 3, 28, 8, 0, 0, 0, time.UTC))).To(BeTrue())
+			Expect(hub.IsOpen(time.Date(2022, 3, 28, 17, 59, 0, 0, time.UTC))).To(BeTrue())
+			Expect(hub.IsOpen(time.Date(2022, 3, 28, 18, 0, 0, 0, time.UTC))).To(BeFalse())
+			Expect(hub.IsOpen(time.Date(2022, 3, 28, 7, 0, 0, 0, time.UTC))).To(BeFalse())
+		})
+
+		It("Handles operating hours that run past midnight", func() {
+			hub := &network.HubNode{Val: 4, Hours: &network.OperatingHours{Open: 22 * time.Hour, Close: 6 * time.Hour}}
+
+			Expect(hub.IsOpen(time.Date(2022, 3, 28, 23, 0, 0, 0, time.UTC))).To(BeTrue())
+			Expect(hub.IsOpen(time.Date(2022, 3, 28, 5, 0, 0, 0, time.UTC))).To(BeTrue())
+			Expect(hub.IsOpen(time.Date(2022, 3, 28, 12, 0, 0, 0, time.UTC))).To(BeFalse())
+		})
+
+		It("Reads operating hours in UTC", func() {
+			hub := &network.HubNode{Val: 4, Hours: &network.OperatingHours{Open: 8 * time.Hour, Close: 18 * time.Hour}}
+			est := time.FixedZone("EST", -5*60*60)
+
+			Expect(hub.IsOpen(time.Date(2022, 3, 28, 7, 0, 0, 0, est))).To(BeTrue())
+		})
+
+		It("Limits dispatches to the smaller of fleet and capacity", func() {
+			limit, ok := (&network.HubNode{}).DispatchLimit()
+			Expect(ok).To(BeFalse())
+
+			limit, ok = (&network.HubNode{Fleet: 3}).DispatchLimit()
+			Expect(ok).To(BeTrue())
+			Expect(limit).To(BeEquivalentTo(3))
+
+			limit, _ = (&network.HubNode{Capacity: 5}).DispatchLimit()
+			Expect(limit).To(BeEquivalentTo(5))
+
+			limit, _ = (&network.HubNode{Fleet: 4, Capacity: 2}).DispatchLimit()
+			Expect(limit).To(BeEquivalentTo(2))
+		})
+	})
+
+	Context("PairedStopNode", func() {
+		It("Links a pickup and a dropoff as partners", func() {
+			pickup, dropoff := network.NewPair(*dummyStop(1), *dummyStop(2))
+
+			Expect(pickup.Partner).To(BeIdenticalTo(dropoff))
+			Expect(dropoff.Partner).To(BeIdenticalTo(pickup))
+			Expect(pickup.IsPickup()).To(BeTrue())
+			Expect(dropoff.IsDropoff()).To(BeTrue())
+		})
+
+		It("Implements the DeliveryNode interface as a stop", func() {
+			var node network.DeliveryNode
+			node, _ = network.NewPair(*dummyStop(1), *dummyStop(2))
+
+			Expect(node.ID()).To(BeEquivalentTo(1))
+			Expect(node.IsHub()).To(BeFalse())
+		})
 	})
 
 	Context("StopNode", func() {
@@ -0,0 +1,295 @@
+package network
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Number of bins in the stop-to-stop weight histogram produced by Stats.
+const weightHistogramBins = 10
+
+// EdgeCounts splits a network's edges by the kinds of node they connect.
+type EdgeCounts struct {
+	HubToStop  int `json:"hub_to_stop"`
+	StopToHub  int `json:"stop_to_hub"`
+	StopToStop int `json:"stop_to_stop"`
+	HubToHub   int `json:"hub_to_hub"`
+
+	// HubWait counts the waiting arcs of a time-expanded network, which join consecutive copies of the same hub. They are kept apart from the hub-to-hub transfers.
+	HubWait int `json:"hub_wait"`
+}
+
+// HistogramBin counts the values falling in the half-open interval [Lower, Upper). The last bin of a histogram is closed on both ends.
+type HistogramBin struct {
+	Lower float64 `json:"lower"`
+	Upper float64 `json:"upper"`
+	Count int     `json:"count"`
+}
+
+// NetworkStats summarises the structure of a delivery network.
+//
+// Degree distributions are indexed by degree: InDegrees[k] is the number of nodes with exactly k inbound edges. Weights and the timestamp span are in nanoseconds when encoded as JSON.
+type NetworkStats struct {
+	Hubs       int        `json:"hubs"`
+	Stops      int        `json:"stops"`
+	Edges      int        `json:"edges"`
+	EdgeCounts EdgeCounts `json:"edge_counts"`
+
+	// Density is the fraction of possible hub-to-stop, stop-to-hub and stop-to-stop edges present, where a generated network with no edge bounds is taken as complete: every hub linked to every stop in both directions, plus one edge per pair of stops. Hub-to-hub edges are left out, and measured by TransferDensity instead.
+	Density float64 `json:"density"`
+
+	// TransferDensity is the fraction of possible transfers present, with one possible each way between every pair of hubs. Waiting arcs are not counted.
+	TransferDensity float64 `json:"transfer_density"`
+
+	MeanOutDegree float64 `json:"mean_out_degree"`
+
+	// MeanStopOutDegree is the number of edges per stop, hub edges included. Hubs are few, so this tracks how edges grow with the number of stops.
+	MeanStopOutDegree float64 `json:"mean_stop_out_degree"`
+
+	InDegrees  []int `json:"in_degrees"`
+	OutDegrees []int `json:"out_degrees"`
+
+	// StopWeights is a histogram of stop-to-stop edge weights. Hub edges are left out, since generated networks give them all the same weight.
+	StopWeights []HistogramBin `json:"stop_weights"`
+
+	FirstStop time.Time     `json:"first_stop"`
+	LastStop  time.Time     `json:"last_stop"`
+	Span      time.Duration `json:"span"`
+
+	// LongestPath is the number of edges in the longest chain of stop-to-stop edges, or -1 if the stop edges contain a cycle.
+	LongestPath int `json:"longest_path"`
+}
+
+// Stats computes summary statistics for G.
+func Stats(G *DeliveryNetwork) *NetworkStats {
+	S := &NetworkStats{
+		Hubs:  len(G.Hubs),
+		Stops: len(G.Stops),
+	}
+
+	inDegree := make(map[int64]int, len(G.Hubs)+len(G.Stops))
+	weightLo, weightHi := math.Inf(1), math.Inf(-1)
+
+	for _, edges := range G.DEdges {
+		for _, edge := range edges {
+			S.Edges++
+			inDegree[edge.To().ID()]++
+
+			switch edge.Kind() {
+			case TransferEdge:
+				S.EdgeCounts.HubToHub++
+			case WaitEdge:
+				S.EdgeCounts.HubWait++
+			case DispatchEdge:
+				S.EdgeCounts.HubToStop++
+			case ReturnEdge:
+				S.EdgeCounts.StopToHub++
+			default:
+				S.EdgeCounts.StopToStop++
+				weightLo, weightHi = math.Min(weightLo, edge.Wgt), math.Max(weightHi, edge.Wgt)
+			}
+		}
+	}
+
+	nHubs, nStops := float64(S.Hubs), float64(S.Stops)
+	if possible := 2*nHubs*nStops + nStops*(nStops-1)/2; possible > 0 {
+		S.Density = float64(S.EdgeCounts.HubToStop+S.EdgeCounts.StopToHub+S.EdgeCounts.StopToStop) / possible
+	}
+
+	if possible := nHubs * (nHubs - 1); possible > 0 {
+		S.TransferDensity = float64(S.EdgeCounts.HubToHub) / possible
+	}
+
+	if nNodes := S.Hubs + S.Stops; nNodes > 0 {
+		S.MeanOutDegree = float64(S.Edges) / float64(nNodes)
+	}
+
+	if S.Stops > 0 {
+		S.MeanStopOutDegree = float64(S.Edges) / float64(S.Stops)
+	}
+
+	S.InDegrees, S.OutDegrees = []int{}, []int{}
+	nodes := G.Nodes()
+	for nodes.Next() {
+		id := nodes.Node().ID()
+		S.InDegrees = tally(S.InDegrees, inDegree[id])
+		S.OutDegrees = tally(S.OutDegrees, len(G.DEdges[id]))
+	}
+
+	// The weights are binned in a second pass over the edges rather than copied out, since large networks have tens of millions of them.
+	S.StopWeights = histogramBins(weightLo, weightHi, S.EdgeCounts.StopToStop, weightHistogramBins)
+	if len(S.StopWeights) > 1 {
+		for _, edges := range G.DEdges {
+			for _, edge := range edges {
+				if edge.Kind() == StopLinkEdge {
+					binValue(S.StopWeights, edge.Wgt)
+				}
+			}
+		}
+	}
+
+	for _, stop := range G.Stops {
+		if S.FirstStop.IsZero() || stop.Timestamp.Before(S.FirstStop) {
+			S.FirstStop = stop.Timestamp
+		}
+		if S.LastStop.IsZero() || stop.Timestamp.After(S.LastStop) {
+			S.LastStop = stop.Timestamp
+		}
+	}
+	S.Span = S.LastStop.Sub(S.FirstStop)
+
+	S.LongestPath = longestStopPath(G)
+
+	return S
+}
+
+// tally increments the count at index k of a degree distribution, growing it as needed.
+func tally(dist []int, k int) []int {
+	for len(dist) <= k {
+		dist = append(dist, 0)
+	}
+
+	dist[k]++
+	return dist
+}
+
+// histogramBins returns nBins empty equal-width bins spanning [lo, hi], ready to be filled by binValue. If the count values all equal lo and hi, a single bin already holding them is returned instead.
+func histogramBins(lo, hi float64, count, nBins int) []HistogramBin {
+	if count == 0 {
+		return []HistogramBin{}
+	}
+
+	if lo == hi {
+		return []HistogramBin{{Lower: lo, Upper: hi, Count: count}}
+	}
+
+	width := (hi - lo) / float64(nBins)
+	bins := make([]HistogramBin, nBins)
+	for i := range bins {
+		bins[i].Lower = lo + float64(i)*width
+		bins[i].Upper = lo + float64(i+1)*width
+	}
+	bins[nBins-1].Upper = hi
+
+	return bins
+}
+
+// binValue counts v in the bin of bins that holds it. The bins must be equal-width and span v.
+func binValue(bins []HistogramBin, v float64) {
+	lo, nBins := bins[0].Lower, len(bins)
+	width := (bins[nBins-1].Upper - lo) / float64(nBins)
+
+	i := int((v - lo) / width)
+	if i >= nBins {
+		i = nBins - 1
+	}
+	bins[i].Count++
+}
+
+// longestStopPath returns the number of edges in the longest path through G's stop-to-stop edges, using Kahn's algorithm to visit stops in topological order. Returns -1 if the stop edges contain a cycle.
+func longestStopPath(G *DeliveryNetwork) int {
+	inDegree := make(map[int64]int, len(G.Stops))
+	for id := range G.Stops {
+		for _, edge := range G.DEdges[id] {
+			if !edge.Dst.IsHub() {
+				inDegree[edge.Dst.ID()]++
+			}
+		}
+	}
+
+	queue := make([]int64, 0, len(G.Stops))
+	for id := range G.Stops {
+		if inDegree[id] == 0 {
+			queue = append(queue, id)
+		}
+	}
+
+	depth := make(map[int64]int, len(G.Stops))
+	longest, visited := 0, 0
+
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		visited++
+
+		if depth[id] > longest {
+			longest = depth[id]
+		}
+
+		for _, edge := range G.DEdges[id] {
+			if edge.Dst.IsHub() {
+				continue
+			}
+
+			dst := edge.Dst.ID()
+			if depth[id]+1 > depth[dst] {
+				depth[dst] = depth[id] + 1
+			}
+
+			inDegree[dst]--
+			if inDegree[dst] == 0 {
+				queue = append(queue, dst)
+			}
+		}
+	}
+
+	if visited < len(G.Stops) {
+		return -1
+	}
+
+	return longest
+}
+
+// String renders the statistics as an aligned plain-text report.
+func (S *NetworkStats) String() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 4, 2, ' ', 0)
+
+	fmt.Fprintf(w, "hubs\t%d\n", S.Hubs)
+	fmt.Fprintf(w, "stops\t%d\n", S.Stops)
+	fmt.Fprintf(w, "edges\t%d\n", S.Edges)
+	fmt.Fprintf(w, "  hub->stop\t%d\n", S.EdgeCounts.HubToStop)
+	fmt.Fprintf(w, "  stop->hub\t%d\n", S.EdgeCounts.StopToHub)
+	fmt.Fprintf(w, "  stop->stop\t%d\n", S.EdgeCounts.StopToStop)
+	fmt.Fprintf(w, "  hub->hub\t%d\n", S.EdgeCounts.HubToHub)
+	if S.EdgeCounts.HubWait > 0 {
+		fmt.Fprintf(w, "  hub wait\t%d\n", S.EdgeCounts.HubWait)
+	}
+	fmt.Fprintf(w, "density\t%.4f\n", S.Density)
+	if S.Hubs > 1 {
+		fmt.Fprintf(w, "transfer density\t%.4f\n", S.TransferDensity)
+	}
+	fmt.Fprintf(w, "mean out-degree\t%.2f\n", S.MeanOutDegree)
+	fmt.Fprintf(w, "edges per stop\t%.2f\n", S.MeanStopOutDegree)
+	fmt.Fprintf(w, "in-degrees\t%s\n", formatDistribution(S.InDegrees))
+	fmt.Fprintf(w, "out-degrees\t%s\n", formatDistribution(S.OutDegrees))
+
+	if S.Stops > 0 {
+		fmt.Fprintf(w, "timestamp span\t%s (%s to %s)\n", S.Span, S.FirstStop.Format(time.RFC3339), S.LastStop.Format(time.RFC3339))
+	}
+
+	fmt.Fprintf(w, "longest stop path\t%d\n", S.LongestPath)
+
+	fmt.Fprintf(w, "stop->stop weights\t\n")
+	for _, bin := range S.StopWeights {
+		fmt.Fprintf(w, "  [%s, %s]\t%d\n", time.Duration(bin.Lower), time.Duration(bin.Upper), bin.Count)
+	}
+
+	w.Flush()
+	return b.String()
+}
+
+// formatDistribution renders the non-zero entries of a degree distribution as degree:count pairs.
+func formatDistribution(dist []int) string {
+	parts := make([]string, 0, len(dist))
+	for k, count := range dist {
+		if count > 0 {
+			parts = append(parts, fmt.Sprintf("%d:%d", k, count))
+		}
+	}
+
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,144 @@
+package network_test
+
+import (
+	"encoding/json"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+var _ = Describe("Stats", func() {
+	var (
+		t0 time.Time
+		G  *network.DeliveryNetwork
+	)
+
+	BeforeEach(func() {
+		t0 = time.Date(2022, 3, 29, 8, 0, 0, 0, time.UTC)
+
+		G = network.NewDeliveryNetwork()
+		G.Hubs[1] = &network.HubNode{Val: 1}
+		G.Stops[2] = &network.StopNode{Val: 2, Timestamp: t0}
+		G.Stops[3] = &network.StopNode{Val: 3, Timestamp: t0.Add(1 * time.Hour)}
+		G.Stops[4] = &network.StopNode{Val: 4, Timestamp: t0.Add(3 * time.Hour)}
+
+		for _, id := range []int64{2, 3, 4} {
+			G.DEdges[1] = append(G.DEdges[1], &network.DeliveryEdge{Src: G.Hubs[1], Dst: G.Stops[id], Wgt: float64(time.Hour)})
+			G.DEdges[id] = append(G.DEdges[id], &network.DeliveryEdge{Src: G.Stops[id], Dst: G.Hubs[1], Wgt: float64(time.Hour)})
+		}
+
+		G.DEdges[2] = append(G.DEdges[2], &network.DeliveryEdge{Src: G.Stops[2], Dst: G.Stops[3], Wgt: float64(1 * time.Hour)})
+		G.DEdges[2] = append(G.DEdges[2], &network.DeliveryEdge{Src: G.Stops[2], Dst: G.Stops[4], Wgt: float64(3 * time.Hour)})
+		G.DEdges[3] = append(G.DEdges[3], &network.DeliveryEdge{Src: G.Stops[3], Dst: G.Stops[4], Wgt: float64(2 * time.Hour)})
+	})
+
+	It("Counts nodes and edges by kind", func() {
+		S := network.Stats(G)
+
+		Expect(S.Hubs).To(Equal(1))
+		Expect(S.Stops).To(Equal(3))
+		Expect(S.Edges).To(Equal(9))
+		Expect(S.EdgeCounts).To(Equal(network.EdgeCounts{HubToStop: 3, StopToHub: 3, StopToStop: 3}))
+	})
+
+	It("Computes density relative to a complete generated network", func() {
+		S := network.Stats(G)
+		Expect(S.Density).To(BeNumerically("~", 1.0))
+		Expect(S.MeanOutDegree).To(BeNumerically("~", 9.0/4.0))
+		Expect(S.MeanStopOutDegree).To(BeNumerically("~", 9.0/3.0))
+	})
+
+	It("Measures transfers apart from the other edges", func() {
+		G.Hubs[5] = &network.HubNode{Val: 5}
+		for _, id := range []int64{2, 3, 4} {
+			G.DEdges[5] = append(G.DEdges[5], &network.DeliveryEdge{Src: G.Hubs[5], Dst: G.Stops[id], Wgt: float64(time.Hour)})
+			G.DEdges[id] = append(G.DEdges[id], &network.DeliveryEdge{Src: G.Stops[id], Dst: G.Hubs[5], Wgt: float64(time.Hour)})
+		}
+		G.DEdges[1] = append(G.DEdges[1], &network.DeliveryEdge{Src: G.Hubs[1], Dst: G.Hubs[5], Wgt: float64(2 * time.Hour)})
+
+		S := network.Stats(G)
+		Expect(S.EdgeCounts.HubToHub).To(Equal(1))
+		// All 12 hub-stop edges and 3 stop-to-stop edges are present, but only one of the 2 possible transfers.
+		Expect(S.Density).To(BeNumerically("~", 1.0))
+		Expect(S.TransferDensity).To(BeNumerically("~", 0.5))
+
+		// A network without transfers has none of its possible ones, not fewer possible.
+		G.DEdges[1] = G.DEdges[1][:len(G.DEdges[1])-1]
+		S = network.Stats(G)
+		Expect(S.Density).To(BeNumerically("~", 1.0))
+		Expect(S.TransferDensity).To(BeZero())
+	})
+
+	It("Counts waiting arcs apart from transfers", func() {
+		G.Hubs[5] = &network.HubNode{Val: 5}
+		G.DEdges[1] = append(G.DEdges[1], &network.DeliveryEdge{Src: G.Hubs[1], Dst: G.Hubs[5], Knd: network.WaitEdge})
+
+		S := network.Stats(G)
+		Expect(S.EdgeCounts.HubToHub).To(BeZero())
+		Expect(S.EdgeCounts.HubWait).To(Equal(1))
+		Expect(S.String()).To(ContainSubstring("hub wait"))
+	})
+
+	It("Computes degree distributions", func() {
+		S := network.Stats(G)
+
+		// Stop 2 is reached only by the hub, stop 3 by the hub and stop 2, stop 4 by all three; the hub by every stop.
+		Expect(S.InDegrees).To(Equal([]int{0, 1, 1, 2}))
+		// Stop 4 links only back to the hub; stops 2 and 3 have 3 and 2 edges; the hub has 3.
+		Expect(S.OutDegrees).To(Equal([]int{0, 1, 1, 2}))
+	})
+
+	It("Builds a histogram of stop-to-stop weights", func() {
+		S := network.Stats(G)
+
+		total := 0
+		for _, bin := range S.StopWeights {
+			total += bin.Count
+		}
+
+		Expect(total).To(Equal(3))
+		Expect(S.StopWeights[0].Lower).To(BeEquivalentTo(float64(1 * time.Hour)))
+		Expect(S.StopWeights[len(S.StopWeights)-1].Upper).To(BeEquivalentTo(float64(3 * time.Hour)))
+	})
+
+	It("Reports the timestamp span and the longest stop path", func() {
+		S := network.Stats(G)
+
+		Expect(S.FirstStop).To(Equal(t0))
+		Expect(S.LastStop).To(Equal(t0.Add(3 * time.Hour)))
+		Expect(S.Span).To(Equal(3 * time.Hour))
+		Expect(S.LongestPath).To(Equal(2))
+	})
+
+	It("Reports a cycle among stop edges as a longest path of -1", func() {
+		G.DEdges[4] = append(G.DEdges[4], &network.DeliveryEdge{Src: G.Stops[4], Dst: G.Stops[2], Wgt: 1.0})
+		Expect(network.Stats(G).LongestPath).To(Equal(-1))
+	})
+
+	It("Handles an empty network", func() {
+		S := network.Stats(network.NewDeliveryNetwork())
+
+		Expect(S.Edges).To(BeZero())
+		Expect(S.Density).To(BeZero())
+		Expect(S.StopWeights).To(BeEmpty())
+		Expect(S.LongestPath).To(BeZero())
+	})
+
+	It("Renders as text and JSON", func() {
+		S := network.Stats(G)
+
+		Expect(S.String()).To(ContainSubstring("stop->stop"))
+		Expect(S.String()).To(ContainSubstring("longest stop path"))
+
+		raw, err := json.Marshal(S)
+		Expect(err).NotTo(HaveOccurred())
+
+		decoded := &network.NetworkStats{}
+		Expect(json.Unmarshal(raw, decoded)).To(Succeed())
+		Expect(decoded.EdgeCounts).To(Equal(S.EdgeCounts))
+		Expect(decoded.Span).To(Equal(S.Span))
+	})
+})
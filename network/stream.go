@@ -0,0 +1,632 @@
+package network
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"time"
+)
+
+// StreamVersion is the version of the binary stream format written by StreamWriter. StreamReader rejects streams of any other version.
+const StreamVersion = 1
+
+// A stream starts with streamMagic and the format version as a uvarint, followed by records. Each record is a kind byte, the length of its payload as a uvarint and the payload itself. The stream ends with a trailer record holding the node and edge counts and a CRC-32C checksum of every record before it.
+const (
+	streamMagic = "BRWS"
+
+	hubRecord  byte = 'H'
+	stopRecord byte = 'S'
+	edgeRecord byte = 'E'
+	endRecord  byte = 'Z'
+
+	// No valid record comes close to this length, so anything longer means the stream is corrupt.
+	maxRecordLen = 1 << 10
+)
+
+// Flags stored in hub and stop records.
+const (
+	hasLocation byte = 1 << iota
+	hasHours
+	isPaired
+	isPickup
+)
+
+// Flags stored in edge records.
+const (
+	hasMeta byte = 1 << iota
+)
+
+var streamTable = crc32.MakeTable(crc32.Castagnoli)
+
+// StreamWriter writes a network to a compact binary stream one node or edge at a time, so a network can be saved while it is generated or converted without holding it all in memory. Nodes must be written before any edge that refers to them.
+//
+// Timestamps are stored as Unix nanoseconds, so stops read back from a stream are in UTC.
+type StreamWriter struct {
+	w   *bufio.Writer
+	crc hash.Hash32
+	buf []byte
+
+	nodes, edges uint64
+	closed       bool
+}
+
+// NewStreamWriter writes the stream header to w and returns a writer for the records that follow. Records are buffered, so Close must be called to flush them and write the trailer.
+func NewStreamWriter(w io.Writer) (*StreamWriter, error) {
+	s := &StreamWriter{
+		w:   bufio.NewWriter(w),
+		crc: crc32.New(streamTable),
+	}
+
+	if _, err := s.w.Write(binary.AppendUvarint([]byte(streamMagic), StreamVersion)); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// WriteHub writes a hub record.
+func (s *StreamWriter) WriteHub(hub *HubNode) error {
+	flags := byte(0)
+	if hub.Loc != nil {
+		flags |= hasLocation
+	}
+	if hub.Hours != nil {
+		flags |= hasHours
+	}
+
+	b := binary.AppendVarint(s.buf[:0], hub.Val)
+	b = binary.AppendUvarint(b, uint64(hub.Fleet))
+	b = binary.AppendUvarint(b, uint64(hub.Capacity))
+	b = append(b, flags)
+	b = appendLocation(b, hub.Loc)
+
+	if hub.Hours != nil {
+		b = binary.AppendVarint(b, int64(hub.Hours.Open))
+		b = binary.AppendVarint(b, int64(hub.Hours.Close))
+	}
+
+	s.nodes++
+	return s.record(hubRecord, b)
+}
+
+// WriteStop writes a stop record for a stop that is not part of a pickup-and-delivery pair.
+func (s *StreamWriter) WriteStop(stop *StopNode) error {
+	return s.writeStop(stop, nil)
+}
+
+// WritePair writes stop records for both halves of the pickup-and-delivery pair starting at pickup.
+func (s *StreamWriter) WritePair(pickup *PairedStopNode) error {
+	if err := s.writeStop(&pickup.StopNode, pickup); err != nil {
+		return err
+	}
+
+	return s.writeStop(&pickup.Partner.StopNode, pickup.Partner)
+}
+
+func (s *StreamWriter) writeStop(stop *StopNode, pair *PairedStopNode) error {
+	flags := byte(0)
+	if stop.Loc != nil {
+		flags |= hasLocation
+	}
+	if pair != nil {
+		flags |= isPaired
+		if pair.Pickup {
+			flags |= isPickup
+		}
+	}
+
+	b := binary.AppendVarint(s.buf[:0], stop.Val)
+	b = binary.AppendVarint(b, stop.Timestamp.UnixNano())
+	b = binary.AppendVarint(b, int64(stop.Day))
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(stop.Demand))
+	b = append(b, flags)
+	b = appendLocation(b, stop.Loc)
+
+	if pair != nil {
+		b = binary.AppendVarint(b, pair.Partner.ID())
+	}
+
+	s.nodes++
+	return s.record(stopRecord, b)
+}
+
+// WriteEdge writes an edge record, including the edge's kind and any metadata.
+func (s *StreamWriter) WriteEdge(edge *DeliveryEdge) error {
+	flags := byte(0)
+	if edge.Meta != nil {
+		flags |= hasMeta
+	}
+
+	b := binary.AppendVarint(s.buf[:0], edge.Src.ID())
+	b = binary.AppendVarint(b, edge.Dst.ID())
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(edge.Wgt))
+	b = append(b, byte(edge.Kind()), flags)
+
+	if meta := edge.Meta; meta != nil {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(meta.Distance))
+		b = binary.AppendVarint(b, int64(meta.Travel))
+		b = binary.AppendVarint(b, int64(meta.Slack))
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(meta.Cost))
+	}
+
+	s.edges++
+	return s.record(edgeRecord, b)
+}
+
+// WriteNetwork writes every node of G, hubs first and then stops, each in ID order, followed by its edges in order of source ID. It doesn't close the stream.
+func (s *StreamWriter) WriteNetwork(G *DeliveryNetwork) error {
+	for _, id := range sortedIDs(G.Hubs) {
+		if err := s.WriteHub(G.Hubs[id]); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range sortedIDs(G.Stops) {
+		if err := s.writeStop(G.Stops[id], G.Pairs[id]); err != nil {
+			return err
+		}
+	}
+
+	for _, src := range sortedIDs(G.DEdges) {
+		for _, edge := range G.DEdges[src] {
+			if err := s.WriteEdge(edge); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// Close writes the trailer and flushes the stream. It doesn't close the underlying writer.
+func (s *StreamWriter) Close() error {
+	if s.closed {
+		return fmt.Errorf("Stream is already closed.")
+	}
+
+	b := binary.AppendUvarint(s.buf[:0], s.nodes)
+	b = binary.AppendUvarint(b, s.edges)
+	b = binary.LittleEndian.AppendUint32(b, s.crc.Sum32())
+
+	if err := s.frame(endRecord, b); err != nil {
+		return err
+	}
+
+	s.closed = true
+	return s.w.Flush()
+}
+
+// record writes a record and adds it to the checksum.
+func (s *StreamWriter) record(kind byte, payload []byte) error {
+	if s.closed {
+		return fmt.Errorf("Stream is already closed.")
+	}
+
+	// Keep the grown buffer for the next record.
+	s.buf = payload
+
+	if err := s.frame(kind, payload); err != nil {
+		return err
+	}
+
+	s.crc.Write([]byte{kind})
+	s.crc.Write(binary.AppendUvarint(nil, uint64(len(payload))))
+	s.crc.Write(payload)
+
+	return nil
+}
+
+func (s *StreamWriter) frame(kind byte, payload []byte) error {
+	head := binary.AppendUvarint([]byte{kind}, uint64(len(payload)))
+	if _, err := s.w.Write(head); err != nil {
+		return err
+	}
+
+	_, err := s.w.Write(payload)
+	return err
+}
+
+func appendLocation(b []byte, loc *Location) []byte {
+	if loc == nil {
+		return b
+	}
+
+	b = binary.LittleEndian.AppendUint64(b, math.Float64bits(loc.Lat))
+	return binary.LittleEndian.AppendUint64(b, math.Float64bits(loc.Lon))
+}
+
+// StreamRecord is a single node or edge read from a stream. Exactly one of Hub, Stop and Edge is set.
+//
+// Paired is true for stops in a pickup-and-delivery pair, in which case Partner is the ID of the other half and Pickup tells which half this is.
+type StreamRecord struct {
+	Hub  *HubNode
+	Stop *StopNode
+	Edge *StreamEdge
+
+	Paired  bool
+	Partner int64
+	Pickup  bool
+}
+
+// StreamEdge is an edge as stored in a stream, with its ends given by ID.
+type StreamEdge struct {
+	Src, Dst int64
+	Wgt      float64
+	Kind     EdgeKind
+	Meta     *EdgeMeta
+}
+
+// StreamReader reads the records of a stream written by StreamWriter one at a time.
+type StreamReader struct {
+	r   *bufio.Reader
+	crc hash.Hash32
+	buf []byte
+
+	// Version is the format version given in the stream's header.
+	Version uint64
+
+	nodes, edges uint64
+	done         bool
+}
+
+// NewStreamReader reads and checks the stream header from r.
+func NewStreamReader(r io.Reader) (*StreamReader, error) {
+	s := &StreamReader{
+		r:   bufio.NewReader(r),
+		crc: crc32.New(streamTable),
+	}
+
+	magic := make([]byte, len(streamMagic))
+	if _, err := io.ReadFull(s.r, magic); err != nil || string(magic) != streamMagic {
+		return nil, fmt.Errorf("Stream does not start with a burrow stream header.")
+	}
+
+	version, err := binary.ReadUvarint(s.r)
+	if err != nil {
+		return nil, fmt.Errorf("Stream does not start with a burrow stream header.")
+	}
+	if version != StreamVersion {
+		return nil, fmt.Errorf("Stream has format version %d, but only version %d can be read.", version, StreamVersion)
+	}
+
+	s.Version = version
+	return s, nil
+}
+
+// Next returns the next record in the stream, or io.EOF once the trailer has been read and checked.
+//
+// The checksum can only be checked at the end, so records from a corrupt stream may be returned before the corruption is reported. Code that must not act on bad data should collect records until Next returns io.EOF.
+func (s *StreamReader) Next() (*StreamRecord, error) {
+	if s.done {
+		return nil, io.EOF
+	}
+
+	kind, payload, err := s.readFrame()
+	if err != nil {
+		return nil, err
+	}
+
+	if kind == endRecord {
+		return nil, s.checkTrailer(payload)
+	}
+
+	s.crc.Write([]byte{kind})
+	s.crc.Write(binary.AppendUvarint(nil, uint64(len(payload))))
+	s.crc.Write(payload)
+
+	d := &payloadDecoder{b: payload}
+	rec := &StreamRecord{}
+
+	switch kind {
+	case hubRecord:
+		s.nodes++
+		rec.Hub = d.hub()
+	case stopRecord:
+		s.nodes++
+		rec.Stop = d.stop(rec)
+	case edgeRecord:
+		s.edges++
+		rec.Edge = d.edge()
+	default:
+		return nil, fmt.Errorf("Unknown record kind %q.", kind)
+	}
+
+	if d.err != nil || len(d.b) > 0 {
+		return nil, fmt.Errorf("Malformed %q record.", kind)
+	}
+
+	return rec, nil
+}
+
+// ReadNetwork reads every remaining record into a new network, rebuilding pickup-and-delivery pairs. Edges must refer to nodes that came before them.
+func (s *StreamReader) ReadNetwork() (*DeliveryNetwork, error) {
+	G := NewDeliveryNetwork()
+	partners := make(map[int64]int64)
+	seen := make(edgeSet)
+
+	for {
+		rec, err := s.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		if err := G.addStreamRecord(rec, partners, seen); err != nil {
+			return nil, fmt.Errorf("Record %d: %w", s.nodes+s.edges, err)
+		}
+	}
+
+	for _, id := range sortedIDs(G.Pairs) {
+		if G.Pairs[id].Partner == nil {
+			return nil, fmt.Errorf("Stop %d is paired with a stop that is not in the stream.", id)
+		}
+	}
+
+	return G, nil
+}
+
+// ReadCSR reads every remaining record straight into a CSR view, without building node or edge structs. Nodes are numbered in the order the stream lists them, and pairs are ignored.
+func (s *StreamReader) ReadCSR() (*CSR, error) {
+	b := newCSRBuilder(0)
+
+	for {
+		rec, err := s.Next()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case rec.Hub != nil:
+			err = b.addStreamNode(rec.Hub.ID(), true)
+		case rec.Stop != nil:
+			err = b.addStreamNode(rec.Stop.ID(), false)
+		default:
+			err = b.addEdge(rec.Edge.Src, rec.Edge.Dst, rec.Edge.Wgt)
+		}
+
+		if err != nil {
+			return nil, fmt.Errorf("Record %d: %w", s.nodes+s.edges, err)
+		}
+	}
+
+	return b.build(), nil
+}
+
+func (b *csrBuilder) addStreamNode(id int64, hub bool) error {
+	if _, ok := b.C.Index[id]; ok {
+		return fmt.Errorf("Node %d is listed twice.", id)
+	}
+
+	b.addNode(id, hub)
+	return nil
+}
+
+// addStreamRecord adds a node or edge read from a stream to G. partners records the partner each paired stop names, so that both halves of a pair can be checked against each other, and seen records the edges read so far, so that none is added twice.
+func (G *DeliveryNetwork) addStreamRecord(rec *StreamRecord, partners map[int64]int64, seen edgeSet) error {
+	switch {
+	case rec.Hub != nil:
+		if G.Node(rec.Hub.ID()) != nil {
+			return fmt.Errorf("Node %d is listed twice.", rec.Hub.ID())
+		}
+		G.Hubs[rec.Hub.ID()] = rec.Hub
+
+	case rec.Stop != nil:
+		id := rec.Stop.ID()
+		if G.Node(id) != nil {
+			return fmt.Errorf("Node %d is listed twice.", id)
+		}
+
+		if !rec.Paired {
+			G.Stops[id] = rec.Stop
+			return nil
+		}
+
+		// Paired stops are stored through their PairedStopNode from the start, so edges read later point at the same StopNode as the pair index. Each half is linked to its partner once both have been read.
+		pair := &PairedStopNode{StopNode: *rec.Stop, Pickup: rec.Pickup}
+		partners[id] = rec.Partner
+
+		if partner, ok := G.Pairs[rec.Partner]; ok {
+			if partners[rec.Partner] != id || partner.Pickup == pair.Pickup {
+				return fmt.Errorf("Stop %d names %d as its partner, but they are not a pickup and dropoff that name each other.", id, rec.Partner)
+			}
+			pair.Partner, partner.Partner = partner, pair
+		}
+
+		G.Stops[id] = &pair.StopNode
+		G.Pairs[id] = pair
+
+	default:
+		ends := make([]DeliveryNode, 2)
+		for i, id := range []int64{rec.Edge.Src, rec.Edge.Dst} {
+			node, ok := G.Node(id).(DeliveryNode)
+			if !ok {
+				return fmt.Errorf("Edge refers to unknown node %d.", id)
+			}
+			ends[i] = node
+		}
+
+		if err := checkEdgeKind(rec.Edge.Kind, ends[0], ends[1]); err != nil {
+			return err
+		}
+
+		if err := seen.add(rec.Edge.Src, rec.Edge.Dst); err != nil {
+			return err
+		}
+
+		G.DEdges[rec.Edge.Src] = append(G.DEdges[rec.Edge.Src], &DeliveryEdge{Src: ends[0], Dst: ends[1], Wgt: rec.Edge.Wgt, Knd: rec.Edge.Kind, Meta: rec.Edge.Meta})
+	}
+
+	return nil
+}
+
+// readFrame reads a record's kind and payload. The payload shares the reader's buffer, so it is only valid until the next call.
+func (s *StreamReader) readFrame() (byte, []byte, error) {
+	kind, err := s.r.ReadByte()
+	if err != nil {
+		return 0, nil, truncated(err)
+	}
+
+	n, err := binary.ReadUvarint(s.r)
+	if err != nil {
+		return 0, nil, truncated(err)
+	}
+	if n > maxRecordLen {
+		return 0, nil, fmt.Errorf("Record of %d bytes is longer than any valid record.", n)
+	}
+
+	if uint64(cap(s.buf)) < n {
+		s.buf = make([]byte, n)
+	}
+	payload := s.buf[:n]
+
+	if _, err := io.ReadFull(s.r, payload); err != nil {
+		return 0, nil, truncated(err)
+	}
+
+	return kind, payload, nil
+}
+
+// checkTrailer compares the trailer's counts and checksum with what was read.
+func (s *StreamReader) checkTrailer(payload []byte) error {
+	d := &payloadDecoder{b: payload}
+	nodes, edges := d.uvarint(), d.uvarint()
+
+	var sum uint32
+	if d.err == nil && len(d.b) == 4 {
+		sum = binary.LittleEndian.Uint32(d.b)
+	} else {
+		return fmt.Errorf("Malformed %q record.", endRecord)
+	}
+
+	if nodes != s.nodes || edges != s.edges {
+		return fmt.Errorf("Stream trailer counts %d nodes and %d edges, but the stream holds %d and %d.", nodes, edges, s.nodes, s.edges)
+	}
+
+	if got := s.crc.Sum32(); got != sum {
+		return fmt.Errorf("Stream checksum %08x does not match its contents, whose checksum is %08x.", sum, got)
+	}
+
+	s.done = true
+	return io.EOF
+}
+
+func truncated(err error) error {
+	if err == io.EOF || errors.Is(err, io.ErrUnexpectedEOF) {
+		return fmt.Errorf("Stream ends before its trailer, so it is probably truncated.")
+	}
+
+	return err
+}
+
+// payloadDecoder reads fields off the front of a record's payload. Once a read fails, err is set and every later read returns zero.
+type payloadDecoder struct {
+	b   []byte
+	err error
+}
+
+func (d *payloadDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Varint(d.b)
+	if n <= 0 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *payloadDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+
+	v, n := binary.Uvarint(d.b)
+	if n <= 0 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+
+	d.b = d.b[n:]
+	return v
+}
+
+func (d *payloadDecoder) float() float64 {
+	if d.err != nil || len(d.b) < 8 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+
+	v := math.Float64frombits(binary.LittleEndian.Uint64(d.b))
+	d.b = d.b[8:]
+	return v
+}
+
+func (d *payloadDecoder) flags() byte {
+	if d.err != nil || len(d.b) < 1 {
+		d.err = io.ErrUnexpectedEOF
+		return 0
+	}
+
+	v := d.b[0]
+	d.b = d.b[1:]
+	return v
+}
+
+func (d *payloadDecoder) location(flags byte) *Location {
+	if flags&hasLocation == 0 {
+		return nil
+	}
+
+	return &Location{Lat: d.float(), Lon: d.float()}
+}
+
+func (d *payloadDecoder) hub() *HubNode {
+	hub := &HubNode{Val: d.varint(), Fleet: uint(d.uvarint()), Capacity: uint(d.uvarint())}
+
+	flags := d.flags()
+	hub.Loc = d.location(flags)
+
+	if flags&hasHours != 0 {
+		hub.Hours = &OperatingHours{Open: time.Duration(d.varint()), Close: time.Duration(d.varint())}
+	}
+
+	return hub
+}
+
+func (d *payloadDecoder) edge() *StreamEdge {
+	edge := &StreamEdge{Src: d.varint(), Dst: d.varint(), Wgt: d.float(), Kind: EdgeKind(d.flags())}
+
+	if d.flags()&hasMeta != 0 {
+		edge.Meta = &EdgeMeta{Distance: d.float(), Travel: time.Duration(d.varint()), Slack: time.Duration(d.varint()), Cost: d.float()}
+	}
+
+	return edge
+}
+
+func (d *payloadDecoder) stop(rec *StreamRecord) *StopNode {
+	stop := &StopNode{Val: d.varint()}
+	stop.Timestamp = time.Unix(0, d.varint()).UTC()
+	stop.Day = int(d.varint())
+	stop.Demand = d.float()
+
+	flags := d.flags()
+	stop.Loc = d.location(flags)
+
+	if flags&isPaired != 0 {
+		rec.Paired, rec.Pickup, rec.Partner = true, flags&isPickup != 0, d.varint()
+	}
+
+	return stop
+}
@@ -0,0 +1,171 @@
+package network_test
+
+import (
+	"bytes"
+	"io"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+var _ = Describe("Streams", func() {
+	var (
+		t0 time.Time
+		G  *network.DeliveryNetwork
+	)
+
+	BeforeEach(func() {
+		t0 = time.Date(2022, 3, 28, 8, 0, 0, 0, time.UTC)
+
+		G = network.NewDeliveryNetwork()
+		hub := &network.HubNode{Val: 1, Fleet: 2, Loc: &network.Location{Lat: 40.7, Lon: -74.0}}
+		hub.Hours = &network.OperatingHours{Open: 6 * time.Hour, Close: 22 * time.Hour}
+		a := &network.StopNode{Val: 2, Timestamp: t0, Demand: 1.5}
+		b := &network.StopNode{Val: 3, Timestamp: t0.Add(90 * time.Second), Day: 1}
+
+		G.Hubs[1] = hub
+		G.Stops[2], G.Stops[3] = a, b
+		G.DEdges[1] = []*network.DeliveryEdge{{Src: hub, Dst: a, Wgt: float64(time.Hour)}}
+		G.DEdges[2] = []*network.DeliveryEdge{{Src: a, Dst: b, Wgt: float64(90 * time.Second)}}
+		G.DEdges[3] = []*network.DeliveryEdge{{Src: b, Dst: hub, Wgt: float64(time.Hour)}}
+
+		p, d := network.NewPair(network.StopNode{Val: 4, Timestamp: t0}, network.StopNode{Val: 5, Timestamp: t0.Add(time.Hour)})
+		G.AddPair(p)
+		G.DEdges[4] = []*network.DeliveryEdge{{Src: &p.StopNode, Dst: &d.StopNode, Wgt: float64(time.Hour)}}
+	})
+
+	write := func() []byte {
+		var buf bytes.Buffer
+		w, err := network.NewStreamWriter(&buf)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.WriteNetwork(G)).To(Succeed())
+		Expect(w.Close()).To(Succeed())
+
+		return buf.Bytes()
+	}
+
+	read := func(data []byte) (*network.DeliveryNetwork, error) {
+		r, err := network.NewStreamReader(bytes.NewReader(data))
+		Expect(err).NotTo(HaveOccurred())
+		return r.ReadNetwork()
+	}
+
+	It("Round-trips a network with every node field", func() {
+		H, err := read(write())
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(H.Hubs).To(Equal(G.Hubs))
+		Expect(H.Stops).To(Equal(G.Stops))
+
+		Expect(H.Pairs).To(HaveLen(2))
+		Expect(H.Pairs[4].Partner).To(BeIdenticalTo(H.Pairs[5]))
+		Expect(H.Stops[5]).To(BeIdenticalTo(&H.Pairs[5].StopNode))
+		Expect(H.DEdges[4][0].Dst).To(BeIdenticalTo(H.Stops[5]))
+
+		for _, edge := range G.Edges().(*network.DeliveryEdges).Payload {
+			w, ok := H.Weight(edge.Src.ID(), edge.Dst.ID())
+			Expect(ok).To(BeTrue())
+			Expect(w).To(Equal(edge.Wgt))
+		}
+	})
+
+	It("Keeps each edge's kind, so waiting arcs stay apart from transfers", func() {
+		G.Hubs[6] = &network.HubNode{Val: 6}
+		G.DEdges[1] = append(G.DEdges[1], &network.DeliveryEdge{Src: G.Hubs[1], Dst: G.Hubs[6], Knd: network.WaitEdge})
+
+		H, err := read(write())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(H.DEdges[1][1].Kind()).To(Equal(network.WaitEdge))
+		Expect(H.DEdges[1][0].Kind()).To(Equal(network.DispatchEdge))
+	})
+
+	It("Keeps edge metadata where the edge has it", func() {
+		G.DEdges[1][0].Meta = &network.EdgeMeta{Distance: 12.5, Travel: 40 * time.Minute, Slack: 20 * time.Minute, Cost: 7.25}
+
+		H, err := read(write())
+		Expect(err).NotTo(HaveOccurred())
+		Expect(H.DEdges[1][0].Meta).To(Equal(G.DEdges[1][0].Meta))
+		Expect(H.DEdges[2][0].Meta).To(BeNil())
+	})
+
+	It("Rejects an edge listed twice", func() {
+		G.DEdges[2] = append(G.DEdges[2], &network.DeliveryEdge{Src: G.Stops[2], Dst: G.Stops[3], Wgt: float64(time.Minute)})
+
+		_, err := read(write())
+		Expect(err).To(MatchError("Record 8: Edge from 2 to 3 is listed twice."))
+	})
+
+	It("Yields records one at a time, ending with io.EOF", func() {
+		r, err := network.NewStreamReader(bytes.NewReader(write()))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(r.Version).To(BeEquivalentTo(network.StreamVersion))
+
+		kinds := make([]string, 0)
+		for {
+			rec, err := r.Next()
+			if err == io.EOF {
+				break
+			}
+			Expect(err).NotTo(HaveOccurred())
+
+			switch {
+			case rec.Hub != nil:
+				kinds = append(kinds, "hub")
+			case rec.Stop != nil:
+				kinds = append(kinds, "stop")
+			default:
+				kinds = append(kinds, "edge")
+			}
+		}
+
+		Expect(kinds).To(Equal([]string{"hub", "stop", "stop", "stop", "stop", "edge", "edge", "edge", "edge"}))
+
+		_, err = r.Next()
+		Expect(err).To(Equal(io.EOF))
+	})
+
+	It("Builds a CSR view straight from the stream", func() {
+		r, err := network.NewStreamReader(bytes.NewReader(write()))
+		Expect(err).NotTo(HaveOccurred())
+
+		C, err := r.ReadCSR()
+		Expect(err).NotTo(HaveOccurred())
+		Expect(C).To(Equal(network.NewCSR(G)))
+	})
+
+	It("Detects corrupted, truncated and foreign streams", func() {
+		data := write()
+
+		corrupt := append([]byte{}, data...)
+		corrupt[len(corrupt)-11] ^= 0xff
+		_, err := read(corrupt)
+		Expect(err).To(MatchError(ContainSubstring("does not match its contents")))
+
+		_, err = read(data[:len(data)-8])
+		Expect(err).To(MatchError("Stream ends before its trailer, so it is probably truncated."))
+
+		_, err = network.NewStreamReader(bytes.NewReader([]byte("id,kind,timestamp\n")))
+		Expect(err).To(MatchError("Stream does not start with a burrow stream header."))
+
+		future := append([]byte{}, data...)
+		future[4] = network.StreamVersion + 1
+		_, err = network.NewStreamReader(bytes.NewReader(future))
+		Expect(err).To(MatchError("Stream has format version 2, but only version 1 can be read."))
+	})
+
+	It("Rejects edges to nodes the stream hasn't listed yet", func() {
+		var buf bytes.Buffer
+		w, err := network.NewStreamWriter(&buf)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(w.WriteHub(G.Hubs[1])).To(Succeed())
+		Expect(w.WriteEdge(G.DEdges[1][0])).To(Succeed())
+		Expect(w.Close()).To(Succeed())
+
+		_, err = read(buf.Bytes())
+		Expect(err).To(MatchError("Record 2: Edge refers to unknown node 2."))
+		Expect(w.WriteHub(G.Hubs[1])).To(MatchError("Stream is already closed."))
+	})
+})
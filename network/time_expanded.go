@@ -0,0 +1,189 @@
+package network
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// HubInstant is a hub at a single point in time: one of the time-indexed copies of a hub in a TimeExpandedNetwork.
+//
+// The embedded HubNode is a copy of Hub with its own ID, and is what the network stores in its Hubs map and edges, so code unaware of time expansion sees an ordinary hub.
+type HubInstant struct {
+	HubNode
+	Hub  *HubNode
+	Time time.Time
+}
+
+// TimeExpandedNetwork is a delivery network in which every hub is replaced by a chain of copies, one per time step, joined in time order by waiting arcs. Vehicles leave a hub from the copy at or before the time they must set out to reach a stop, and return to the copy at or after the time they get back, so a flow of vehicles through the network accounts for how many sit at each hub at each step.
+//
+// Stops, their timestamps and stop-to-stop edges are carried over unchanged. The embedded DeliveryNetwork holds the expanded graph, so a TimeExpandedNetwork can be used anywhere a gonum graph can.
+type TimeExpandedNetwork struct {
+	*DeliveryNetwork
+
+	// Step is the time between consecutive copies of a hub.
+	Step time.Duration
+
+	// Instants indexes the hub copies by ID.
+	Instants map[int64]*HubInstant
+
+	// Timelines lists each original hub's copies in time order, keyed by the original hub's ID.
+	Timelines map[int64][]*HubInstant
+}
+
+// NewTimeExpandedNetwork expands the hubs of G into copies step apart. Edge weights out of and into hubs are read as travel times: an edge from a hub to a stop leaves the latest copy that still reaches the stop by its timestamp, and an edge from a stop to a hub arrives at the earliest copy after the vehicle can get there. Hub-to-hub edges leave every copy of their source hub that has a copy of their destination hub to arrive at. Waiting arcs have zero weight and are of kind WaitEdge, so they can be told apart from transfers.
+//
+// Every hub gets copies over the same span of steps, aligned to multiples of step since the zero time, from the earliest departure to the latest arrival. Copies are given IDs above every ID in G.
+func NewTimeExpandedNetwork(G *DeliveryNetwork, step time.Duration) (*TimeExpandedNetwork, error) {
+	if step <= 0 {
+		return nil, fmt.Errorf("Time step must be positive, got %s.", step)
+	}
+
+	T := &TimeExpandedNetwork{
+		DeliveryNetwork: NewDeliveryNetwork(),
+		Step:            step,
+		Instants:        make(map[int64]*HubInstant),
+		Timelines:       make(map[int64][]*HubInstant),
+	}
+
+	for id, stop := range G.Stops {
+		T.Stops[id] = stop
+	}
+
+	for id, stop := range G.Pairs {
+		T.Pairs[id] = stop
+	}
+
+	nextID := int64(0)
+	var start, end time.Time
+
+	observe := func(t time.Time) {
+		if start.IsZero() || t.Before(start) {
+			start = t
+		}
+		if end.IsZero() || t.After(end) {
+			end = t
+		}
+	}
+
+	for id, edges := range G.DEdges {
+		if id >= nextID {
+			nextID = id + 1
+		}
+
+		for _, edge := range edges {
+			if id := edge.Dst.ID(); id >= nextID {
+				nextID = id + 1
+			}
+
+			switch src, dst := edge.Src, edge.Dst; {
+			case src.IsHub() && !dst.IsHub():
+				observe(T.floor(dst.(*StopNode).Timestamp.Add(-time.Duration(edge.Wgt))))
+			case !src.IsHub() && dst.IsHub():
+				observe(T.ceil(src.(*StopNode).Timestamp.Add(time.Duration(edge.Wgt))))
+			case !src.IsHub() && !dst.IsHub():
+				T.DEdges[id] = append(T.DEdges[id], edge)
+			}
+		}
+	}
+
+	for id := range G.Hubs {
+		if id >= nextID {
+			nextID = id + 1
+		}
+	}
+	for id := range G.Stops {
+		if id >= nextID {
+			nextID = id + 1
+		}
+	}
+
+	hubs := make([]*HubNode, 0, len(G.Hubs))
+	for _, hub := range G.Hubs {
+		hubs = append(hubs, hub)
+	}
+	sort.Slice(hubs, func(i, j int) bool { return hubs[i].ID() < hubs[j].ID() })
+
+	for _, hub := range hubs {
+		timeline := make([]*HubInstant, 0)
+
+		for t := start; !start.IsZero() && !t.After(end); t = t.Add(step) {
+			instant := &HubInstant{HubNode: *hub, Hub: hub, Time: t}
+			instant.Val = nextID
+			nextID++
+
+			T.Hubs[instant.ID()] = &instant.HubNode
+			T.Instants[instant.ID()] = instant
+
+			if n := len(timeline); n > 0 {
+				prev := timeline[n-1]
+				T.DEdges[prev.ID()] = append(T.DEdges[prev.ID()], &DeliveryEdge{Src: &prev.HubNode, Dst: &instant.HubNode, Wgt: 0.0, Knd: WaitEdge})
+			}
+
+			timeline = append(timeline, instant)
+		}
+
+		T.Timelines[hub.ID()] = timeline
+	}
+
+	for _, hub := range hubs {
+		for _, edge := range G.DEdges[hub.ID()] {
+			w := time.Duration(edge.Wgt)
+
+			if dst, ok := edge.Dst.(*StopNode); ok {
+				src := T.Instant(hub.ID(), T.floor(dst.Timestamp.Add(-w)))
+				T.DEdges[src.ID()] = append(T.DEdges[src.ID()], &DeliveryEdge{Src: &src.HubNode, Dst: dst, Wgt: edge.Wgt, Knd: edge.Kind(), Meta: edge.copyMeta()})
+				continue
+			}
+
+			for _, src := range T.Timelines[hub.ID()] {
+				if dst := T.Instant(edge.Dst.ID(), T.ceil(src.Time.Add(w))); dst != nil && !dst.Time.Before(src.Time.Add(w)) {
+					T.DEdges[src.ID()] = append(T.DEdges[src.ID()], &DeliveryEdge{Src: &src.HubNode, Dst: &dst.HubNode, Wgt: edge.Wgt, Knd: edge.Kind(), Meta: edge.copyMeta()})
+				}
+			}
+		}
+	}
+
+	for _, stop := range G.Stops {
+		for _, edge := range G.DEdges[stop.ID()] {
+			if !edge.Dst.IsHub() {
+				continue
+			}
+
+			dst := T.Instant(edge.Dst.ID(), T.ceil(stop.Timestamp.Add(time.Duration(edge.Wgt))))
+			T.DEdges[stop.ID()] = append(T.DEdges[stop.ID()], &DeliveryEdge{Src: stop, Dst: &dst.HubNode, Wgt: edge.Wgt, Knd: edge.Kind(), Meta: edge.copyMeta()})
+		}
+	}
+
+	return T, nil
+}
+
+// Instant returns the copy of the hub with the given original ID that stands for time t: the latest copy at or before t, or the first copy if t precedes them all. Returns nil if the hub has no copies.
+func (T *TimeExpandedNetwork) Instant(hub int64, t time.Time) *HubInstant {
+	timeline := T.Timelines[hub]
+	if len(timeline) == 0 {
+		return nil
+	}
+
+	// The first copy after t, less one.
+	i := sort.Search(len(timeline), func(i int) bool { return timeline[i].Time.After(t) }) - 1
+	if i < 0 {
+		i = 0
+	}
+
+	return timeline[i]
+}
+
+// floor rounds t down to a multiple of the network's step.
+func (T *TimeExpandedNetwork) floor(t time.Time) time.Time {
+	return t.Truncate(T.Step)
+}
+
+// ceil rounds t up to a multiple of the network's step.
+func (T *TimeExpandedNetwork) ceil(t time.Time) time.Time {
+	if f := t.Truncate(T.Step); f.Before(t) {
+		return f.Add(T.Step)
+	}
+
+	return t
+}
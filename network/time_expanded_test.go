@@ -0,0 +1,130 @@
+package network_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gonum.org/v1/gonum/graph"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+var _ = Describe("TimeExpandedNetwork", func() {
+	var (
+		t0     time.Time
+		hub    *network.HubNode
+		stops  []*network.StopNode
+		G      *network.DeliveryNetwork
+		T      *network.TimeExpandedNetwork
+		hourAt func(h int) time.Time
+	)
+
+	link := func(src, dst network.DeliveryNode, wgt time.Duration) {
+		G.DEdges[src.ID()] = append(G.DEdges[src.ID()], &network.DeliveryEdge{Src: src, Dst: dst, Wgt: float64(wgt)})
+	}
+
+	BeforeEach(func() {
+		t0 = time.Date(2022, 3, 29, 0, 0, 0, 0, time.UTC)
+		hourAt = func(h int) time.Time { return t0.Add(time.Duration(h) * time.Hour) }
+
+		G = network.NewDeliveryNetwork()
+		hub = &network.HubNode{Val: 1, Fleet: 3}
+		G.Hubs[hub.ID()] = hub
+
+		stops = []*network.StopNode{
+			{Val: 10, Timestamp: hourAt(8)},
+			{Val: 11, Timestamp: hourAt(9).Add(30 * time.Minute)},
+		}
+
+		for _, stop := range stops {
+			G.Stops[stop.ID()] = stop
+			link(hub, stop, time.Hour)
+			link(stop, hub, time.Hour)
+		}
+		link(stops[0], stops[1], 90*time.Minute)
+
+		var err error
+		T, err = network.NewTimeExpandedNetwork(G, time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("Implements the required Directed and Weighted interfaces", func() {
+		var _ graph.Directed = T
+		var _ graph.Weighted = T
+	})
+
+	It("Copies each hub once per step from the earliest departure to the latest return", func() {
+		timeline := T.Timelines[hub.ID()]
+		Expect(timeline).To(HaveLen(5))
+		Expect(T.Hubs).To(HaveLen(5))
+
+		for i, instant := range timeline {
+			Expect(instant.Time).To(Equal(hourAt(7 + i)))
+			Expect(instant.Hub).To(Equal(hub))
+			Expect(instant.Fleet).To(BeEquivalentTo(3))
+			Expect(instant.ID()).To(BeNumerically(">", 11))
+			Expect(T.Instants[instant.ID()]).To(Equal(instant))
+
+			if i > 0 {
+				w, ok := T.Weight(timeline[i-1].ID(), instant.ID())
+				Expect(ok).To(BeTrue())
+				Expect(w).To(BeZero())
+			}
+		}
+	})
+
+	It("Dispatches from the latest copy that reaches a stop on time and returns to the earliest copy after arrival", func() {
+		Expect(T.HasEdgeFromTo(T.Instant(hub.ID(), hourAt(7)).ID(), 10)).To(BeTrue())
+		Expect(T.HasEdgeFromTo(T.Instant(hub.ID(), hourAt(8)).ID(), 11)).To(BeTrue())
+		Expect(T.HasEdgeFromTo(10, T.Instant(hub.ID(), hourAt(9)).ID())).To(BeTrue())
+		Expect(T.HasEdgeFromTo(11, T.Instant(hub.ID(), hourAt(11)).ID())).To(BeTrue())
+
+		Expect(T.From(T.Instant(hub.ID(), hourAt(9)).ID()).Len()).To(Equal(1))
+	})
+
+	It("Keeps stops and stop-to-stop edges", func() {
+		Expect(T.Stops).To(HaveLen(2))
+		Expect(T.Stops[10]).To(Equal(stops[0]))
+
+		w, ok := T.Weight(10, 11)
+		Expect(ok).To(BeTrue())
+		Expect(w).To(Equal(float64(90 * time.Minute)))
+	})
+
+	It("Links hubs to each copy of another hub that can be reached in time", func() {
+		other := &network.HubNode{Val: 2}
+		G.Hubs[other.ID()] = other
+		link(hub, other, 90*time.Minute)
+
+		T, err := network.NewTimeExpandedNetwork(G, time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(T.Timelines[other.ID()]).To(HaveLen(5))
+
+		Expect(T.HasEdgeFromTo(T.Instant(hub.ID(), hourAt(7)).ID(), T.Instant(other.ID(), hourAt(9)).ID())).To(BeTrue())
+		Expect(T.HasEdgeFromTo(T.Instant(hub.ID(), hourAt(9)).ID(), T.Instant(other.ID(), hourAt(11)).ID())).To(BeTrue())
+
+		// Leaving at 10:00 arrives after the last copy.
+		Expect(T.From(T.Instant(hub.ID(), hourAt(10)).ID()).Len()).To(Equal(1))
+
+		// Transfers leave the first three copies; each hub's 5 copies are joined by 4 waiting arcs.
+		Expect(T.EdgesOfKind(network.TransferEdge).Len()).To(Equal(3))
+		Expect(T.EdgesOfKind(network.WaitEdge).Len()).To(Equal(8))
+		for _, edge := range T.EdgesOfKind(network.WaitEdge).Payload {
+			Expect(edge.IsTransfer()).To(BeFalse())
+			Expect(T.Instants[edge.Src.ID()].Hub).To(BeIdenticalTo(T.Instants[edge.Dst.ID()].Hub))
+		}
+	})
+
+	It("Finds the copy standing for any time", func() {
+		Expect(T.Instant(hub.ID(), hourAt(8).Add(59*time.Minute)).Time).To(Equal(hourAt(8)))
+		Expect(T.Instant(hub.ID(), hourAt(3)).Time).To(Equal(hourAt(7)))
+		Expect(T.Instant(hub.ID(), hourAt(20)).Time).To(Equal(hourAt(11)))
+		Expect(T.Instant(99, hourAt(8))).To(BeNil())
+	})
+
+	It("Rejects non-positive steps", func() {
+		_, err := network.NewTimeExpandedNetwork(G, 0)
+		Expect(err).To(MatchError("Time step must be positive, got 0s."))
+	})
+})
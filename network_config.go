@@ -3,34 +3,97 @@ package burrow
 import (
 	"time"
 	"fmt"
+	"math/rand"
+
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bdshroyer/burrow/network"
 )
 
 
 type TimeBox [2]time.Duration
 
+// HubConfig sets the operating attributes of a single generated hub. Zero values leave the hub unrestricted; see network.HubNode for their meaning.
+type HubConfig struct {
+	Hours           *network.OperatingHours
+	Fleet, Capacity uint
+}
+
 type DeliveryNetworkConfig struct {
 	HubNodes, StopNodes uint
 	Distro SampleDistribution[time.Time]
 	EdgeBounds *TimeBox
+
+	// Workers sets the number of goroutines used to build stop-to-stop edges. Values of 0 or 1 build them sequentially.
+	// Stops are always sampled on the calling goroutine, so a seeded distribution yields the same network for any worker count.
+	Workers uint
+
+	// Assignment picks the hubs each stop is linked to. If nil, every stop is linked to every hub.
+	Assignment HubAssignment
+
+	// Locations, if set, gives every hub and stop a location. Hubs are placed as they're created, and each stop right after its timestamp is sampled.
+	// Every edge then carries its distance and travel time at Speed in its metadata. Stop-to-stop edges are still drawn by timestamp alone, so some may be too short to drive; routing.Validate reports routes that take them.
+	Locations LocationDistribution
+
+	// Speed is the travel speed in kilometres per hour used to fill in edge metadata when there are locations. Zero means defaultSpeed.
+	Speed float64
+
+	// HubConfigs[i] applies to the i-th hub generated. Hubs past the end of the list are unrestricted. Hub-to-stop edges are only drawn to stops falling within the hub's operating hours.
+	HubConfigs []HubConfig
+
+	// Demand, if set, gives every stop a demand. It is sampled after the stop's timestamp and location.
+	Demand SampleDistribution[float64]
+
+	// Pairs is the number of pickup-and-delivery pairs to generate on top of the ordinary stops. Each dropoff follows its pickup after a delay drawn from PairDelay, which must be set if Pairs is.
+	Pairs     uint
+	PairDelay SampleDistribution[time.Duration]
+
+	// LineHaul, if positive, links every hub to every other hub in both directions with a transfer edge of this weight. If zero, hubs are only linked to stops.
+	LineHaul time.Duration
+
+	// Sink, if set, receives every node and edge as MakeDeliveryNetwork creates it, with each node before any of its edges. The caller closes the sink once the network is made.
+	Sink NetworkSink
+}
+
+// NetworkSink receives the parts of a network as it is generated. network.StreamWriter is one.
+type NetworkSink interface {
+	WriteHub(*network.HubNode) error
+	WriteStop(*network.StopNode) error
+	WritePair(pickup *network.PairedStopNode) error
+	WriteEdge(*network.DeliveryEdge) error
 }
 
-func (spec NetworkSpec) parseDistribution() (SampleDistribution[time.Time], error) {
+func (spec *NetworkSpec) parseDistribution(rng randSource) (SampleDistribution[time.Time], error) {
+	return parseTimestampDistribution(spec.GetUniform(), spec.GetGaussian(), spec.Start, spec.End, spec.ClampToWindow, rng)
+}
+
+// parseTimestampDistribution converts whichever of the distribution specs is set into a sampling function. The uniform distribution spans [start, end), and if clamp is set, every other distribution is truncated to it.
+// Returns a nil distribution if neither spec is set.
+func parseTimestampDistribution(
+	uniform *NetworkSpec_UniformDistro,
+	gaussian *NetworkSpec_GaussianDistro,
+	start, end *timestamppb.Timestamp,
+	clamp bool,
+	rng randSource,
+) (SampleDistribution[time.Time], error) {
 	var distro SampleDistribution[time.Time]
 	var err error
 
-	if distroSpec := spec.GetUniform(); distroSpec != nil {
-		tStart := spec.Start.AsTime()
-		durationRange := spec.End.AsTime().Sub(spec.Start.AsTime())
+	if clamp && (start == nil || end == nil) {
+		return nil, fmt.Errorf("Clamping to the window requires both start and end.")
+	}
 
-		if distro, err = UniformTimestampDistribution(tStart, durationRange); err != nil {
+	if uniform != nil {
+		tStart := start.AsTime()
+		durationRange := end.AsTime().Sub(start.AsTime())
+
+		if distro, err = uniformTimestampDistribution(rng, tStart, durationRange); err != nil {
 			return nil, err
 		}
 	}
 
-	if distroSpec := spec.GetGaussian(); distroSpec != nil {
-		tMean := time.UnixMilli(int64(distroSpec.Mean))
-		dStdDev := time.Duration(distroSpec.StdDev)
-		if distro, err = GaussianTimestampDistribution(tMean, dStdDev); err != nil {
+	if gaussian != nil {
+		if distro, err = parseGaussianDistribution(gaussian, start, end, gaussian.Truncate || clamp, rng); err != nil {
 			return nil, err
 		}
 	}
@@ -38,13 +101,97 @@ func (spec NetworkSpec) parseDistribution() (SampleDistribution[time.Time], erro
 	return distro, err
 }
 
+// parseGaussianDistribution reads a Gaussian spec, preferring MeanTime and Deviation over the legacy Mean (Unix milliseconds) and StdDev (nanoseconds). Without any mean, the distribution is centered on the midpoint of [start, end), and it is an error if either is unset. If truncate is set, samples are confined to [start, end).
+func parseGaussianDistribution(gaussian *NetworkSpec_GaussianDistro, start, end *timestamppb.Timestamp, truncate bool, rng randSource) (SampleDistribution[time.Time], error) {
+	if gaussian.MeanTime != nil && gaussian.Mean != 0 {
+		return nil, fmt.Errorf("Gaussian spec sets both Mean and MeanTime; MeanTime replaces Mean.")
+	}
+
+	if gaussian.Deviation != nil && gaussian.StdDev != 0 {
+		return nil, fmt.Errorf("Gaussian spec sets both StdDev and Deviation; Deviation replaces StdDev.")
+	}
+
+	var tMean time.Time
+	switch {
+	case gaussian.MeanTime != nil:
+		tMean = gaussian.MeanTime.AsTime()
+	case gaussian.Mean != 0:
+		tMean = time.UnixMilli(gaussian.Mean)
+	case start != nil && end != nil:
+		tMean = start.AsTime().Add(end.AsTime().Sub(start.AsTime()) / 2)
+	default:
+		return nil, fmt.Errorf("Gaussian spec has no mean; set MeanTime, or set both start and end to center it on their midpoint.")
+	}
+
+	dStdDev := time.Duration(gaussian.StdDev)
+	if gaussian.Deviation != nil {
+		dStdDev = gaussian.Deviation.AsDuration()
+	}
+
+	if !truncate {
+		return gaussianTimestampDistribution(rng, tMean, dStdDev)
+	}
+
+	if start == nil || end == nil {
+		return nil, fmt.Errorf("Truncating a Gaussian distribution requires both start and end.")
+	}
+
+	return truncatedGaussianTimestampDistribution(rng, tMean, dStdDev, start.AsTime(), end.AsTime())
+}
+
+// parseHubConfigs converts the spec's hub attributes into hub configs. A hub only gets operating hours if its spec sets Open or Close.
+func (spec *NetworkSpec) parseHubConfigs() []HubConfig {
+	if len(spec.HubAttributes) == 0 {
+		return nil
+	}
+
+	hubConfigs := make([]HubConfig, 0, len(spec.HubAttributes))
+	for _, attrs := range spec.HubAttributes {
+		hubCfg := HubConfig{Fleet: uint(attrs.Fleet), Capacity: uint(attrs.Capacity)}
+
+		if attrs.Open != nil || attrs.Close != nil {
+			hubCfg.Hours = &network.OperatingHours{Open: attrs.Open.AsDuration(), Close: attrs.Close.AsDuration()}
+		}
+
+		hubConfigs = append(hubConfigs, hubCfg)
+	}
+
+	return hubConfigs
+}
+
 // Generates a NetworkConfig from a NetworkSpec. Returns an error if it's unable to convert the distribution
 // specification into an actual distribution sampling function.
 //
 // Note that this conversion does no validation on the non-distribution attributes of the network spec. Bad values like negative node counts are expected to be handled by the generating method.
 func NewNetworkConfig(spec NetworkSpec) (*DeliveryNetworkConfig, error) {
+	return NewNetworkConfigFrom(&spec)
+}
+
+// NewNetworkConfigFrom works like NewNetworkConfig, but takes the spec by pointer, as LoadSpec and ParseSpec return it.
+func NewNetworkConfigFrom(spec *NetworkSpec) (*DeliveryNetworkConfig, error) {
+	return newNetworkConfig(spec, globalRand{})
+}
+
+// NewSeededNetworkConfig works like NewNetworkConfig, except that the config's distribution draws from rng instead of the shared math/rand source.
+// Configs with their own sources can be used to generate networks concurrently and reproducibly.
+func NewSeededNetworkConfig(spec *NetworkSpec, rng *rand.Rand) (*DeliveryNetworkConfig, error) {
+	if rng == nil {
+		return nil, fmt.Errorf("Must receive a non-null random source.")
+	}
+
+	return newNetworkConfig(spec, rng)
+}
 
-	distro, err := spec.parseDistribution()
+func newNetworkConfig(spec *NetworkSpec, rng randSource) (*DeliveryNetworkConfig, error) {
+	if spec == nil {
+		return nil, fmt.Errorf("No network spec provided")
+	}
+
+	if len(spec.Days) > 0 {
+		return nil, fmt.Errorf("Spec describes a multi-day network; use NewMultiDayNetworkConfig")
+	}
+
+	distro, err := spec.parseDistribution(rng)
 	if err != nil {
 		return nil, err
 	}
@@ -53,12 +200,58 @@ func NewNetworkConfig(spec NetworkSpec) (*DeliveryNetworkConfig, error) {
 		return nil, fmt.Errorf("No distribution provided")
 	}
 
+	assignment, locations, err := spec.parseHubAssignment(rng)
+	if err != nil {
+		return nil, err
+	}
+
+	demand, err := spec.parseDemandDistribution(rng)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs, pairDelay, err := spec.parsePairs(rng)
+	if err != nil {
+		return nil, err
+	}
+
 	cfg := &DeliveryNetworkConfig{
 		HubNodes: uint(spec.Hubs),
 		StopNodes: uint(spec.Stops),
 		EdgeBounds: &TimeBox{spec.ShortEdge.AsDuration(), spec.LongEdge.AsDuration()},
 		Distro: distro,
+		Assignment: assignment,
+		Locations: locations,
+		HubConfigs: spec.parseHubConfigs(),
+		Demand: demand,
+		Pairs: pairs,
+		PairDelay: pairDelay,
+		LineHaul: spec.LineHaul.AsDuration(),
 	}
 
 	return cfg, nil
 }
+
+// MakeNetworkFromSpec generates a network from spec with the random source rng, or with the shared math/rand source if rng is nil. Specs listing days produce a multi-day network; the rest a single-day one.
+func MakeNetworkFromSpec(spec *NetworkSpec, rng *rand.Rand) (*network.DeliveryNetwork, error) {
+	var source randSource = globalRand{}
+	if rng != nil {
+		source = rng
+	}
+
+	if spec != nil && len(spec.Days) > 0 {
+		cfg, err := newMultiDayNetworkConfig(spec, source)
+		if err != nil {
+			return nil, err
+		}
+
+		return MakeMultiDayNetwork(*cfg)
+	}
+
+	cfg, err := newNetworkConfig(spec, source)
+	if err != nil {
+		return nil, err
+	}
+
+	return MakeDeliveryNetwork(*cfg)
+}
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/bdshroyer/burrow"
+	"github.com/bdshroyer/burrow/network"
 	"github.com/bdshroyer/burrow/testutils"
 	. "github.com/onsi/ginkgo/v2"
 	. "github.com/onsi/gomega"
@@ -111,6 +112,109 @@ var _ = Describe("NetworkConfig", func() {
 				Expect(err).NotTo(HaveOccurred())
 				Expect(pValue).To(And(BeNumerically(">=", 0.0), BeNumerically("<=", 0.95)))
 			})
+
+			It("Reads Gaussian means and deviations as timestamps and durations", func() {
+				mu := tStart.AsTime().Add(11 * time.Hour)
+				spec.Distribution = &burrow.NetworkSpec_Gaussian{Gaussian: &burrow.NetworkSpec_GaussianDistro{
+					MeanTime:  timestamppb.New(mu),
+					Deviation: durationpb.New(2 * time.Hour),
+				}}
+
+				cfg, err := burrow.NewNetworkConfigFrom(&spec)
+				Expect(err).NotTo(HaveOccurred())
+
+				samples := make([]float64, 10000)
+				for i := range samples {
+					samples[i] = float64(cfg.Distro().Sub(mu)) / float64(time.Hour)
+				}
+
+				mean, variance := stat.MeanVariance(samples, nil)
+				Expect(mean).To(BeNumerically("~", 0, 0.1))
+				Expect(math.Sqrt(variance)).To(BeNumerically("~", 2, 0.1))
+			})
+
+			It("Centers Gaussians without a mean on the window and truncates them to it on request", func() {
+				spec.Distribution = &burrow.NetworkSpec_Gaussian{Gaussian: &burrow.NetworkSpec_GaussianDistro{
+					Deviation: durationpb.New(12 * time.Hour),
+					Truncate:  true,
+				}}
+
+				cfg, err := burrow.NewNetworkConfigFrom(&spec)
+				Expect(err).NotTo(HaveOccurred())
+
+				for i := 0; i < 1000; i++ {
+					ts := cfg.Distro()
+					Expect(ts).To(BeTemporally(">=", tStart.AsTime()))
+					Expect(ts).To(BeTemporally("<", tEnd.AsTime()))
+				}
+			})
+
+			It("Rejects Gaussians that mix legacy and new fields, lack a mean or truncate without a window", func() {
+				gaussian := &burrow.NetworkSpec_GaussianDistro{Mean: 1, MeanTime: tStart}
+				spec.Distribution = &burrow.NetworkSpec_Gaussian{Gaussian: gaussian}
+
+				_, err := burrow.NewNetworkConfigFrom(&spec)
+				Expect(err).To(MatchError("Gaussian spec sets both Mean and MeanTime; MeanTime replaces Mean."))
+
+				gaussian.Mean, gaussian.StdDev, gaussian.Deviation = 0, 1, durationpb.New(time.Hour)
+				_, err = burrow.NewNetworkConfigFrom(&spec)
+				Expect(err).To(MatchError("Gaussian spec sets both StdDev and Deviation; Deviation replaces StdDev."))
+
+				gaussian.StdDev, gaussian.MeanTime, spec.End = 0, nil, nil
+				_, err = burrow.NewNetworkConfigFrom(&spec)
+				Expect(err).To(MatchError("Gaussian spec has no mean; set MeanTime, or set both start and end to center it on their midpoint."))
+
+				gaussian.MeanTime, gaussian.Truncate = tStart, true
+				_, err = burrow.NewNetworkConfigFrom(&spec)
+				Expect(err).To(MatchError("Truncating a Gaussian distribution requires both start and end."))
+			})
+
+			It("Keeps every timestamp in the window when asked to clamp", func() {
+				spec.ClampToWindow = true
+				spec.Distribution = &burrow.NetworkSpec_Gaussian{Gaussian: &burrow.NetworkSpec_GaussianDistro{
+					MeanTime:  tEnd,
+					Deviation: durationpb.New(6 * time.Hour),
+				}}
+
+				cfg, err := burrow.NewNetworkConfigFrom(&spec)
+				Expect(err).NotTo(HaveOccurred())
+
+				for i := 0; i < 1000; i++ {
+					ts := cfg.Distro()
+					Expect(ts).To(BeTemporally(">=", tStart.AsTime()))
+					Expect(ts).To(BeTemporally("<", tEnd.AsTime()))
+				}
+
+				spec.Start = nil
+				_, err = burrow.NewNetworkConfigFrom(&spec)
+				Expect(err).To(MatchError("Clamping to the window requires both start and end."))
+			})
+
+			It("Reads the line haul as a duration", func() {
+				cfg, err := burrow.NewNetworkConfigFrom(&spec)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.LineHaul).To(BeZero())
+
+				spec.LineHaul = durationpb.New(4 * time.Hour)
+				cfg, err = burrow.NewNetworkConfigFrom(&spec)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(cfg.LineHaul).To(Equal(4 * time.Hour))
+			})
+
+			It("Carries hub attributes over to hub configs", func() {
+				spec.HubAttributes = []*burrow.NetworkSpec_HubSpec{
+					{Open: durationpb.New(6 * time.Hour), Close: durationpb.New(14 * time.Hour), Fleet: 4},
+					{Capacity: 2},
+				}
+
+				cfg, err := burrow.NewNetworkConfigFrom(&spec)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(cfg.HubConfigs).To(Equal([]burrow.HubConfig{
+					{Hours: &network.OperatingHours{Open: 6 * time.Hour, Close: 14 * time.Hour}, Fleet: 4},
+					{Capacity: 2},
+				}))
+			})
 		})
 	})
 })
@@ -38,6 +38,22 @@ type NetworkSpec struct {
 	End          *timestamppb.Timestamp     `protobuf:"bytes,6,opt,name=end,proto3" json:"end,omitempty"`
 	ShortEdge    *durationpb.Duration       `protobuf:"bytes,7,opt,name=ShortEdge,proto3" json:"ShortEdge,omitempty"`
 	LongEdge     *durationpb.Duration       `protobuf:"bytes,8,opt,name=LongEdge,proto3" json:"LongEdge,omitempty"`
+	// If Days is non-empty, the spec describes a multi-day network and the top-level Stops, Distribution, start and end are ignored.
+	// DayBoundary is the time of day, as an offset from midnight UTC, that no stop-to-stop edge may cross.
+	Days        []*NetworkSpec_DaySpec `protobuf:"bytes,9,rep,name=Days,proto3" json:"Days,omitempty"`
+	DayBoundary *durationpb.Duration   `protobuf:"bytes,10,opt,name=DayBoundary,proto3" json:"DayBoundary,omitempty"`
+	// If Assignment is unset, every hub serves every stop.
+	Assignment *NetworkSpec_HubAssignment `protobuf:"bytes,11,opt,name=Assignment,proto3" json:"Assignment,omitempty"`
+	Area       *NetworkSpec_LocationBox   `protobuf:"bytes,12,opt,name=Area,proto3" json:"Area,omitempty"`
+	// HubAttributes[i] applies to the i-th hub in generation order. Hubs past the end of the list are unrestricted.
+	HubAttributes []*NetworkSpec_HubSpec `protobuf:"bytes,13,rep,name=HubAttributes,proto3" json:"HubAttributes,omitempty"`
+	// If Demand is unset, stops have no demand.
+	Demand *NetworkSpec_DemandSpec `protobuf:"bytes,14,opt,name=Demand,proto3" json:"Demand,omitempty"`
+	Pairs  *NetworkSpec_PairSpec   `protobuf:"bytes,15,opt,name=Pairs,proto3" json:"Pairs,omitempty"`
+	// If ClampToWindow is set, every timestamp distribution, including each day's in a multi-day spec, is truncated to its [start, end) window, which must then be set. Uniform distributions already lie within it; Gaussian ones behave as if Truncate were set.
+	ClampToWindow bool `protobuf:"varint,16,opt,name=ClampToWindow,proto3" json:"ClampToWindow,omitempty"`
+	// If LineHaul is set, every hub is linked to every other hub, in both directions, by a transfer edge of this weight. Transfers model parcels moving between depots before final delivery, in single- and multi-day specs alike.
+	LineHaul *durationpb.Duration `protobuf:"bytes,17,opt,name=LineHaul,proto3" json:"LineHaul,omitempty"`
 }
 
 func (x *NetworkSpec) Reset() {
@@ -135,45 +151,939 @@ func (x *NetworkSpec) GetLongEdge() *durationpb.Duration {
 	return nil
 }
 
+func (x *NetworkSpec) GetDays() []*NetworkSpec_DaySpec {
+	if x != nil {
+		return x.Days
+	}
+	return nil
+}
+
+func (x *NetworkSpec) GetDayBoundary() *durationpb.Duration {
+	if x != nil {
+		return x.DayBoundary
+	}
+	return nil
+}
+
+func (x *NetworkSpec) GetAssignment() *NetworkSpec_HubAssignment {
+	if x != nil {
+		return x.Assignment
+	}
+	return nil
+}
+
+func (x *NetworkSpec) GetArea() *NetworkSpec_LocationBox {
+	if x != nil {
+		return x.Area
+	}
+	return nil
+}
+
+func (x *NetworkSpec) GetHubAttributes() []*NetworkSpec_HubSpec {
+	if x != nil {
+		return x.HubAttributes
+	}
+	return nil
+}
+
+func (x *NetworkSpec) GetDemand() *NetworkSpec_DemandSpec {
+	if x != nil {
+		return x.Demand
+	}
+	return nil
+}
+
+func (x *NetworkSpec) GetPairs() *NetworkSpec_PairSpec {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+func (x *NetworkSpec) GetClampToWindow() bool {
+	if x != nil {
+		return x.ClampToWindow
+	}
+	return false
+}
+
+func (x *NetworkSpec) GetLineHaul() *durationpb.Duration {
+	if x != nil {
+		return x.LineHaul
+	}
+	return nil
+}
+
 type isNetworkSpec_Distribution interface {
 	isNetworkSpec_Distribution()
 }
 
-type NetworkSpec_Uniform struct {
-	Uniform *NetworkSpec_UniformDistro `protobuf:"bytes,3,opt,name=Uniform,proto3,oneof"`
+type NetworkSpec_Uniform struct {
+	Uniform *NetworkSpec_UniformDistro `protobuf:"bytes,3,opt,name=Uniform,proto3,oneof"`
+}
+
+type NetworkSpec_Gaussian struct {
+	Gaussian *NetworkSpec_GaussianDistro `protobuf:"bytes,4,opt,name=Gaussian,proto3,oneof"`
+}
+
+func (*NetworkSpec_Uniform) isNetworkSpec_Distribution() {}
+
+func (*NetworkSpec_Gaussian) isNetworkSpec_Distribution() {}
+
+type NetworkSpec_UniformDistro struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *NetworkSpec_UniformDistro) Reset() {
+	*x = NetworkSpec_UniformDistro{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_spec_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkSpec_UniformDistro) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkSpec_UniformDistro) ProtoMessage() {}
+
+func (x *NetworkSpec_UniformDistro) ProtoReflect() protoreflect.Message {
+	mi := &file_network_spec_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkSpec_UniformDistro.ProtoReflect.Descriptor instead.
+func (*NetworkSpec_UniformDistro) Descriptor() ([]byte, []int) {
+	return file_network_spec_proto_rawDescGZIP(), []int{0, 0}
+}
+
+// Normally distributed stop timestamps. MeanTime defaults to the midpoint of [start, end) when both are set. If Truncate is set, samples are confined to [start, end), which must then both be set.
+//
+// Mean and StdDev are the original fields, in Unix milliseconds and nanoseconds respectively. They are deprecated but still read when MeanTime and Deviation are unset; to migrate, move Mean into MeanTime and StdDev into Deviation, converting the units. A spec may not set both the old and the new form of a field.
+type NetworkSpec_GaussianDistro struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Deprecated: Do not use.
+	Mean int64 `protobuf:"varint,1,opt,name=Mean,proto3" json:"Mean,omitempty"`
+	// Deprecated: Do not use.
+	StdDev    int64                  `protobuf:"varint,2,opt,name=StdDev,proto3" json:"StdDev,omitempty"`
+	MeanTime  *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=MeanTime,proto3" json:"MeanTime,omitempty"`
+	Deviation *durationpb.Duration   `protobuf:"bytes,4,opt,name=Deviation,proto3" json:"Deviation,omitempty"`
+	Truncate  bool                   `protobuf:"varint,5,opt,name=Truncate,proto3" json:"Truncate,omitempty"`
+}
+
+func (x *NetworkSpec_GaussianDistro) Reset() {
+	*x = NetworkSpec_GaussianDistro{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_spec_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkSpec_GaussianDistro) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkSpec_GaussianDistro) ProtoMessage() {}
+
+func (x *NetworkSpec_GaussianDistro) ProtoReflect() protoreflect.Message {
+	mi := &file_network_spec_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkSpec_GaussianDistro.ProtoReflect.Descriptor instead.
+func (*NetworkSpec_GaussianDistro) Descriptor() ([]byte, []int) {
+	return file_network_spec_proto_rawDescGZIP(), []int{0, 1}
+}
+
+// Deprecated: Do not use.
+func (x *NetworkSpec_GaussianDistro) GetMean() int64 {
+	if x != nil {
+		return x.Mean
+	}
+	return 0
+}
+
+// Deprecated: Do not use.
+func (x *NetworkSpec_GaussianDistro) GetStdDev() int64 {
+	if x != nil {
+		return x.StdDev
+	}
+	return 0
+}
+
+func (x *NetworkSpec_GaussianDistro) GetMeanTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.MeanTime
+	}
+	return nil
+}
+
+func (x *NetworkSpec_GaussianDistro) GetDeviation() *durationpb.Duration {
+	if x != nil {
+		return x.Deviation
+	}
+	return nil
+}
+
+func (x *NetworkSpec_GaussianDistro) GetTruncate() bool {
+	if x != nil {
+		return x.Truncate
+	}
+	return false
+}
+
+// A single day of a multi-day network. ActiveHubs holds the 0-based indices of the hubs operating that day; if empty, every hub operates.
+type NetworkSpec_DaySpec struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Stops uint32 `protobuf:"varint,1,opt,name=Stops,proto3" json:"Stops,omitempty"`
+	// Types that are assignable to Distribution:
+	//
+	//	*NetworkSpec_DaySpec_Uniform
+	//	*NetworkSpec_DaySpec_Gaussian
+	Distribution isNetworkSpec_DaySpec_Distribution `protobuf_oneof:"Distribution"`
+	Start        *timestamppb.Timestamp             `protobuf:"bytes,4,opt,name=start,proto3" json:"start,omitempty"`
+	End          *timestamppb.Timestamp             `protobuf:"bytes,5,opt,name=end,proto3" json:"end,omitempty"`
+	ActiveHubs   []uint32                           `protobuf:"varint,6,rep,packed,name=ActiveHubs,proto3" json:"ActiveHubs,omitempty"`
+}
+
+func (x *NetworkSpec_DaySpec) Reset() {
+	*x = NetworkSpec_DaySpec{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_spec_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkSpec_DaySpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkSpec_DaySpec) ProtoMessage() {}
+
+func (x *NetworkSpec_DaySpec) ProtoReflect() protoreflect.Message {
+	mi := &file_network_spec_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkSpec_DaySpec.ProtoReflect.Descriptor instead.
+func (*NetworkSpec_DaySpec) Descriptor() ([]byte, []int) {
+	return file_network_spec_proto_rawDescGZIP(), []int{0, 2}
+}
+
+func (x *NetworkSpec_DaySpec) GetStops() uint32 {
+	if x != nil {
+		return x.Stops
+	}
+	return 0
+}
+
+func (m *NetworkSpec_DaySpec) GetDistribution() isNetworkSpec_DaySpec_Distribution {
+	if m != nil {
+		return m.Distribution
+	}
+	return nil
+}
+
+func (x *NetworkSpec_DaySpec) GetUniform() *NetworkSpec_UniformDistro {
+	if x, ok := x.GetDistribution().(*NetworkSpec_DaySpec_Uniform); ok {
+		return x.Uniform
+	}
+	return nil
+}
+
+func (x *NetworkSpec_DaySpec) GetGaussian() *NetworkSpec_GaussianDistro {
+	if x, ok := x.GetDistribution().(*NetworkSpec_DaySpec_Gaussian); ok {
+		return x.Gaussian
+	}
+	return nil
+}
+
+func (x *NetworkSpec_DaySpec) GetStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Start
+	}
+	return nil
+}
+
+func (x *NetworkSpec_DaySpec) GetEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.End
+	}
+	return nil
+}
+
+func (x *NetworkSpec_DaySpec) GetActiveHubs() []uint32 {
+	if x != nil {
+		return x.ActiveHubs
+	}
+	return nil
+}
+
+type isNetworkSpec_DaySpec_Distribution interface {
+	isNetworkSpec_DaySpec_Distribution()
+}
+
+type NetworkSpec_DaySpec_Uniform struct {
+	Uniform *NetworkSpec_UniformDistro `protobuf:"bytes,2,opt,name=Uniform,proto3,oneof"`
+}
+
+type NetworkSpec_DaySpec_Gaussian struct {
+	Gaussian *NetworkSpec_GaussianDistro `protobuf:"bytes,3,opt,name=Gaussian,proto3,oneof"`
+}
+
+func (*NetworkSpec_DaySpec_Uniform) isNetworkSpec_DaySpec_Distribution() {}
+
+func (*NetworkSpec_DaySpec_Gaussian) isNetworkSpec_DaySpec_Distribution() {}
+
+// A rectangular area, in decimal degrees, over which hub and stop locations are sampled uniformly.
+type NetworkSpec_LocationBox struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	South float64 `protobuf:"fixed64,1,opt,name=South,proto3" json:"South,omitempty"`
+	West  float64 `protobuf:"fixed64,2,opt,name=West,proto3" json:"West,omitempty"`
+	North float64 `protobuf:"fixed64,3,opt,name=North,proto3" json:"North,omitempty"`
+	East  float64 `protobuf:"fixed64,4,opt,name=East,proto3" json:"East,omitempty"`
+}
+
+func (x *NetworkSpec_LocationBox) Reset() {
+	*x = NetworkSpec_LocationBox{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_spec_proto_msgTypes[4]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkSpec_LocationBox) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkSpec_LocationBox) ProtoMessage() {}
+
+func (x *NetworkSpec_LocationBox) ProtoReflect() protoreflect.Message {
+	mi := &file_network_spec_proto_msgTypes[4]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkSpec_LocationBox.ProtoReflect.Descriptor instead.
+func (*NetworkSpec_LocationBox) Descriptor() ([]byte, []int) {
+	return file_network_spec_proto_rawDescGZIP(), []int{0, 3}
+}
+
+func (x *NetworkSpec_LocationBox) GetSouth() float64 {
+	if x != nil {
+		return x.South
+	}
+	return 0
+}
+
+func (x *NetworkSpec_LocationBox) GetWest() float64 {
+	if x != nil {
+		return x.West
+	}
+	return 0
+}
+
+func (x *NetworkSpec_LocationBox) GetNorth() float64 {
+	if x != nil {
+		return x.North
+	}
+	return 0
+}
+
+func (x *NetworkSpec_LocationBox) GetEast() float64 {
+	if x != nil {
+		return x.East
+	}
+	return 0
+}
+
+// Decides which hubs serve each stop. Hub and stop indices are 0-based positions in generation order.
+type NetworkSpec_HubAssignment struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Mode:
+	//
+	//	*NetworkSpec_HubAssignment_Nearest
+	//	*NetworkSpec_HubAssignment_Proportional
+	//	*NetworkSpec_HubAssignment_Explicit
+	Mode isNetworkSpec_HubAssignment_Mode `protobuf_oneof:"Mode"`
+}
+
+func (x *NetworkSpec_HubAssignment) Reset() {
+	*x = NetworkSpec_HubAssignment{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_spec_proto_msgTypes[5]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkSpec_HubAssignment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkSpec_HubAssignment) ProtoMessage() {}
+
+func (x *NetworkSpec_HubAssignment) ProtoReflect() protoreflect.Message {
+	mi := &file_network_spec_proto_msgTypes[5]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkSpec_HubAssignment.ProtoReflect.Descriptor instead.
+func (*NetworkSpec_HubAssignment) Descriptor() ([]byte, []int) {
+	return file_network_spec_proto_rawDescGZIP(), []int{0, 4}
+}
+
+func (m *NetworkSpec_HubAssignment) GetMode() isNetworkSpec_HubAssignment_Mode {
+	if m != nil {
+		return m.Mode
+	}
+	return nil
+}
+
+func (x *NetworkSpec_HubAssignment) GetNearest() *NetworkSpec_HubAssignment_NearestHub {
+	if x, ok := x.GetMode().(*NetworkSpec_HubAssignment_Nearest); ok {
+		return x.Nearest
+	}
+	return nil
+}
+
+func (x *NetworkSpec_HubAssignment) GetProportional() *NetworkSpec_HubAssignment_ProportionalHubs {
+	if x, ok := x.GetMode().(*NetworkSpec_HubAssignment_Proportional); ok {
+		return x.Proportional
+	}
+	return nil
+}
+
+func (x *NetworkSpec_HubAssignment) GetExplicit() *NetworkSpec_HubAssignment_ExplicitHubs {
+	if x, ok := x.GetMode().(*NetworkSpec_HubAssignment_Explicit); ok {
+		return x.Explicit
+	}
+	return nil
+}
+
+type isNetworkSpec_HubAssignment_Mode interface {
+	isNetworkSpec_HubAssignment_Mode()
+}
+
+type NetworkSpec_HubAssignment_Nearest struct {
+	Nearest *NetworkSpec_HubAssignment_NearestHub `protobuf:"bytes,1,opt,name=Nearest,proto3,oneof"`
+}
+
+type NetworkSpec_HubAssignment_Proportional struct {
+	Proportional *NetworkSpec_HubAssignment_ProportionalHubs `protobuf:"bytes,2,opt,name=Proportional,proto3,oneof"`
+}
+
+type NetworkSpec_HubAssignment_Explicit struct {
+	Explicit *NetworkSpec_HubAssignment_ExplicitHubs `protobuf:"bytes,3,opt,name=Explicit,proto3,oneof"`
+}
+
+func (*NetworkSpec_HubAssignment_Nearest) isNetworkSpec_HubAssignment_Mode() {}
+
+func (*NetworkSpec_HubAssignment_Proportional) isNetworkSpec_HubAssignment_Mode() {}
+
+func (*NetworkSpec_HubAssignment_Explicit) isNetworkSpec_HubAssignment_Mode() {}
+
+// Operating attributes of a single hub. Open and Close are offsets from midnight UTC; a window whose Close precedes its Open runs past midnight, and if neither is set the hub never closes.
+// Fleet is the number of vehicles based at the hub and Capacity the most routes it can dispatch. Zero means unlimited.
+type NetworkSpec_HubSpec struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Open     *durationpb.Duration `protobuf:"bytes,1,opt,name=Open,proto3" json:"Open,omitempty"`
+	Close    *durationpb.Duration `protobuf:"bytes,2,opt,name=Close,proto3" json:"Close,omitempty"`
+	Fleet    uint32               `protobuf:"varint,3,opt,name=Fleet,proto3" json:"Fleet,omitempty"`
+	Capacity uint32               `protobuf:"varint,4,opt,name=Capacity,proto3" json:"Capacity,omitempty"`
+}
+
+func (x *NetworkSpec_HubSpec) Reset() {
+	*x = NetworkSpec_HubSpec{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_spec_proto_msgTypes[6]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkSpec_HubSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkSpec_HubSpec) ProtoMessage() {}
+
+func (x *NetworkSpec_HubSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_network_spec_proto_msgTypes[6]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkSpec_HubSpec.ProtoReflect.Descriptor instead.
+func (*NetworkSpec_HubSpec) Descriptor() ([]byte, []int) {
+	return file_network_spec_proto_rawDescGZIP(), []int{0, 5}
+}
+
+func (x *NetworkSpec_HubSpec) GetOpen() *durationpb.Duration {
+	if x != nil {
+		return x.Open
+	}
+	return nil
+}
+
+func (x *NetworkSpec_HubSpec) GetClose() *durationpb.Duration {
+	if x != nil {
+		return x.Close
+	}
+	return nil
+}
+
+func (x *NetworkSpec_HubSpec) GetFleet() uint32 {
+	if x != nil {
+		return x.Fleet
+	}
+	return 0
+}
+
+func (x *NetworkSpec_HubSpec) GetCapacity() uint32 {
+	if x != nil {
+		return x.Capacity
+	}
+	return 0
+}
+
+// Distribution of each stop's demand, such as a parcel count or weight. Uniform demand spans [Min, Max).
+type NetworkSpec_DemandSpec struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	// Types that are assignable to Distribution:
+	//
+	//	*NetworkSpec_DemandSpec_Fixed
+	//	*NetworkSpec_DemandSpec_Uniform
+	//	*NetworkSpec_DemandSpec_Poisson
+	Distribution isNetworkSpec_DemandSpec_Distribution `protobuf_oneof:"Distribution"`
+}
+
+func (x *NetworkSpec_DemandSpec) Reset() {
+	*x = NetworkSpec_DemandSpec{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_spec_proto_msgTypes[7]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkSpec_DemandSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkSpec_DemandSpec) ProtoMessage() {}
+
+func (x *NetworkSpec_DemandSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_network_spec_proto_msgTypes[7]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkSpec_DemandSpec.ProtoReflect.Descriptor instead.
+func (*NetworkSpec_DemandSpec) Descriptor() ([]byte, []int) {
+	return file_network_spec_proto_rawDescGZIP(), []int{0, 6}
+}
+
+func (m *NetworkSpec_DemandSpec) GetDistribution() isNetworkSpec_DemandSpec_Distribution {
+	if m != nil {
+		return m.Distribution
+	}
+	return nil
+}
+
+func (x *NetworkSpec_DemandSpec) GetFixed() *NetworkSpec_DemandSpec_FixedDemand {
+	if x, ok := x.GetDistribution().(*NetworkSpec_DemandSpec_Fixed); ok {
+		return x.Fixed
+	}
+	return nil
+}
+
+func (x *NetworkSpec_DemandSpec) GetUniform() *NetworkSpec_DemandSpec_UniformDemand {
+	if x, ok := x.GetDistribution().(*NetworkSpec_DemandSpec_Uniform); ok {
+		return x.Uniform
+	}
+	return nil
+}
+
+func (x *NetworkSpec_DemandSpec) GetPoisson() *NetworkSpec_DemandSpec_PoissonDemand {
+	if x, ok := x.GetDistribution().(*NetworkSpec_DemandSpec_Poisson); ok {
+		return x.Poisson
+	}
+	return nil
+}
+
+type isNetworkSpec_DemandSpec_Distribution interface {
+	isNetworkSpec_DemandSpec_Distribution()
+}
+
+type NetworkSpec_DemandSpec_Fixed struct {
+	Fixed *NetworkSpec_DemandSpec_FixedDemand `protobuf:"bytes,1,opt,name=Fixed,proto3,oneof"`
+}
+
+type NetworkSpec_DemandSpec_Uniform struct {
+	Uniform *NetworkSpec_DemandSpec_UniformDemand `protobuf:"bytes,2,opt,name=Uniform,proto3,oneof"`
+}
+
+type NetworkSpec_DemandSpec_Poisson struct {
+	Poisson *NetworkSpec_DemandSpec_PoissonDemand `protobuf:"bytes,3,opt,name=Poisson,proto3,oneof"`
+}
+
+func (*NetworkSpec_DemandSpec_Fixed) isNetworkSpec_DemandSpec_Distribution() {}
+
+func (*NetworkSpec_DemandSpec_Uniform) isNetworkSpec_DemandSpec_Distribution() {}
+
+func (*NetworkSpec_DemandSpec_Poisson) isNetworkSpec_DemandSpec_Distribution() {}
+
+// Pickup-and-delivery pairs, generated on top of the ordinary stops. Each pickup is sampled like any other stop, and its dropoff follows after a delay drawn uniformly from [MinDelay, MaxDelay), or exactly MinDelay if MaxDelay doesn't exceed it.
+type NetworkSpec_PairSpec struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Count    uint32               `protobuf:"varint,1,opt,name=Count,proto3" json:"Count,omitempty"`
+	MinDelay *durationpb.Duration `protobuf:"bytes,2,opt,name=MinDelay,proto3" json:"MinDelay,omitempty"`
+	MaxDelay *durationpb.Duration `protobuf:"bytes,3,opt,name=MaxDelay,proto3" json:"MaxDelay,omitempty"`
+}
+
+func (x *NetworkSpec_PairSpec) Reset() {
+	*x = NetworkSpec_PairSpec{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_spec_proto_msgTypes[8]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkSpec_PairSpec) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkSpec_PairSpec) ProtoMessage() {}
+
+func (x *NetworkSpec_PairSpec) ProtoReflect() protoreflect.Message {
+	mi := &file_network_spec_proto_msgTypes[8]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkSpec_PairSpec.ProtoReflect.Descriptor instead.
+func (*NetworkSpec_PairSpec) Descriptor() ([]byte, []int) {
+	return file_network_spec_proto_rawDescGZIP(), []int{0, 7}
+}
+
+func (x *NetworkSpec_PairSpec) GetCount() uint32 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *NetworkSpec_PairSpec) GetMinDelay() *durationpb.Duration {
+	if x != nil {
+		return x.MinDelay
+	}
+	return nil
+}
+
+func (x *NetworkSpec_PairSpec) GetMaxDelay() *durationpb.Duration {
+	if x != nil {
+		return x.MaxDelay
+	}
+	return nil
+}
+
+// Each stop is served by the hub closest to it. Requires an Area.
+type NetworkSpec_HubAssignment_NearestHub struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+}
+
+func (x *NetworkSpec_HubAssignment_NearestHub) Reset() {
+	*x = NetworkSpec_HubAssignment_NearestHub{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_spec_proto_msgTypes[9]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkSpec_HubAssignment_NearestHub) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkSpec_HubAssignment_NearestHub) ProtoMessage() {}
+
+func (x *NetworkSpec_HubAssignment_NearestHub) ProtoReflect() protoreflect.Message {
+	mi := &file_network_spec_proto_msgTypes[9]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkSpec_HubAssignment_NearestHub.ProtoReflect.Descriptor instead.
+func (*NetworkSpec_HubAssignment_NearestHub) Descriptor() ([]byte, []int) {
+	return file_network_spec_proto_rawDescGZIP(), []int{0, 4, 0}
+}
+
+// Each stop is served by a single hub chosen at random, with probability proportional to the hub's weight.
+type NetworkSpec_HubAssignment_ProportionalHubs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Weights []float64 `protobuf:"fixed64,1,rep,packed,name=Weights,proto3" json:"Weights,omitempty"`
+}
+
+func (x *NetworkSpec_HubAssignment_ProportionalHubs) Reset() {
+	*x = NetworkSpec_HubAssignment_ProportionalHubs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_spec_proto_msgTypes[10]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkSpec_HubAssignment_ProportionalHubs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkSpec_HubAssignment_ProportionalHubs) ProtoMessage() {}
+
+func (x *NetworkSpec_HubAssignment_ProportionalHubs) ProtoReflect() protoreflect.Message {
+	mi := &file_network_spec_proto_msgTypes[10]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkSpec_HubAssignment_ProportionalHubs.ProtoReflect.Descriptor instead.
+func (*NetworkSpec_HubAssignment_ProportionalHubs) Descriptor() ([]byte, []int) {
+	return file_network_spec_proto_rawDescGZIP(), []int{0, 4, 1}
+}
+
+func (x *NetworkSpec_HubAssignment_ProportionalHubs) GetWeights() []float64 {
+	if x != nil {
+		return x.Weights
+	}
+	return nil
+}
+
+type NetworkSpec_HubAssignment_Membership struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Hub   uint32   `protobuf:"varint,1,opt,name=Hub,proto3" json:"Hub,omitempty"`
+	Stops []uint32 `protobuf:"varint,2,rep,packed,name=Stops,proto3" json:"Stops,omitempty"`
+}
+
+func (x *NetworkSpec_HubAssignment_Membership) Reset() {
+	*x = NetworkSpec_HubAssignment_Membership{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_spec_proto_msgTypes[11]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkSpec_HubAssignment_Membership) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkSpec_HubAssignment_Membership) ProtoMessage() {}
+
+func (x *NetworkSpec_HubAssignment_Membership) ProtoReflect() protoreflect.Message {
+	mi := &file_network_spec_proto_msgTypes[11]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkSpec_HubAssignment_Membership.ProtoReflect.Descriptor instead.
+func (*NetworkSpec_HubAssignment_Membership) Descriptor() ([]byte, []int) {
+	return file_network_spec_proto_rawDescGZIP(), []int{0, 4, 2}
 }
 
-type NetworkSpec_Gaussian struct {
-	Gaussian *NetworkSpec_GaussianDistro `protobuf:"bytes,4,opt,name=Gaussian,proto3,oneof"`
+func (x *NetworkSpec_HubAssignment_Membership) GetHub() uint32 {
+	if x != nil {
+		return x.Hub
+	}
+	return 0
 }
 
-func (*NetworkSpec_Uniform) isNetworkSpec_Distribution() {}
+func (x *NetworkSpec_HubAssignment_Membership) GetStops() []uint32 {
+	if x != nil {
+		return x.Stops
+	}
+	return nil
+}
 
-func (*NetworkSpec_Gaussian) isNetworkSpec_Distribution() {}
+// Each stop is served by the hubs listing it as a member. Stops no hub lists have no hub edges.
+type NetworkSpec_HubAssignment_ExplicitHubs struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
 
-type NetworkSpec_UniformDistro struct {
+	Members []*NetworkSpec_HubAssignment_Membership `protobuf:"bytes,1,rep,name=Members,proto3" json:"Members,omitempty"`
+}
+
+func (x *NetworkSpec_HubAssignment_ExplicitHubs) Reset() {
+	*x = NetworkSpec_HubAssignment_ExplicitHubs{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_spec_proto_msgTypes[12]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkSpec_HubAssignment_ExplicitHubs) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkSpec_HubAssignment_ExplicitHubs) ProtoMessage() {}
+
+func (x *NetworkSpec_HubAssignment_ExplicitHubs) ProtoReflect() protoreflect.Message {
+	mi := &file_network_spec_proto_msgTypes[12]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkSpec_HubAssignment_ExplicitHubs.ProtoReflect.Descriptor instead.
+func (*NetworkSpec_HubAssignment_ExplicitHubs) Descriptor() ([]byte, []int) {
+	return file_network_spec_proto_rawDescGZIP(), []int{0, 4, 3}
+}
+
+func (x *NetworkSpec_HubAssignment_ExplicitHubs) GetMembers() []*NetworkSpec_HubAssignment_Membership {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+type NetworkSpec_DemandSpec_FixedDemand struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
+
+	Value float64 `protobuf:"fixed64,1,opt,name=Value,proto3" json:"Value,omitempty"`
 }
 
-func (x *NetworkSpec_UniformDistro) Reset() {
-	*x = NetworkSpec_UniformDistro{}
+func (x *NetworkSpec_DemandSpec_FixedDemand) Reset() {
+	*x = NetworkSpec_DemandSpec_FixedDemand{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_network_spec_proto_msgTypes[1]
+		mi := &file_network_spec_proto_msgTypes[13]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *NetworkSpec_UniformDistro) String() string {
+func (x *NetworkSpec_DemandSpec_FixedDemand) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NetworkSpec_UniformDistro) ProtoMessage() {}
+func (*NetworkSpec_DemandSpec_FixedDemand) ProtoMessage() {}
 
-func (x *NetworkSpec_UniformDistro) ProtoReflect() protoreflect.Message {
-	mi := &file_network_spec_proto_msgTypes[1]
+func (x *NetworkSpec_DemandSpec_FixedDemand) ProtoReflect() protoreflect.Message {
+	mi := &file_network_spec_proto_msgTypes[13]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -184,37 +1094,44 @@ func (x *NetworkSpec_UniformDistro) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NetworkSpec_UniformDistro.ProtoReflect.Descriptor instead.
-func (*NetworkSpec_UniformDistro) Descriptor() ([]byte, []int) {
-	return file_network_spec_proto_rawDescGZIP(), []int{0, 0}
+// Deprecated: Use NetworkSpec_DemandSpec_FixedDemand.ProtoReflect.Descriptor instead.
+func (*NetworkSpec_DemandSpec_FixedDemand) Descriptor() ([]byte, []int) {
+	return file_network_spec_proto_rawDescGZIP(), []int{0, 6, 0}
 }
 
-type NetworkSpec_GaussianDistro struct {
+func (x *NetworkSpec_DemandSpec_FixedDemand) GetValue() float64 {
+	if x != nil {
+		return x.Value
+	}
+	return 0
+}
+
+type NetworkSpec_DemandSpec_UniformDemand struct {
 	state         protoimpl.MessageState
 	sizeCache     protoimpl.SizeCache
 	unknownFields protoimpl.UnknownFields
 
-	Mean   int64 `protobuf:"varint,1,opt,name=Mean,proto3" json:"Mean,omitempty"`
-	StdDev int64 `protobuf:"varint,2,opt,name=StdDev,proto3" json:"StdDev,omitempty"`
+	Min float64 `protobuf:"fixed64,1,opt,name=Min,proto3" json:"Min,omitempty"`
+	Max float64 `protobuf:"fixed64,2,opt,name=Max,proto3" json:"Max,omitempty"`
 }
 
-func (x *NetworkSpec_GaussianDistro) Reset() {
-	*x = NetworkSpec_GaussianDistro{}
+func (x *NetworkSpec_DemandSpec_UniformDemand) Reset() {
+	*x = NetworkSpec_DemandSpec_UniformDemand{}
 	if protoimpl.UnsafeEnabled {
-		mi := &file_network_spec_proto_msgTypes[2]
+		mi := &file_network_spec_proto_msgTypes[14]
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		ms.StoreMessageInfo(mi)
 	}
 }
 
-func (x *NetworkSpec_GaussianDistro) String() string {
+func (x *NetworkSpec_DemandSpec_UniformDemand) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*NetworkSpec_GaussianDistro) ProtoMessage() {}
+func (*NetworkSpec_DemandSpec_UniformDemand) ProtoMessage() {}
 
-func (x *NetworkSpec_GaussianDistro) ProtoReflect() protoreflect.Message {
-	mi := &file_network_spec_proto_msgTypes[2]
+func (x *NetworkSpec_DemandSpec_UniformDemand) ProtoReflect() protoreflect.Message {
+	mi := &file_network_spec_proto_msgTypes[14]
 	if protoimpl.UnsafeEnabled && x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -225,21 +1142,68 @@ func (x *NetworkSpec_GaussianDistro) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use NetworkSpec_GaussianDistro.ProtoReflect.Descriptor instead.
-func (*NetworkSpec_GaussianDistro) Descriptor() ([]byte, []int) {
-	return file_network_spec_proto_rawDescGZIP(), []int{0, 1}
+// Deprecated: Use NetworkSpec_DemandSpec_UniformDemand.ProtoReflect.Descriptor instead.
+func (*NetworkSpec_DemandSpec_UniformDemand) Descriptor() ([]byte, []int) {
+	return file_network_spec_proto_rawDescGZIP(), []int{0, 6, 1}
 }
 
-func (x *NetworkSpec_GaussianDistro) GetMean() int64 {
+func (x *NetworkSpec_DemandSpec_UniformDemand) GetMin() float64 {
 	if x != nil {
-		return x.Mean
+		return x.Min
 	}
 	return 0
 }
 
-func (x *NetworkSpec_GaussianDistro) GetStdDev() int64 {
+func (x *NetworkSpec_DemandSpec_UniformDemand) GetMax() float64 {
 	if x != nil {
-		return x.StdDev
+		return x.Max
+	}
+	return 0
+}
+
+type NetworkSpec_DemandSpec_PoissonDemand struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Mean float64 `protobuf:"fixed64,1,opt,name=Mean,proto3" json:"Mean,omitempty"`
+}
+
+func (x *NetworkSpec_DemandSpec_PoissonDemand) Reset() {
+	*x = NetworkSpec_DemandSpec_PoissonDemand{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_network_spec_proto_msgTypes[15]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *NetworkSpec_DemandSpec_PoissonDemand) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NetworkSpec_DemandSpec_PoissonDemand) ProtoMessage() {}
+
+func (x *NetworkSpec_DemandSpec_PoissonDemand) ProtoReflect() protoreflect.Message {
+	mi := &file_network_spec_proto_msgTypes[15]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NetworkSpec_DemandSpec_PoissonDemand.ProtoReflect.Descriptor instead.
+func (*NetworkSpec_DemandSpec_PoissonDemand) Descriptor() ([]byte, []int) {
+	return file_network_spec_proto_rawDescGZIP(), []int{0, 6, 2}
+}
+
+func (x *NetworkSpec_DemandSpec_PoissonDemand) GetMean() float64 {
+	if x != nil {
+		return x.Mean
 	}
 	return 0
 }
@@ -253,7 +1217,7 @@ var file_network_spec_proto_rawDesc = []byte{
 	0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x1a,
 	0x1e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66,
 	0x2f, 0x64, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22,
-	0xeb, 0x03, 0x0a, 0x0b, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x70, 0x65, 0x63, 0x12,
+	0xa2, 0x15, 0x0a, 0x0b, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x70, 0x65, 0x63, 0x12,
 	0x12, 0x0a, 0x04, 0x48, 0x75, 0x62, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x04, 0x48,
 	0x75, 0x62, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x74, 0x6f, 0x70, 0x73, 0x18, 0x02, 0x20, 0x01,
 	0x28, 0x0d, 0x52, 0x05, 0x53, 0x74, 0x6f, 0x70, 0x73, 0x12, 0x3f, 0x0a, 0x07, 0x55, 0x6e, 0x69,
@@ -277,16 +1241,155 @@ var file_network_spec_proto_rawDesc = []byte{
 	0x6f, 0x72, 0x74, 0x45, 0x64, 0x67, 0x65, 0x12, 0x35, 0x0a, 0x08, 0x4c, 0x6f, 0x6e, 0x67, 0x45,
 	0x64, 0x67, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67,
 	0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61,
-	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x4c, 0x6f, 0x6e, 0x67, 0x45, 0x64, 0x67, 0x65, 0x1a, 0x0f,
-	0x0a, 0x0d, 0x55, 0x6e, 0x69, 0x66, 0x6f, 0x72, 0x6d, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x1a,
-	0x3c, 0x0a, 0x0e, 0x47, 0x61, 0x75, 0x73, 0x73, 0x69, 0x61, 0x6e, 0x44, 0x69, 0x73, 0x74, 0x72,
-	0x6f, 0x12, 0x12, 0x0a, 0x04, 0x4d, 0x65, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x52,
-	0x04, 0x4d, 0x65, 0x61, 0x6e, 0x12, 0x16, 0x0a, 0x06, 0x53, 0x74, 0x64, 0x44, 0x65, 0x76, 0x18,
-	0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x06, 0x53, 0x74, 0x64, 0x44, 0x65, 0x76, 0x42, 0x0e, 0x0a,
-	0x0c, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x42, 0x1d, 0x5a,
-	0x1b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x62, 0x64, 0x73, 0x68,
-	0x72, 0x6f, 0x79, 0x65, 0x72, 0x2f, 0x62, 0x75, 0x72, 0x72, 0x6f, 0x77, 0x62, 0x06, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x33,
+	0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x4c, 0x6f, 0x6e, 0x67, 0x45, 0x64, 0x67, 0x65, 0x12, 0x31,
+	0x0a, 0x04, 0x44, 0x61, 0x79, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x1d, 0x2e, 0x74,
+	0x75, 0x74, 0x6f, 0x72, 0x69, 0x61, 0x6c, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53,
+	0x70, 0x65, 0x63, 0x2e, 0x44, 0x61, 0x79, 0x53, 0x70, 0x65, 0x63, 0x52, 0x04, 0x44, 0x61, 0x79,
+	0x73, 0x12, 0x3b, 0x0a, 0x0b, 0x44, 0x61, 0x79, 0x42, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x72, 0x79,
+	0x18, 0x0a, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x52, 0x0b, 0x44, 0x61, 0x79, 0x42, 0x6f, 0x75, 0x6e, 0x64, 0x61, 0x72, 0x79, 0x12, 0x43,
+	0x0a, 0x0a, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x18, 0x0b, 0x20, 0x01,
+	0x28, 0x0b, 0x32, 0x23, 0x2e, 0x74, 0x75, 0x74, 0x6f, 0x72, 0x69, 0x61, 0x6c, 0x2e, 0x4e, 0x65,
+	0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x70, 0x65, 0x63, 0x2e, 0x48, 0x75, 0x62, 0x41, 0x73, 0x73,
+	0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x52, 0x0a, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d,
+	0x65, 0x6e, 0x74, 0x12, 0x35, 0x0a, 0x04, 0x41, 0x72, 0x65, 0x61, 0x18, 0x0c, 0x20, 0x01, 0x28,
+	0x0b, 0x32, 0x21, 0x2e, 0x74, 0x75, 0x74, 0x6f, 0x72, 0x69, 0x61, 0x6c, 0x2e, 0x4e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x53, 0x70, 0x65, 0x63, 0x2e, 0x4c, 0x6f, 0x63, 0x61, 0x74, 0x69, 0x6f,
+	0x6e, 0x42, 0x6f, 0x78, 0x52, 0x04, 0x41, 0x72, 0x65, 0x61, 0x12, 0x43, 0x0a, 0x0d, 0x48, 0x75,
+	0x62, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x18, 0x0d, 0x20, 0x03, 0x28,
+	0x0b, 0x32, 0x1d, 0x2e, 0x74, 0x75, 0x74, 0x6f, 0x72, 0x69, 0x61, 0x6c, 0x2e, 0x4e, 0x65, 0x74,
+	0x77, 0x6f, 0x72, 0x6b, 0x53, 0x70, 0x65, 0x63, 0x2e, 0x48, 0x75, 0x62, 0x53, 0x70, 0x65, 0x63,
+	0x52, 0x0d, 0x48, 0x75, 0x62, 0x41, 0x74, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74, 0x65, 0x73, 0x12,
+	0x38, 0x0a, 0x06, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x20, 0x2e, 0x74, 0x75, 0x74, 0x6f, 0x72, 0x69, 0x61, 0x6c, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x53, 0x70, 0x65, 0x63, 0x2e, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x70, 0x65,
+	0x63, 0x52, 0x06, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x34, 0x0a, 0x05, 0x50, 0x61, 0x69,
+	0x72, 0x73, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1e, 0x2e, 0x74, 0x75, 0x74, 0x6f, 0x72,
+	0x69, 0x61, 0x6c, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x70, 0x65, 0x63, 0x2e,
+	0x50, 0x61, 0x69, 0x72, 0x53, 0x70, 0x65, 0x63, 0x52, 0x05, 0x50, 0x61, 0x69, 0x72, 0x73, 0x12,
+	0x24, 0x0a, 0x0d, 0x43, 0x6c, 0x61, 0x6d, 0x70, 0x54, 0x6f, 0x57, 0x69, 0x6e, 0x64, 0x6f, 0x77,
+	0x18, 0x10, 0x20, 0x01, 0x28, 0x08, 0x52, 0x0d, 0x43, 0x6c, 0x61, 0x6d, 0x70, 0x54, 0x6f, 0x57,
+	0x69, 0x6e, 0x64, 0x6f, 0x77, 0x12, 0x35, 0x0a, 0x08, 0x4c, 0x69, 0x6e, 0x65, 0x48, 0x61, 0x75,
+	0x6c, 0x18, 0x11, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x52, 0x08, 0x4c, 0x69, 0x6e, 0x65, 0x48, 0x61, 0x75, 0x6c, 0x1a, 0x0f, 0x0a, 0x0d,
+	0x55, 0x6e, 0x69, 0x66, 0x6f, 0x72, 0x6d, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x1a, 0xd1, 0x01,
+	0x0a, 0x0e, 0x47, 0x61, 0x75, 0x73, 0x73, 0x69, 0x61, 0x6e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f,
+	0x12, 0x16, 0x0a, 0x04, 0x4d, 0x65, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x03, 0x42, 0x02,
+	0x18, 0x01, 0x52, 0x04, 0x4d, 0x65, 0x61, 0x6e, 0x12, 0x1a, 0x0a, 0x06, 0x53, 0x74, 0x64, 0x44,
+	0x65, 0x76, 0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x42, 0x02, 0x18, 0x01, 0x52, 0x06, 0x53, 0x74,
+	0x64, 0x44, 0x65, 0x76, 0x12, 0x36, 0x0a, 0x08, 0x4d, 0x65, 0x61, 0x6e, 0x54, 0x69, 0x6d, 0x65,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e,
+	0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61,
+	0x6d, 0x70, 0x52, 0x08, 0x4d, 0x65, 0x61, 0x6e, 0x54, 0x69, 0x6d, 0x65, 0x12, 0x37, 0x0a, 0x09,
+	0x44, 0x65, 0x76, 0x69, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x09, 0x44, 0x65, 0x76, 0x69,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1a, 0x0a, 0x08, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74,
+	0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x08, 0x52, 0x08, 0x54, 0x72, 0x75, 0x6e, 0x63, 0x61, 0x74,
+	0x65, 0x1a, 0xb4, 0x02, 0x0a, 0x07, 0x44, 0x61, 0x79, 0x53, 0x70, 0x65, 0x63, 0x12, 0x14, 0x0a,
+	0x05, 0x53, 0x74, 0x6f, 0x70, 0x73, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x53, 0x74,
+	0x6f, 0x70, 0x73, 0x12, 0x3f, 0x0a, 0x07, 0x55, 0x6e, 0x69, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x02,
+	0x20, 0x01, 0x28, 0x0b, 0x32, 0x23, 0x2e, 0x74, 0x75, 0x74, 0x6f, 0x72, 0x69, 0x61, 0x6c, 0x2e,
+	0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x70, 0x65, 0x63, 0x2e, 0x55, 0x6e, 0x69, 0x66,
+	0x6f, 0x72, 0x6d, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x48, 0x00, 0x52, 0x07, 0x55, 0x6e, 0x69,
+	0x66, 0x6f, 0x72, 0x6d, 0x12, 0x42, 0x0a, 0x08, 0x47, 0x61, 0x75, 0x73, 0x73, 0x69, 0x61, 0x6e,
+	0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x24, 0x2e, 0x74, 0x75, 0x74, 0x6f, 0x72, 0x69, 0x61,
+	0x6c, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x70, 0x65, 0x63, 0x2e, 0x47, 0x61,
+	0x75, 0x73, 0x73, 0x69, 0x61, 0x6e, 0x44, 0x69, 0x73, 0x74, 0x72, 0x6f, 0x48, 0x00, 0x52, 0x08,
+	0x47, 0x61, 0x75, 0x73, 0x73, 0x69, 0x61, 0x6e, 0x12, 0x30, 0x0a, 0x05, 0x73, 0x74, 0x61, 0x72,
+	0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x05, 0x73, 0x74, 0x61, 0x72, 0x74, 0x12, 0x2c, 0x0a, 0x03, 0x65, 0x6e,
+	0x64, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x54, 0x69, 0x6d, 0x65, 0x73, 0x74,
+	0x61, 0x6d, 0x70, 0x52, 0x03, 0x65, 0x6e, 0x64, 0x12, 0x1e, 0x0a, 0x0a, 0x41, 0x63, 0x74, 0x69,
+	0x76, 0x65, 0x48, 0x75, 0x62, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x0d, 0x52, 0x0a, 0x41, 0x63,
+	0x74, 0x69, 0x76, 0x65, 0x48, 0x75, 0x62, 0x73, 0x42, 0x0e, 0x0a, 0x0c, 0x44, 0x69, 0x73, 0x74,
+	0x72, 0x69, 0x62, 0x75, 0x74, 0x69, 0x6f, 0x6e, 0x1a, 0x61, 0x0a, 0x0b, 0x4c, 0x6f, 0x63, 0x61,
+	0x74, 0x69, 0x6f, 0x6e, 0x42, 0x6f, 0x78, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x6f, 0x75, 0x74, 0x68,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x05, 0x53, 0x6f, 0x75, 0x74, 0x68, 0x12, 0x12, 0x0a,
+	0x04, 0x57, 0x65, 0x73, 0x74, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x57, 0x65, 0x73,
+	0x74, 0x12, 0x14, 0x0a, 0x05, 0x4e, 0x6f, 0x72, 0x74, 0x68, 0x18, 0x03, 0x20, 0x01, 0x28, 0x01,
+	0x52, 0x05, 0x4e, 0x6f, 0x72, 0x74, 0x68, 0x12, 0x12, 0x0a, 0x04, 0x45, 0x61, 0x73, 0x74, 0x18,
+	0x04, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x45, 0x61, 0x73, 0x74, 0x1a, 0xdb, 0x03, 0x0a, 0x0d,
+	0x48, 0x75, 0x62, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x12, 0x4a, 0x0a,
+	0x07, 0x4e, 0x65, 0x61, 0x72, 0x65, 0x73, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e,
+	0x2e, 0x74, 0x75, 0x74, 0x6f, 0x72, 0x69, 0x61, 0x6c, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72,
+	0x6b, 0x53, 0x70, 0x65, 0x63, 0x2e, 0x48, 0x75, 0x62, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d,
+	0x65, 0x6e, 0x74, 0x2e, 0x4e, 0x65, 0x61, 0x72, 0x65, 0x73, 0x74, 0x48, 0x75, 0x62, 0x48, 0x00,
+	0x52, 0x07, 0x4e, 0x65, 0x61, 0x72, 0x65, 0x73, 0x74, 0x12, 0x5a, 0x0a, 0x0c, 0x50, 0x72, 0x6f,
+	0x70, 0x6f, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x34, 0x2e, 0x74, 0x75, 0x74, 0x6f, 0x72, 0x69, 0x61, 0x6c, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f,
+	0x72, 0x6b, 0x53, 0x70, 0x65, 0x63, 0x2e, 0x48, 0x75, 0x62, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e,
+	0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x72, 0x74, 0x69, 0x6f, 0x6e, 0x61,
+	0x6c, 0x48, 0x75, 0x62, 0x73, 0x48, 0x00, 0x52, 0x0c, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x72, 0x74,
+	0x69, 0x6f, 0x6e, 0x61, 0x6c, 0x12, 0x4e, 0x0a, 0x08, 0x45, 0x78, 0x70, 0x6c, 0x69, 0x63, 0x69,
+	0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x30, 0x2e, 0x74, 0x75, 0x74, 0x6f, 0x72, 0x69,
+	0x61, 0x6c, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x70, 0x65, 0x63, 0x2e, 0x48,
+	0x75, 0x62, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x45, 0x78, 0x70,
+	0x6c, 0x69, 0x63, 0x69, 0x74, 0x48, 0x75, 0x62, 0x73, 0x48, 0x00, 0x52, 0x08, 0x45, 0x78, 0x70,
+	0x6c, 0x69, 0x63, 0x69, 0x74, 0x1a, 0x0c, 0x0a, 0x0a, 0x4e, 0x65, 0x61, 0x72, 0x65, 0x73, 0x74,
+	0x48, 0x75, 0x62, 0x1a, 0x2c, 0x0a, 0x10, 0x50, 0x72, 0x6f, 0x70, 0x6f, 0x72, 0x74, 0x69, 0x6f,
+	0x6e, 0x61, 0x6c, 0x48, 0x75, 0x62, 0x73, 0x12, 0x18, 0x0a, 0x07, 0x57, 0x65, 0x69, 0x67, 0x68,
+	0x74, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x01, 0x52, 0x07, 0x57, 0x65, 0x69, 0x67, 0x68, 0x74,
+	0x73, 0x1a, 0x34, 0x0a, 0x0a, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x12,
+	0x10, 0x0a, 0x03, 0x48, 0x75, 0x62, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x03, 0x48, 0x75,
+	0x62, 0x12, 0x14, 0x0a, 0x05, 0x53, 0x74, 0x6f, 0x70, 0x73, 0x18, 0x02, 0x20, 0x03, 0x28, 0x0d,
+	0x52, 0x05, 0x53, 0x74, 0x6f, 0x70, 0x73, 0x1a, 0x58, 0x0a, 0x0c, 0x45, 0x78, 0x70, 0x6c, 0x69,
+	0x63, 0x69, 0x74, 0x48, 0x75, 0x62, 0x73, 0x12, 0x48, 0x0a, 0x07, 0x4d, 0x65, 0x6d, 0x62, 0x65,
+	0x72, 0x73, 0x18, 0x01, 0x20, 0x03, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x74, 0x75, 0x74, 0x6f, 0x72,
+	0x69, 0x61, 0x6c, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x70, 0x65, 0x63, 0x2e,
+	0x48, 0x75, 0x62, 0x41, 0x73, 0x73, 0x69, 0x67, 0x6e, 0x6d, 0x65, 0x6e, 0x74, 0x2e, 0x4d, 0x65,
+	0x6d, 0x62, 0x65, 0x72, 0x73, 0x68, 0x69, 0x70, 0x52, 0x07, 0x4d, 0x65, 0x6d, 0x62, 0x65, 0x72,
+	0x73, 0x42, 0x06, 0x0a, 0x04, 0x4d, 0x6f, 0x64, 0x65, 0x1a, 0x9b, 0x01, 0x0a, 0x07, 0x48, 0x75,
+	0x62, 0x53, 0x70, 0x65, 0x63, 0x12, 0x2d, 0x0a, 0x04, 0x4f, 0x70, 0x65, 0x6e, 0x18, 0x01, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x04,
+	0x4f, 0x70, 0x65, 0x6e, 0x12, 0x2f, 0x0a, 0x05, 0x43, 0x6c, 0x6f, 0x73, 0x65, 0x18, 0x02, 0x20,
+	0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f,
+	0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x05,
+	0x43, 0x6c, 0x6f, 0x73, 0x65, 0x12, 0x14, 0x0a, 0x05, 0x46, 0x6c, 0x65, 0x65, 0x74, 0x18, 0x03,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x46, 0x6c, 0x65, 0x65, 0x74, 0x12, 0x1a, 0x0a, 0x08, 0x43,
+	0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x08, 0x43,
+	0x61, 0x70, 0x61, 0x63, 0x69, 0x74, 0x79, 0x1a, 0xf9, 0x02, 0x0a, 0x0a, 0x44, 0x65, 0x6d, 0x61,
+	0x6e, 0x64, 0x53, 0x70, 0x65, 0x63, 0x12, 0x44, 0x0a, 0x05, 0x46, 0x69, 0x78, 0x65, 0x64, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2c, 0x2e, 0x74, 0x75, 0x74, 0x6f, 0x72, 0x69, 0x61, 0x6c,
+	0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x70, 0x65, 0x63, 0x2e, 0x44, 0x65, 0x6d,
+	0x61, 0x6e, 0x64, 0x53, 0x70, 0x65, 0x63, 0x2e, 0x46, 0x69, 0x78, 0x65, 0x64, 0x44, 0x65, 0x6d,
+	0x61, 0x6e, 0x64, 0x48, 0x00, 0x52, 0x05, 0x46, 0x69, 0x78, 0x65, 0x64, 0x12, 0x4a, 0x0a, 0x07,
+	0x55, 0x6e, 0x69, 0x66, 0x6f, 0x72, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e,
+	0x74, 0x75, 0x74, 0x6f, 0x72, 0x69, 0x61, 0x6c, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b,
+	0x53, 0x70, 0x65, 0x63, 0x2e, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x70, 0x65, 0x63, 0x2e,
+	0x55, 0x6e, 0x69, 0x66, 0x6f, 0x72, 0x6d, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x48, 0x00, 0x52,
+	0x07, 0x55, 0x6e, 0x69, 0x66, 0x6f, 0x72, 0x6d, 0x12, 0x4a, 0x0a, 0x07, 0x50, 0x6f, 0x69, 0x73,
+	0x73, 0x6f, 0x6e, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x2e, 0x2e, 0x74, 0x75, 0x74, 0x6f,
+	0x72, 0x69, 0x61, 0x6c, 0x2e, 0x4e, 0x65, 0x74, 0x77, 0x6f, 0x72, 0x6b, 0x53, 0x70, 0x65, 0x63,
+	0x2e, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x53, 0x70, 0x65, 0x63, 0x2e, 0x50, 0x6f, 0x69, 0x73,
+	0x73, 0x6f, 0x6e, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x48, 0x00, 0x52, 0x07, 0x50, 0x6f, 0x69,
+	0x73, 0x73, 0x6f, 0x6e, 0x1a, 0x23, 0x0a, 0x0b, 0x46, 0x69, 0x78, 0x65, 0x64, 0x44, 0x65, 0x6d,
+	0x61, 0x6e, 0x64, 0x12, 0x14, 0x0a, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x18, 0x01, 0x20, 0x01,
+	0x28, 0x01, 0x52, 0x05, 0x56, 0x61, 0x6c, 0x75, 0x65, 0x1a, 0x33, 0x0a, 0x0d, 0x55, 0x6e, 0x69,
+	0x66, 0x6f, 0x72, 0x6d, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x12, 0x10, 0x0a, 0x03, 0x4d, 0x69,
+	0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x4d, 0x69, 0x6e, 0x12, 0x10, 0x0a, 0x03,
+	0x4d, 0x61, 0x78, 0x18, 0x02, 0x20, 0x01, 0x28, 0x01, 0x52, 0x03, 0x4d, 0x61, 0x78, 0x1a, 0x23,
+	0x0a, 0x0d, 0x50, 0x6f, 0x69, 0x73, 0x73, 0x6f, 0x6e, 0x44, 0x65, 0x6d, 0x61, 0x6e, 0x64, 0x12,
+	0x12, 0x0a, 0x04, 0x4d, 0x65, 0x61, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x01, 0x52, 0x04, 0x4d,
+	0x65, 0x61, 0x6e, 0x42, 0x0e, 0x0a, 0x0c, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75, 0x74,
+	0x69, 0x6f, 0x6e, 0x1a, 0x8e, 0x01, 0x0a, 0x08, 0x50, 0x61, 0x69, 0x72, 0x53, 0x70, 0x65, 0x63,
+	0x12, 0x14, 0x0a, 0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52,
+	0x05, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x35, 0x0a, 0x08, 0x4d, 0x69, 0x6e, 0x44, 0x65, 0x6c,
+	0x61, 0x79, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c,
+	0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75, 0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x52, 0x08, 0x4d, 0x69, 0x6e, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x12, 0x35, 0x0a,
+	0x08, 0x4d, 0x61, 0x78, 0x44, 0x65, 0x6c, 0x61, 0x79, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0b, 0x32,
+	0x19, 0x2e, 0x67, 0x6f, 0x6f, 0x67, 0x6c, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x75,
+	0x66, 0x2e, 0x44, 0x75, 0x72, 0x61, 0x74, 0x69, 0x6f, 0x6e, 0x52, 0x08, 0x4d, 0x61, 0x78, 0x44,
+	0x65, 0x6c, 0x61, 0x79, 0x42, 0x0e, 0x0a, 0x0c, 0x44, 0x69, 0x73, 0x74, 0x72, 0x69, 0x62, 0x75,
+	0x74, 0x69, 0x6f, 0x6e, 0x42, 0x1d, 0x5a, 0x1b, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63,
+	0x6f, 0x6d, 0x2f, 0x62, 0x64, 0x73, 0x68, 0x72, 0x6f, 0x79, 0x65, 0x72, 0x2f, 0x62, 0x75, 0x72,
+	0x72, 0x6f, 0x77, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
 }
 
 var (
@@ -301,26 +1404,64 @@ func file_network_spec_proto_rawDescGZIP() []byte {
 	return file_network_spec_proto_rawDescData
 }
 
-var file_network_spec_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_network_spec_proto_msgTypes = make([]protoimpl.MessageInfo, 16)
 var file_network_spec_proto_goTypes = []interface{}{
-	(*NetworkSpec)(nil),                // 0: tutorial.NetworkSpec
-	(*NetworkSpec_UniformDistro)(nil),  // 1: tutorial.NetworkSpec.UniformDistro
-	(*NetworkSpec_GaussianDistro)(nil), // 2: tutorial.NetworkSpec.GaussianDistro
-	(*timestamppb.Timestamp)(nil),      // 3: google.protobuf.Timestamp
-	(*durationpb.Duration)(nil),        // 4: google.protobuf.Duration
+	(*NetworkSpec)(nil),                                // 0: tutorial.NetworkSpec
+	(*NetworkSpec_UniformDistro)(nil),                  // 1: tutorial.NetworkSpec.UniformDistro
+	(*NetworkSpec_GaussianDistro)(nil),                 // 2: tutorial.NetworkSpec.GaussianDistro
+	(*NetworkSpec_DaySpec)(nil),                        // 3: tutorial.NetworkSpec.DaySpec
+	(*NetworkSpec_LocationBox)(nil),                    // 4: tutorial.NetworkSpec.LocationBox
+	(*NetworkSpec_HubAssignment)(nil),                  // 5: tutorial.NetworkSpec.HubAssignment
+	(*NetworkSpec_HubSpec)(nil),                        // 6: tutorial.NetworkSpec.HubSpec
+	(*NetworkSpec_DemandSpec)(nil),                     // 7: tutorial.NetworkSpec.DemandSpec
+	(*NetworkSpec_PairSpec)(nil),                       // 8: tutorial.NetworkSpec.PairSpec
+	(*NetworkSpec_HubAssignment_NearestHub)(nil),       // 9: tutorial.NetworkSpec.HubAssignment.NearestHub
+	(*NetworkSpec_HubAssignment_ProportionalHubs)(nil), // 10: tutorial.NetworkSpec.HubAssignment.ProportionalHubs
+	(*NetworkSpec_HubAssignment_Membership)(nil),       // 11: tutorial.NetworkSpec.HubAssignment.Membership
+	(*NetworkSpec_HubAssignment_ExplicitHubs)(nil),     // 12: tutorial.NetworkSpec.HubAssignment.ExplicitHubs
+	(*NetworkSpec_DemandSpec_FixedDemand)(nil),         // 13: tutorial.NetworkSpec.DemandSpec.FixedDemand
+	(*NetworkSpec_DemandSpec_UniformDemand)(nil),       // 14: tutorial.NetworkSpec.DemandSpec.UniformDemand
+	(*NetworkSpec_DemandSpec_PoissonDemand)(nil),       // 15: tutorial.NetworkSpec.DemandSpec.PoissonDemand
+	(*timestamppb.Timestamp)(nil),                      // 16: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),                        // 17: google.protobuf.Duration
 }
 var file_network_spec_proto_depIdxs = []int32{
-	1, // 0: tutorial.NetworkSpec.Uniform:type_name -> tutorial.NetworkSpec.UniformDistro
-	2, // 1: tutorial.NetworkSpec.Gaussian:type_name -> tutorial.NetworkSpec.GaussianDistro
-	3, // 2: tutorial.NetworkSpec.start:type_name -> google.protobuf.Timestamp
-	3, // 3: tutorial.NetworkSpec.end:type_name -> google.protobuf.Timestamp
-	4, // 4: tutorial.NetworkSpec.ShortEdge:type_name -> google.protobuf.Duration
-	4, // 5: tutorial.NetworkSpec.LongEdge:type_name -> google.protobuf.Duration
-	6, // [6:6] is the sub-list for method output_type
-	6, // [6:6] is the sub-list for method input_type
-	6, // [6:6] is the sub-list for extension type_name
-	6, // [6:6] is the sub-list for extension extendee
-	0, // [0:6] is the sub-list for field type_name
+	1,  // 0: tutorial.NetworkSpec.Uniform:type_name -> tutorial.NetworkSpec.UniformDistro
+	2,  // 1: tutorial.NetworkSpec.Gaussian:type_name -> tutorial.NetworkSpec.GaussianDistro
+	16, // 2: tutorial.NetworkSpec.start:type_name -> google.protobuf.Timestamp
+	16, // 3: tutorial.NetworkSpec.end:type_name -> google.protobuf.Timestamp
+	17, // 4: tutorial.NetworkSpec.ShortEdge:type_name -> google.protobuf.Duration
+	17, // 5: tutorial.NetworkSpec.LongEdge:type_name -> google.protobuf.Duration
+	3,  // 6: tutorial.NetworkSpec.Days:type_name -> tutorial.NetworkSpec.DaySpec
+	17, // 7: tutorial.NetworkSpec.DayBoundary:type_name -> google.protobuf.Duration
+	5,  // 8: tutorial.NetworkSpec.Assignment:type_name -> tutorial.NetworkSpec.HubAssignment
+	4,  // 9: tutorial.NetworkSpec.Area:type_name -> tutorial.NetworkSpec.LocationBox
+	6,  // 10: tutorial.NetworkSpec.HubAttributes:type_name -> tutorial.NetworkSpec.HubSpec
+	7,  // 11: tutorial.NetworkSpec.Demand:type_name -> tutorial.NetworkSpec.DemandSpec
+	8,  // 12: tutorial.NetworkSpec.Pairs:type_name -> tutorial.NetworkSpec.PairSpec
+	17, // 13: tutorial.NetworkSpec.LineHaul:type_name -> google.protobuf.Duration
+	16, // 14: tutorial.NetworkSpec.GaussianDistro.MeanTime:type_name -> google.protobuf.Timestamp
+	17, // 15: tutorial.NetworkSpec.GaussianDistro.Deviation:type_name -> google.protobuf.Duration
+	1,  // 16: tutorial.NetworkSpec.DaySpec.Uniform:type_name -> tutorial.NetworkSpec.UniformDistro
+	2,  // 17: tutorial.NetworkSpec.DaySpec.Gaussian:type_name -> tutorial.NetworkSpec.GaussianDistro
+	16, // 18: tutorial.NetworkSpec.DaySpec.start:type_name -> google.protobuf.Timestamp
+	16, // 19: tutorial.NetworkSpec.DaySpec.end:type_name -> google.protobuf.Timestamp
+	9,  // 20: tutorial.NetworkSpec.HubAssignment.Nearest:type_name -> tutorial.NetworkSpec.HubAssignment.NearestHub
+	10, // 21: tutorial.NetworkSpec.HubAssignment.Proportional:type_name -> tutorial.NetworkSpec.HubAssignment.ProportionalHubs
+	12, // 22: tutorial.NetworkSpec.HubAssignment.Explicit:type_name -> tutorial.NetworkSpec.HubAssignment.ExplicitHubs
+	17, // 23: tutorial.NetworkSpec.HubSpec.Open:type_name -> google.protobuf.Duration
+	17, // 24: tutorial.NetworkSpec.HubSpec.Close:type_name -> google.protobuf.Duration
+	13, // 25: tutorial.NetworkSpec.DemandSpec.Fixed:type_name -> tutorial.NetworkSpec.DemandSpec.FixedDemand
+	14, // 26: tutorial.NetworkSpec.DemandSpec.Uniform:type_name -> tutorial.NetworkSpec.DemandSpec.UniformDemand
+	15, // 27: tutorial.NetworkSpec.DemandSpec.Poisson:type_name -> tutorial.NetworkSpec.DemandSpec.PoissonDemand
+	17, // 28: tutorial.NetworkSpec.PairSpec.MinDelay:type_name -> google.protobuf.Duration
+	17, // 29: tutorial.NetworkSpec.PairSpec.MaxDelay:type_name -> google.protobuf.Duration
+	11, // 30: tutorial.NetworkSpec.HubAssignment.ExplicitHubs.Members:type_name -> tutorial.NetworkSpec.HubAssignment.Membership
+	31, // [31:31] is the sub-list for method output_type
+	31, // [31:31] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
 }
 
 func init() { file_network_spec_proto_init() }
@@ -365,18 +1506,188 @@ func file_network_spec_proto_init() {
 				return nil
 			}
 		}
+		file_network_spec_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NetworkSpec_DaySpec); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_spec_proto_msgTypes[4].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NetworkSpec_LocationBox); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_spec_proto_msgTypes[5].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NetworkSpec_HubAssignment); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_spec_proto_msgTypes[6].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NetworkSpec_HubSpec); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_spec_proto_msgTypes[7].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NetworkSpec_DemandSpec); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_spec_proto_msgTypes[8].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NetworkSpec_PairSpec); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_spec_proto_msgTypes[9].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NetworkSpec_HubAssignment_NearestHub); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_spec_proto_msgTypes[10].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NetworkSpec_HubAssignment_ProportionalHubs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_spec_proto_msgTypes[11].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NetworkSpec_HubAssignment_Membership); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_spec_proto_msgTypes[12].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NetworkSpec_HubAssignment_ExplicitHubs); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_spec_proto_msgTypes[13].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NetworkSpec_DemandSpec_FixedDemand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_spec_proto_msgTypes[14].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NetworkSpec_DemandSpec_UniformDemand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_network_spec_proto_msgTypes[15].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*NetworkSpec_DemandSpec_PoissonDemand); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
 	}
 	file_network_spec_proto_msgTypes[0].OneofWrappers = []interface{}{
 		(*NetworkSpec_Uniform)(nil),
 		(*NetworkSpec_Gaussian)(nil),
 	}
+	file_network_spec_proto_msgTypes[3].OneofWrappers = []interface{}{
+		(*NetworkSpec_DaySpec_Uniform)(nil),
+		(*NetworkSpec_DaySpec_Gaussian)(nil),
+	}
+	file_network_spec_proto_msgTypes[5].OneofWrappers = []interface{}{
+		(*NetworkSpec_HubAssignment_Nearest)(nil),
+		(*NetworkSpec_HubAssignment_Proportional)(nil),
+		(*NetworkSpec_HubAssignment_Explicit)(nil),
+	}
+	file_network_spec_proto_msgTypes[7].OneofWrappers = []interface{}{
+		(*NetworkSpec_DemandSpec_Fixed)(nil),
+		(*NetworkSpec_DemandSpec_Uniform)(nil),
+		(*NetworkSpec_DemandSpec_Poisson)(nil),
+	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: file_network_spec_proto_rawDesc,
 			NumEnums:      0,
-			NumMessages:   3,
+			NumMessages:   16,
 			NumExtensions: 0,
 			NumServices:   0,
 		},
@@ -0,0 +1,66 @@
+package burrow
+
+import (
+	gonet "gonum.org/v1/gonum/graph/network"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+// StandardMetrics maps the names accepted by the burrow CLI to the built-in node metrics.
+var StandardMetrics = map[string]NodeMetric{
+	"in-degree":   InDegree,
+	"out-degree":  OutDegree,
+	"betweenness": Betweenness,
+	"pagerank":    PageRank,
+}
+
+// OutDegree scores each node by its number of outbound edges.
+func OutDegree(G *network.DeliveryNetwork) map[int64]float64 {
+	scores := make(map[int64]float64, len(G.Hubs)+len(G.Stops))
+
+	nodes := G.Nodes()
+	for nodes.Next() {
+		id := nodes.Node().ID()
+		scores[id] = float64(len(G.DEdges[id]))
+	}
+
+	return scores
+}
+
+// InDegree scores each node by its number of inbound edges.
+func InDegree(G *network.DeliveryNetwork) map[int64]float64 {
+	scores := make(map[int64]float64, len(G.Hubs)+len(G.Stops))
+
+	nodes := G.Nodes()
+	for nodes.Next() {
+		scores[nodes.Node().ID()] = 0.0
+	}
+
+	for _, edges := range G.DEdges {
+		for _, edge := range edges {
+			scores[edge.To().ID()]++
+		}
+	}
+
+	return scores
+}
+
+// Betweenness scores each node by its unweighted betweenness centrality, as computed by gonum. Nodes that lie on no shortest paths score 0.
+func Betweenness(G *network.DeliveryNetwork) map[int64]float64 {
+	scores := gonet.Betweenness(G)
+
+	nodes := G.Nodes()
+	for nodes.Next() {
+		id := nodes.Node().ID()
+		if _, ok := scores[id]; !ok {
+			scores[id] = 0.0
+		}
+	}
+
+	return scores
+}
+
+// PageRank scores each node by its PageRank, using a damping factor of 0.85.
+func PageRank(G *network.DeliveryNetwork) map[int64]float64 {
+	return gonet.PageRank(G, 0.85, 1e-8)
+}
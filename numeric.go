@@ -0,0 +1,193 @@
+package burrow
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"golang.org/x/exp/constraints"
+)
+
+// Numeric covers the integer and floating-point types the distributions in this file can produce. Distributions of integer types round their samples to the nearest integer.
+type Numeric interface {
+	constraints.Integer | constraints.Float
+}
+
+// UniformDistribution produces values drawn uniformly from [min, max). For integer types, max - min must fit in an int64.
+func UniformDistribution[T Numeric](min, max T) (SampleDistribution[T], error) {
+	return uniformDistribution(globalRand{}, min, max)
+}
+
+func uniformDistribution[T Numeric](rng randSource, min, max T) (SampleDistribution[T], error) {
+	if max <= min {
+		return nil, fmt.Errorf("Maximum must exceed minimum.")
+	}
+
+	if isFloat[T]() {
+		distroFunc := func() T {
+			return min + T(rng.Float64()*float64(max-min))
+		}
+		return SampleDistribution[T](distroFunc), nil
+	}
+
+	span := int64(max) - int64(min)
+	distroFunc := func() T {
+		return min + T(rng.Int63n(span))
+	}
+
+	return SampleDistribution[T](distroFunc), nil
+}
+
+// NormalDistribution produces normally distributed values with the given mean and standard deviation.
+func NormalDistribution[T Numeric](mean, stdDev float64) (SampleDistribution[T], error) {
+	return normalDistribution[T](globalRand{}, mean, stdDev)
+}
+
+func normalDistribution[T Numeric](rng randSource, mean, stdDev float64) (SampleDistribution[T], error) {
+	if stdDev < 0 {
+		return nil, fmt.Errorf("Standard deviation must not be negative.")
+	}
+
+	distroFunc := func() T {
+		return fromFloat[T](mean + stdDev*rng.NormFloat64())
+	}
+
+	return SampleDistribution[T](distroFunc), nil
+}
+
+// ExponentialDistribution produces exponentially distributed values with the given mean, such as the gaps between arrivals at a given average rate.
+func ExponentialDistribution[T Numeric](mean float64) (SampleDistribution[T], error) {
+	return exponentialDistribution[T](globalRand{}, mean)
+}
+
+func exponentialDistribution[T Numeric](rng randSource, mean float64) (SampleDistribution[T], error) {
+	if mean <= 0 {
+		return nil, fmt.Errorf("Mean must be a positive number.")
+	}
+
+	distroFunc := func() T {
+		return fromFloat[T](-mean * math.Log(1.0-rng.Float64()))
+	}
+
+	return SampleDistribution[T](distroFunc), nil
+}
+
+// PoissonDistribution produces whole-number counts following a Poisson distribution with the given mean.
+func PoissonDistribution[T Numeric](mean float64) (SampleDistribution[T], error) {
+	return poissonDistribution[T](globalRand{}, mean)
+}
+
+func poissonDistribution[T Numeric](rng randSource, mean float64) (SampleDistribution[T], error) {
+	if mean <= 0 {
+		return nil, fmt.Errorf("Mean must be a positive number.")
+	}
+
+	// Counts the arrivals of a unit-rate Poisson process before time mean, so the cost is linear in the mean but nothing underflows for large means.
+	distroFunc := func() T {
+		var count T
+		for elapsed := 0.0; ; count++ {
+			elapsed -= math.Log(1.0 - rng.Float64())
+			if elapsed > mean {
+				return count
+			}
+		}
+	}
+
+	return SampleDistribution[T](distroFunc), nil
+}
+
+// CategoricalDistribution produces the index i with probability proportional to weights[i].
+func CategoricalDistribution(weights []float64) (SampleDistribution[int], error) {
+	return categoricalDistribution(globalRand{}, weights)
+}
+
+func categoricalDistribution(rng randSource, weights []float64) (SampleDistribution[int], error) {
+	total := 0.0
+	for _, w := range weights {
+		if w < 0 {
+			return nil, fmt.Errorf("Weights cannot be negative.")
+		}
+		total += w
+	}
+
+	if total <= 0 {
+		return nil, fmt.Errorf("Weights must sum to a positive number.")
+	}
+
+	cumulative := make([]float64, len(weights))
+	sum := 0.0
+	for i, w := range weights {
+		sum += w
+		cumulative[i] = sum / total
+	}
+
+	distroFunc := func() int {
+		u := rng.Float64()
+		for i, c := range cumulative {
+			if u < c {
+				return i
+			}
+		}
+
+		// Only reachable through rounding error in the last cumulative weight.
+		return len(cumulative) - 1
+	}
+
+	return SampleDistribution[int](distroFunc), nil
+}
+
+// WeightedChoiceDistribution produces values[i] with probability proportional to weights[i].
+func WeightedChoiceDistribution[T Rangeable](values []T, weights []float64) (SampleDistribution[T], error) {
+	return weightedChoiceDistribution(globalRand{}, values, weights)
+}
+
+func weightedChoiceDistribution[T Rangeable](rng randSource, values []T, weights []float64) (SampleDistribution[T], error) {
+	if len(values) != len(weights) {
+		return nil, fmt.Errorf("Expected %d weights for %d values, got %d.", len(values), len(values), len(weights))
+	}
+
+	index, err := categoricalDistribution(rng, weights)
+	if err != nil {
+		return nil, err
+	}
+
+	return Map(index, func(i int) T { return values[i] }), nil
+}
+
+// Map produces f(x) for each value x drawn from dist.
+func Map[T, U Rangeable](dist SampleDistribution[T], f func(T) U) SampleDistribution[U] {
+	if dist == nil {
+		return nil
+	}
+
+	return func() U {
+		return f(dist())
+	}
+}
+
+// Scale turns a numeric distribution into a distribution of durations, reading each value as a number of units. For example, Scale(dist, time.Minute) reads draws as minutes.
+func Scale[T Numeric](dist SampleDistribution[T], unit time.Duration) SampleDistribution[time.Duration] {
+	return Map(dist, func(x T) time.Duration {
+		return time.Duration(math.Round(float64(x) * float64(unit)))
+	})
+}
+
+// Shift turns a distribution of durations into a distribution of timestamps, each the drawn duration after origin.
+func Shift(dist SampleDistribution[time.Duration], origin time.Time) SampleDistribution[time.Time] {
+	return Map(dist, origin.Add)
+}
+
+// isFloat reports whether T is a floating-point type, in which division doesn't truncate.
+func isFloat[T Numeric]() bool {
+	var one T = 1
+	return one/2 != 0
+}
+
+// fromFloat converts x to T, rounding to the nearest integer for integer types.
+func fromFloat[T Numeric](x float64) T {
+	if isFloat[T]() {
+		return T(x)
+	}
+
+	return T(math.Round(x))
+}
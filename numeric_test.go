@@ -0,0 +1,166 @@
+package burrow_test
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gonum.org/v1/gonum/stat"
+
+	"github.com/bdshroyer/burrow"
+)
+
+var _ = Describe("Numeric distributions", func() {
+	const N = 20000
+
+	BeforeEach(func() {
+		rand.Seed(7)
+	})
+
+	draw := func(distro func() float64) []float64 {
+		samples := make([]float64, N)
+		for i := range samples {
+			samples[i] = distro()
+		}
+		return samples
+	}
+
+	Describe("UniformDistribution", func() {
+		It("Draws integers from [min, max)", func() {
+			distro, err := burrow.UniformDistribution[int8](-3, 3)
+			Expect(err).NotTo(HaveOccurred())
+
+			seen := make(map[int8]int)
+			for i := 0; i < N; i++ {
+				seen[distro()]++
+			}
+			Expect(seen).To(HaveLen(6))
+			Expect(seen).To(HaveKey(int8(-3)))
+			Expect(seen).NotTo(HaveKey(int8(3)))
+		})
+
+		It("Draws floats with the uniform mean", func() {
+			distro, err := burrow.UniformDistribution(2.0, 4.0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stat.Mean(draw(distro), nil)).To(BeNumerically("~", 3, 0.02))
+		})
+
+		It("Rejects empty ranges", func() {
+			_, err := burrow.UniformDistribution(5, 5)
+			Expect(err).To(MatchError("Maximum must exceed minimum."))
+		})
+	})
+
+	Describe("NormalDistribution", func() {
+		It("Rounds samples of integer types", func() {
+			distro, err := burrow.NormalDistribution[int](10, 3)
+			Expect(err).NotTo(HaveOccurred())
+
+			samples := draw(func() float64 { return float64(distro()) })
+			for _, x := range samples[:100] {
+				Expect(x).To(Equal(math.Round(x)))
+			}
+
+			mean, variance := stat.MeanVariance(samples, nil)
+			Expect(mean).To(BeNumerically("~", 10, 0.1))
+			// Rounding adds the variance of a uniform error on [-0.5, 0.5).
+			Expect(variance).To(BeNumerically("~", 9+1.0/12, 0.3))
+		})
+
+		It("Rejects negative deviations", func() {
+			_, err := burrow.NormalDistribution[float64](0, -1)
+			Expect(err).To(MatchError("Standard deviation must not be negative."))
+		})
+	})
+
+	Describe("ExponentialDistribution and PoissonDistribution", func() {
+		It("Have the requested means", func() {
+			exp, err := burrow.ExponentialDistribution[float64](2.5)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(stat.Mean(draw(exp), nil)).To(BeNumerically("~", 2.5, 0.1))
+
+			poisson, err := burrow.PoissonDistribution[uint](4)
+			Expect(err).NotTo(HaveOccurred())
+
+			mean, variance := stat.MeanVariance(draw(func() float64 { return float64(poisson()) }), nil)
+			Expect(mean).To(BeNumerically("~", 4, 0.1))
+			Expect(variance).To(BeNumerically("~", 4, 0.2))
+		})
+
+		It("Reject means that aren't positive", func() {
+			_, err := burrow.ExponentialDistribution[float64](0)
+			Expect(err).To(MatchError("Mean must be a positive number."))
+
+			_, err = burrow.PoissonDistribution[int](-1)
+			Expect(err).To(MatchError("Mean must be a positive number."))
+		})
+	})
+
+	Describe("CategoricalDistribution and WeightedChoiceDistribution", func() {
+		It("Pick each index in proportion to its weight", func() {
+			distro, err := burrow.CategoricalDistribution([]float64{1, 0, 3})
+			Expect(err).NotTo(HaveOccurred())
+
+			counts := make([]float64, 3)
+			for i := 0; i < N; i++ {
+				counts[distro()]++
+			}
+			Expect(counts[0] / N).To(BeNumerically("~", 0.25, 0.01))
+			Expect(counts[1]).To(BeZero())
+		})
+
+		It("Pick values in proportion to their weights", func() {
+			distro, err := burrow.WeightedChoiceDistribution([]string{"van", "truck"}, []float64{9, 1})
+			Expect(err).NotTo(HaveOccurred())
+
+			vans := 0
+			for i := 0; i < N; i++ {
+				if distro() == "van" {
+					vans++
+				}
+			}
+			Expect(float64(vans) / N).To(BeNumerically("~", 0.9, 0.01))
+		})
+
+		It("Reject bad weights", func() {
+			_, err := burrow.CategoricalDistribution([]float64{1, -1})
+			Expect(err).To(MatchError("Weights cannot be negative."))
+
+			_, err = burrow.CategoricalDistribution([]float64{0, 0})
+			Expect(err).To(MatchError("Weights must sum to a positive number."))
+
+			_, err = burrow.WeightedChoiceDistribution([]int{1, 2}, []float64{1})
+			Expect(err).To(MatchError("Expected 2 weights for 2 values, got 1."))
+		})
+	})
+
+	Describe("Map, Scale and Shift", func() {
+		It("Turn numeric distributions into timestamps", func() {
+			t0 := today()
+
+			minutes, err := burrow.UniformDistribution(30, 90)
+			Expect(err).NotTo(HaveOccurred())
+
+			distro := burrow.Shift(burrow.Scale(minutes, time.Minute), t0)
+			for i := 0; i < 100; i++ {
+				ts := distro()
+				Expect(ts).To(BeTemporally(">=", t0.Add(30*time.Minute)))
+				Expect(ts).To(BeTemporally("<", t0.Add(90*time.Minute)))
+				Expect(ts.Sub(t0) % time.Minute).To(BeZero())
+			}
+
+			hours, err := burrow.NormalDistribution[float64](0, 1)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(burrow.Scale(hours, time.Hour)()).NotTo(BeZero())
+
+			doubled := burrow.Map(minutes, func(m int) float64 { return 2 * float64(m) })
+			Expect(doubled()).To(And(BeNumerically(">=", 60), BeNumerically("<", 180)))
+		})
+
+		It("Leave nil distributions nil", func() {
+			Expect(burrow.Shift(nil, today())).To(BeNil())
+		})
+	})
+})
@@ -0,0 +1,48 @@
+package burrow
+
+import (
+	"fmt"
+	"time"
+)
+
+// UniformDelayDistribution produces a SampleDistribution of delays drawn uniformly from [min, max). If max equals min, every delay is exactly min.
+func UniformDelayDistribution(min, max time.Duration) (SampleDistribution[time.Duration], error) {
+	return uniformDelayDistribution(globalRand{}, min, max)
+}
+
+func uniformDelayDistribution(rng randSource, min, max time.Duration) (SampleDistribution[time.Duration], error) {
+	if min <= 0 {
+		return nil, fmt.Errorf("Minimum delay must be positive.")
+	}
+
+	if max < min {
+		return nil, fmt.Errorf("Maximum delay must not be less than minimum delay.")
+	}
+
+	if max == min {
+		return func() time.Duration { return min }, nil
+	}
+
+	return uniformDistribution(rng, min, max)
+}
+
+// parsePairs converts the spec's pair settings into a pair count and delay distribution. Returns no pairs and a nil distribution if the spec leaves pairs unset, and an error if MaxDelay is set below MinDelay.
+func (spec *NetworkSpec) parsePairs(rng randSource) (uint, SampleDistribution[time.Duration], error) {
+	pairs := spec.GetPairs()
+	if pairs.GetCount() == 0 {
+		return 0, nil, nil
+	}
+
+	// An unset MaxDelay gives every pair the same delay, MinDelay.
+	minDelay, maxDelay := pairs.MinDelay.AsDuration(), pairs.MinDelay.AsDuration()
+	if pairs.MaxDelay != nil {
+		maxDelay = pairs.MaxDelay.AsDuration()
+	}
+
+	delay, err := uniformDelayDistribution(rng, minDelay, maxDelay)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return uint(pairs.Count), delay, nil
+}
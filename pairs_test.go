@@ -0,0 +1,158 @@
+package burrow_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bdshroyer/burrow"
+	"github.com/bdshroyer/burrow/network"
+)
+
+var _ = Describe("Pairs", func() {
+	var cfg burrow.DeliveryNetworkConfig
+
+	BeforeEach(func() {
+		delay, err := burrow.UniformDelayDistribution(2*time.Hour, 3*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+
+		cfg = burrow.DeliveryNetworkConfig{
+			HubNodes:   2,
+			StopNodes:  10,
+			Distro:     testTimeDist(today(), window),
+			EdgeBounds: &burrow.TimeBox{0, time.Hour},
+			Pairs:      5,
+			PairDelay:  delay,
+		}
+	})
+
+	It("Generates pairs on top of the ordinary stops", func() {
+		G, err := burrow.MakeDeliveryNetwork(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(G.Stops).To(HaveLen(20))
+		Expect(G.Pairs).To(HaveLen(10))
+
+		pickups := 0
+		for id, stop := range G.Pairs {
+			Expect(G.Stops[id]).To(BeIdenticalTo(&stop.StopNode))
+			Expect(stop.Partner.Partner).To(BeIdenticalTo(stop))
+
+			if stop.IsPickup() {
+				pickups++
+				delay := stop.Partner.Timestamp.Sub(stop.Timestamp)
+				Expect(delay).To(And(BeNumerically(">=", 2*time.Hour), BeNumerically("<", 3*time.Hour)))
+			}
+		}
+		Expect(pickups).To(Equal(5))
+	})
+
+	It("Unloads each pickup's demand at its dropoff", func() {
+		cfg.Demand = func() float64 { return 4 }
+
+		G, err := burrow.MakeDeliveryNetwork(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, stop := range G.Pairs {
+			if stop.IsPickup() {
+				Expect(stop.Demand).To(Equal(4.0))
+				Expect(stop.Partner.Demand).To(Equal(-4.0))
+			}
+		}
+	})
+
+	It("Never starts a route at a dropoff or ends one at a pickup", func() {
+		G, err := burrow.MakeDeliveryNetwork(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		for id, stop := range G.Pairs {
+			for hubID := range G.Hubs {
+				Expect(G.HasEdgeFromTo(hubID, id)).To(Equal(stop.IsPickup()))
+				Expect(G.HasEdgeFromTo(id, hubID)).To(Equal(stop.IsDropoff()))
+			}
+		}
+	})
+
+	It("Links every pickup to its dropoff, even outside the edge bounds", func() {
+		G, err := burrow.MakeDeliveryNetwork(cfg)
+		Expect(err).NotTo(HaveOccurred())
+
+		for id, stop := range G.Pairs {
+			if !stop.IsPickup() {
+				continue
+			}
+
+			Expect(G.HasEdgeFromTo(id, stop.Partner.ID())).To(BeTrue())
+
+			// Stop-to-stop edges stay in destination timestamp order.
+			var previous time.Time
+			for _, edge := range G.DEdges[id] {
+				if dst, ok := edge.Dst.(*network.StopNode); ok {
+					Expect(dst.Timestamp.Before(previous)).To(BeFalse())
+					previous = dst.Timestamp
+				}
+			}
+		}
+	})
+
+	It("Requires a delay distribution", func() {
+		cfg.PairDelay = nil
+
+		_, err := burrow.MakeDeliveryNetwork(cfg)
+		Expect(err).To(MatchError("Pickup-and-delivery pairs require a delay distribution."))
+	})
+
+	It("Is rejected by the implicit generator", func() {
+		_, err := burrow.MakeImplicitDeliveryNetwork(cfg)
+		Expect(err).To(MatchError("Implicit networks do not support pickup-and-delivery pairs."))
+	})
+
+	It("Rejects unusable delay ranges", func() {
+		_, err := burrow.UniformDelayDistribution(0, time.Hour)
+		Expect(err).To(MatchError("Minimum delay must be positive."))
+
+		_, err = burrow.UniformDelayDistribution(2*time.Hour, time.Hour)
+		Expect(err).To(MatchError("Maximum delay must not be less than minimum delay."))
+	})
+
+	It("Reads pairs from a spec", func() {
+		spec := &burrow.NetworkSpec{
+			Hubs:         1,
+			Stops:        3,
+			Start:        timestamppb.New(today()),
+			End:          timestamppb.New(today().Add(window)),
+			ShortEdge:    durationpb.New(0),
+			LongEdge:     durationpb.New(window),
+			Distribution: &burrow.NetworkSpec_Uniform{Uniform: &burrow.NetworkSpec_UniformDistro{}},
+			Pairs:        &burrow.NetworkSpec_PairSpec{Count: 4, MinDelay: durationpb.New(30 * time.Minute)},
+		}
+
+		netCfg, err := burrow.NewNetworkConfigFrom(spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(netCfg.Pairs).To(BeEquivalentTo(4))
+		Expect(netCfg.PairDelay()).To(Equal(30 * time.Minute))
+
+		G, err := burrow.MakeDeliveryNetwork(*netCfg)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(G.Stops).To(HaveLen(11))
+	})
+
+	It("Rejects a spec whose maximum pair delay is below its minimum", func() {
+		spec := &burrow.NetworkSpec{
+			Hubs:         1,
+			Stops:        3,
+			Start:        timestamppb.New(today()),
+			End:          timestamppb.New(today().Add(window)),
+			ShortEdge:    durationpb.New(0),
+			LongEdge:     durationpb.New(window),
+			Distribution: &burrow.NetworkSpec_Uniform{Uniform: &burrow.NetworkSpec_UniformDistro{}},
+			Pairs:        &burrow.NetworkSpec_PairSpec{Count: 4, MinDelay: durationpb.New(30 * time.Minute), MaxDelay: durationpb.New(10 * time.Minute)},
+		}
+
+		_, err := burrow.NewNetworkConfigFrom(spec)
+		Expect(err).To(MatchError("Maximum delay must not be less than minimum delay."))
+	})
+})
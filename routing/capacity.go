@@ -0,0 +1,168 @@
+package routing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+// SplitByCapacity splits a chain of stops into consecutive segments whose peak load, as in Route.Load, fits within capacity. Each segment is filled greedily before the next is started, which gives the fewest segments for a fixed stop order when no demand is negative.
+// Returns an error if capacity isn't positive or a single stop's demand exceeds it.
+func SplitByCapacity(stops []*network.StopNode, capacity float64) ([][]*network.StopNode, error) {
+	if err := checkCapacity(stops, capacity); err != nil {
+		return nil, err
+	}
+
+	segments := make([][]*network.StopNode, 0)
+	start, load := 0, 0.0
+
+	for i, stop := range stops {
+		if load+stop.Demand > capacity {
+			segments = append(segments, stops[start:i])
+			start, load = i, 0.0
+		}
+		load += stop.Demand
+	}
+
+	if start < len(stops) {
+		segments = append(segments, stops[start:])
+	}
+
+	return segments, nil
+}
+
+// VehicleLowerBound returns a lower bound on the number of vehicles of the given capacity needed to serve every stop in G: the larger of the uncapacitated minimum path cover and the peak combined load, taking the stops in time order, divided by capacity and rounded up.
+func VehicleLowerBound(G *network.DeliveryNetwork, capacity float64) (int, error) {
+	stops := sortedStops(G)
+	if err := checkCapacity(stops, capacity); err != nil {
+		return 0, err
+	}
+
+	bound := len(minChains(G, stops))
+	if byLoad := int(math.Ceil(chainLoad(stops) / capacity)); byLoad > bound {
+		bound = byLoad
+	}
+
+	return bound, nil
+}
+
+// CapacitatedPathCover covers every stop in G with routes whose peak load fits within capacity, dispatching each from a hub within the hubs' fleet limits, as in MinPathCover.
+//
+// Finding the fewest capacitated routes is NP-hard, so CapacitatedPathCover builds two candidate covers and keeps the smaller one that can be dispatched: the uncapacitated minimum cover with each chain split by SplitByCapacity, and a best-fit cover that walks the stops in time order and appends each to the fullest route that can still take it. Compare the result against VehicleLowerBound to gauge how far from optimal it might be.
+//
+// Like MinPathCover, CapacitatedPathCover rejects networks with pickup-and-delivery pairs.
+//
+// Routes are ordered by the timestamp of their first stop.
+func CapacitatedPathCover(G *network.DeliveryNetwork, capacity float64) ([]Route, error) {
+	if err := rejectPairs(G, "CapacitatedPathCover"); err != nil {
+		return nil, err
+	}
+
+	stops := sortedStops(G)
+	if err := checkCapacity(stops, capacity); err != nil {
+		return nil, err
+	}
+
+	split := make([][]*network.StopNode, 0)
+	for _, chain := range minChains(G, stops) {
+		segments, err := SplitByCapacity(chain, capacity)
+		if err != nil {
+			return nil, err
+		}
+		split = append(split, segments...)
+	}
+
+	candidates := [][][]*network.StopNode{split, bestFitChains(G, stops, capacity)}
+	sort.SliceStable(candidates, func(i, j int) bool { return len(candidates[i]) < len(candidates[j]) })
+
+	var err error
+	for _, chains := range candidates {
+		sortChains(chains)
+
+		var routes []Route
+		if routes, err = dispatch(G, chains); err == nil {
+			return routes, nil
+		}
+	}
+
+	return nil, err
+}
+
+// bestFitChains walks stops in time order and appends each to the fullest open chain that ends at one of its predecessors and can take its demand, starting a new chain if none can.
+func bestFitChains(G *network.DeliveryNetwork, stops []*network.StopNode, capacity float64) [][]*network.StopNode {
+	adj := stopAdjacency(G, stops)
+
+	pred := make([][]int, len(stops))
+	for i, successors := range adj {
+		for _, j := range successors {
+			pred[j] = append(pred[j], i)
+		}
+	}
+
+	chains := make([][]*network.StopNode, 0)
+	loads := make([]float64, 0)
+	last := make([]int, 0)
+
+	// tail[i] is the chain that currently ends at stop i, or unmatched.
+	tail := make([]int, len(stops))
+	for i := range tail {
+		tail[i] = unmatched
+	}
+
+	for j, stop := range stops {
+		best := unmatched
+		for _, i := range pred[j] {
+			c := tail[i]
+			if c == unmatched || loads[c]+stop.Demand > capacity {
+				continue
+			}
+
+			if best == unmatched || loads[c] > loads[best] || (loads[c] == loads[best] && c < best) {
+				best = c
+			}
+		}
+
+		if best == unmatched {
+			best = len(chains)
+			chains = append(chains, []*network.StopNode{})
+			loads = append(loads, 0.0)
+			last = append(last, j)
+		} else {
+			tail[last[best]] = unmatched
+		}
+
+		chains[best] = append(chains[best], stop)
+		loads[best] += stop.Demand
+		last[best], tail[j] = j, best
+	}
+
+	return chains
+}
+
+// sortChains orders chains by the timestamp of their first stop, with ties broken by ID.
+func sortChains(chains [][]*network.StopNode) {
+	sort.SliceStable(chains, func(i, j int) bool {
+		a, b := chains[i][0], chains[j][0]
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.Before(b.Timestamp)
+		}
+		return a.ID() < b.ID()
+	})
+}
+
+// checkCapacity verifies that capacity is positive and that no single stop demands more than it.
+func checkCapacity(stops []*network.StopNode, capacity float64) error {
+	if capacity <= 0 {
+		return fmt.Errorf("Vehicle capacity must be a positive number.")
+	}
+
+	for _, stop := range stops {
+		if stop.Demand > capacity {
+			return fmt.Errorf("Stop %d demands %g, more than a vehicle's capacity of %g.", stop.ID(), stop.Demand, capacity)
+		}
+	}
+
+	return nil
+}
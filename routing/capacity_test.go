@@ -0,0 +1,116 @@
+package routing_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bdshroyer/burrow/network"
+	"github.com/bdshroyer/burrow/routing"
+)
+
+// completeLinks links every stop to every later stop, for stops with IDs 10 to 10+n-1.
+func completeLinks(n int) [][2]int64 {
+	links := make([][2]int64, 0)
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			links = append(links, [2]int64{int64(10 + i), int64(10 + j)})
+		}
+	}
+	return links
+}
+
+var _ = Describe("Capacity", func() {
+	var G *network.DeliveryNetwork
+
+	BeforeEach(func() {
+		G = testNetwork([]*network.HubNode{{Val: 1}}, []int{0, 10, 20, 30, 40}, completeLinks(5))
+		for _, stop := range G.Stops {
+			stop.Demand = 3
+		}
+	})
+
+	Describe("SplitByCapacity", func() {
+		It("Splits a chain into the fewest segments that fit", func() {
+			stops := []*network.StopNode{{Val: 1, Demand: 4}, {Val: 2, Demand: 3}, {Val: 3, Demand: 2}, {Val: 4, Demand: 6}}
+
+			segments, err := routing.SplitByCapacity(stops, 7)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(segments).To(Equal([][]*network.StopNode{stops[:2], stops[2:3], stops[3:]}))
+		})
+
+		It("Counts a pair's parcel only while it is on board", func() {
+			stops := []*network.StopNode{{Val: 1, Demand: 4}, {Val: 2, Demand: -4}, {Val: 3, Demand: 5}, {Val: 4, Demand: 2}}
+			Expect(routing.Route{Stops: stops}.Load()).To(Equal(7.0))
+
+			segments, err := routing.SplitByCapacity(stops, 7)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(segments).To(HaveLen(1))
+		})
+
+		It("Rejects stops that don't fit in a single vehicle", func() {
+			_, err := routing.SplitByCapacity([]*network.StopNode{{Val: 1, Demand: 8}}, 7)
+			Expect(err).To(MatchError("Stop 1 demands 8, more than a vehicle's capacity of 7."))
+		})
+	})
+
+	Describe("VehicleLowerBound", func() {
+		It("Is bounded below by total demand", func() {
+			bound, err := routing.VehicleLowerBound(G, 7)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bound).To(Equal(3))
+		})
+
+		It("Is bounded below by the uncapacitated cover", func() {
+			G = testNetwork([]*network.HubNode{{Val: 1}}, []int{0, 10, 20}, nil)
+
+			bound, err := routing.VehicleLowerBound(G, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(bound).To(Equal(3))
+		})
+
+		It("Rejects a non-positive capacity", func() {
+			_, err := routing.VehicleLowerBound(G, 0)
+			Expect(err).To(MatchError("Vehicle capacity must be a positive number."))
+		})
+	})
+
+	Describe("CapacitatedPathCover", func() {
+		It("Covers every stop without overloading any vehicle", func() {
+			routes, err := routing.CapacitatedPathCover(G, 7)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes).To(HaveLen(3))
+
+			covered := 0
+			for _, route := range routes {
+				Expect(route.Load()).To(BeNumerically("<=", 7))
+				covered += len(route.Stops)
+
+				for i := 1; i < len(route.Stops); i++ {
+					Expect(G.HasEdgeFromTo(route.Stops[i-1].ID(), route.Stops[i].ID())).To(BeTrue())
+				}
+			}
+			Expect(covered).To(Equal(5))
+		})
+
+		It("Matches the uncapacitated cover when capacity is ample", func() {
+			routes, err := routing.CapacitatedPathCover(G, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(routes).To(HaveLen(1))
+			Expect(stopIDs(routes[0])).To(Equal([]int64{10, 11, 12, 13, 14}))
+		})
+
+		It("Respects fleet limits", func() {
+			G.Hubs[1].Fleet = 2
+
+			_, err := routing.CapacitatedPathCover(G, 7)
+			Expect(err).To(MatchError(ContainSubstring("fleet limits")))
+		})
+
+		It("Rejects stops that don't fit in a single vehicle", func() {
+			G.Stops[12].Demand = 10
+
+			_, err := routing.CapacitatedPathCover(G, 7)
+			Expect(err).To(MatchError(ContainSubstring("Stop 12 demands 10")))
+		})
+	})
+})
@@ -0,0 +1,321 @@
+package routing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+// Fleet is the result of a fleet-sizing study by MinFleet or MinFleetExpanded.
+type Fleet struct {
+	// Vehicles is the fewest vehicles that can serve every stop.
+	Vehicles int
+
+	// Routes lists the trips the vehicles make, ordered by the timestamp of their first stop. A trip may end at a different hub than it started from, in which case its Return is set.
+	Routes []Route
+
+	// Weight is the least total weight of the trips, and of any hub-to-hub moves between them, among solutions using Vehicles vehicles.
+	Weight float64
+
+	// Present counts the vehicles at each hub copy of a time-expanded network, keyed by the copy's ID: those starting their day there, returning there or waiting there from the previous copy. It is nil for networks that aren't time-expanded.
+	Present map[int64]int
+
+	// Transfers lists the moves vehicles make between different hubs along hub-to-hub transfer edges of a time-expanded network, ordered by departure. Waiting at a hub from one copy to the next isn't a transfer. It is nil for networks that aren't time-expanded.
+	Transfers []Transfer
+}
+
+// Transfer records vehicles moving empty from one hub to another along a transfer edge, between the hub copies From and To of a time-expanded network.
+type Transfer struct {
+	From, To *network.HubNode
+	Vehicles int
+}
+
+// MinFleet finds the fewest routes that cover every stop in G exactly once, each dispatched by a hub within its dispatch limit, and among those the ones of least total weight. Every route is served by its own vehicle.
+//
+// Both goals are met exactly with one min-cost flow solve: each vehicle costs more than the heaviest set of routes could weigh, so saving a vehicle always beats saving weight. Hub-to-hub transfer edges are ignored, since without time expansion a vehicle can't be followed from one route to the next; use MinFleetExpanded to move vehicles between hubs. Unlike MinPathCover, a route may return to a different hub than the one that dispatched it, since flow can't tell vehicles apart. Likewise, pickup-and-delivery pairs aren't kept on one vehicle; check the result with CheckPairs if G has any.
+func MinFleet(G *network.DeliveryNetwork) (*Fleet, error) {
+	return minFleet(G, nil)
+}
+
+// MinFleetExpanded finds the fewest vehicles that can cover every stop in the time-expanded network T exactly once, and among those the trips of least total weight. Unlike MinFleet, a vehicle back at a hub can wait there, or move along hub-to-hub edges, and go out again on a later trip, so one vehicle may serve several routes. Hub fleet sizes limit the vehicles that start the day at each hub, across all of its copies; dispatch capacities are ignored.
+//
+// The routes start and end at hub copies, so they can be checked against T with Route.Validate. Fleet.Present gives the vehicles at each hub at each step.
+func MinFleetExpanded(T *network.TimeExpandedNetwork) (*Fleet, error) {
+	return minFleet(T.DeliveryNetwork, T)
+}
+
+// fleetModel is the flow network behind MinFleet, with the arcs needed to read routes back out of a solved flow.
+//
+// Each unit of flow carries a stop's departure to the next stop's arrival: every stop sends one unit, out of its out node, and receives one, into its in node. A unit either follows a stop-to-stop edge, or goes back to a hub, through the end node, round the vehicle arc to the start node and out to the first stop of another route. Flow on the vehicle arc is therefore the number of routes, or in a time-expanded network the number of vehicles.
+type fleetModel struct {
+	F     *FlowNetwork
+	stops []*network.StopNode
+
+	// Hubs, or hub copies, each have a dispatch and a return node. In a time-expanded network they are the same node, so vehicles can turn round at a hub.
+	hubs     []*network.HubNode
+	dispatch []int
+	ret      []int
+
+	chains    []fleetArc
+	starts    []fleetArc
+	ends      []fleetArc
+	transfers []fleetArc
+
+	// vehicle is the arc every route or vehicle goes round.
+	vehicle int
+}
+
+// fleetArc records an arc between a stop or hub at index from and one at index to, in the model's stops and hubs lists.
+type fleetArc struct {
+	arc      int
+	from, to int
+}
+
+const (
+	fleetSource = iota
+	fleetSink
+	fleetStart
+	fleetEnd
+	fleetNodes
+)
+
+// newFleetModel builds the flow network for G. If T is not nil, G is its expanded graph. Arcs cost their edge's weight, and the vehicle arc costs 1 more than every weighted arc at full capacity put together.
+func newFleetModel(G *network.DeliveryNetwork, T *network.TimeExpandedNetwork) *fleetModel {
+	m := &fleetModel{F: NewFlowNetwork(fleetNodes), stops: sortedStops(G), hubs: sortedHubs(G)}
+	n := len(m.stops)
+
+	total := 0.0
+	addArc := func(u, v, capacity int, w float64) int {
+		total += float64(capacity) * math.Abs(w)
+		return m.F.AddArc(u, v, capacity, w)
+	}
+
+	stopPos := make(map[int64]int, n)
+	for i, stop := range m.stops {
+		stopPos[stop.ID()] = i
+		m.F.AddArc(fleetSource, m.out(i), 1, 0.0)
+		m.F.AddArc(m.in(i), fleetSink, 1, 0.0)
+	}
+
+	next := fleetNodes + 2*n
+	hubPos := make(map[int64]int, len(m.hubs))
+	bases := make(map[int64]int)
+
+	for h, hub := range m.hubs {
+		hubPos[hub.ID()] = h
+
+		if T == nil {
+			m.dispatch = append(m.dispatch, next)
+			m.ret = append(m.ret, next+1)
+			next += 2
+
+			limit := n
+			if l, ok := hub.DispatchLimit(); ok && int(l) < n {
+				limit = int(l)
+			}
+			m.F.AddArc(fleetStart, m.dispatch[h], limit, 0.0)
+		} else {
+			m.dispatch = append(m.dispatch, next)
+			m.ret = append(m.ret, next)
+			next++
+
+			// Vehicles starting at any copy of a hub draw on the same fleet, through a node shared by all of them.
+			orig := T.Instants[hub.ID()].Hub
+			base, ok := bases[orig.ID()]
+			if !ok {
+				base = next
+				next++
+				bases[orig.ID()] = base
+
+				limit := n
+				if orig.Fleet > 0 && int(orig.Fleet) < n {
+					limit = int(orig.Fleet)
+				}
+				m.F.AddArc(fleetStart, base, limit, 0.0)
+			}
+			m.F.AddArc(base, m.dispatch[h], n, 0.0)
+		}
+
+		m.F.AddArc(m.ret[h], fleetEnd, n, 0.0)
+	}
+
+	for h, hub := range m.hubs {
+		for _, edge := range G.DEdges[hub.ID()] {
+			switch kind := edge.Kind(); kind {
+			case network.DispatchEdge:
+				if j, ok := stopPos[edge.Dst.ID()]; ok {
+					arc := addArc(m.dispatch[h], m.in(j), 1, edge.Wgt)
+					m.starts = append(m.starts, fleetArc{arc, h, j})
+				}
+			case network.TransferEdge, network.WaitEdge:
+				// Vehicles only move between hubs in a time-expanded network, where they can be followed from one trip to the next.
+				if k, ok := hubPos[edge.Dst.ID()]; ok && T != nil {
+					arc := addArc(m.ret[h], m.dispatch[k], n, edge.Wgt)
+					if kind == network.TransferEdge {
+						m.transfers = append(m.transfers, fleetArc{arc, h, k})
+					}
+				}
+			}
+		}
+	}
+
+	for i, stop := range m.stops {
+		for _, edge := range G.DEdges[stop.ID()] {
+			switch edge.Kind() {
+			case network.StopLinkEdge:
+				if j, ok := stopPos[edge.Dst.ID()]; ok {
+					arc := addArc(m.out(i), m.in(j), 1, edge.Wgt)
+					m.chains = append(m.chains, fleetArc{arc, i, j})
+				}
+			case network.ReturnEdge:
+				if h, ok := hubPos[edge.Dst.ID()]; ok {
+					arc := addArc(m.out(i), m.ret[h], 1, edge.Wgt)
+					m.ends = append(m.ends, fleetArc{arc, i, h})
+				}
+			}
+		}
+	}
+
+	m.vehicle = m.F.AddArc(fleetEnd, fleetStart, n, 1.0+total)
+
+	return m
+}
+
+// in and out return the flow nodes a unit arrives at and leaves stop i by.
+func (m *fleetModel) in(i int) int  { return fleetNodes + 2*i }
+func (m *fleetModel) out(i int) int { return fleetNodes + 2*i + 1 }
+
+// solve covers every stop.
+func (m *fleetModel) solve() error {
+	sent, _, err := m.F.MinCostFlow(fleetSource, fleetSink, len(m.stops))
+	if err != nil {
+		return err
+	}
+
+	if sent < len(m.stops) {
+		return fmt.Errorf("No set of routes covers every stop within the hubs' fleet limits.")
+	}
+
+	return nil
+}
+
+// weight sums the edge weights along the arcs a solved flow uses, leaving out the vehicle arc.
+func (m *fleetModel) weight() float64 {
+	w := 0.0
+	for a := 0; a < len(m.F.arcs)/2; a++ {
+		if a != m.vehicle {
+			w += float64(m.F.Flow(a)) * m.F.arcs[2*a].cost
+		}
+	}
+
+	return w
+}
+
+// routes reads the routes out of a solved flow.
+func (m *fleetModel) routes() ([]Route, error) {
+	n := len(m.stops)
+	next, start, end := make([]int, n), make([]int, n), make([]int, n)
+	for i := 0; i < n; i++ {
+		next[i], start[i], end[i] = unmatched, unmatched, unmatched
+	}
+
+	for _, a := range m.chains {
+		if m.F.Flow(a.arc) > 0 {
+			next[a.from] = a.to
+		}
+	}
+	for _, a := range m.starts {
+		if m.F.Flow(a.arc) > 0 {
+			start[a.to] = a.from
+		}
+	}
+	for _, a := range m.ends {
+		if m.F.Flow(a.arc) > 0 {
+			end[a.from] = a.to
+		}
+	}
+
+	routes := make([]Route, 0)
+	covered := 0
+
+	for j := 0; j < n; j++ {
+		if start[j] == unmatched {
+			continue
+		}
+
+		route := Route{Hub: m.hubs[start[j]], Stops: make([]*network.StopNode, 0)}
+		i := j
+		for ; next[i] != unmatched; i = next[i] {
+			route.Stops = append(route.Stops, m.stops[i])
+		}
+		route.Stops = append(route.Stops, m.stops[i])
+
+		if end[i] == unmatched {
+			return nil, fmt.Errorf("Route ending at stop %d doesn't return to a hub.", m.stops[i].ID())
+		}
+
+		if h := m.hubs[end[i]]; h != route.Hub {
+			route.Return = h
+		}
+
+		covered += len(route.Stops)
+		routes = append(routes, route)
+	}
+
+	if covered < n {
+		return nil, fmt.Errorf("Stop-to-stop edges form a cycle, so not every stop can be put on a route.")
+	}
+
+	sortRoutes(routes)
+	return routes, nil
+}
+
+func minFleet(G *network.DeliveryNetwork, T *network.TimeExpandedNetwork) (*Fleet, error) {
+	m := newFleetModel(G, T)
+	if err := m.solve(); err != nil {
+		return nil, err
+	}
+
+	routes, err := m.routes()
+	if err != nil {
+		return nil, err
+	}
+
+	fleet := &Fleet{Vehicles: m.F.Flow(m.vehicle), Routes: routes, Weight: m.weight()}
+
+	if T != nil {
+		fleet.Present = make(map[int64]int, len(m.hubs))
+		for h, hub := range m.hubs {
+			fleet.Present[hub.ID()] = m.F.inflow(m.dispatch[h])
+		}
+
+		fleet.Transfers = m.transfersOf(T)
+	}
+
+	return fleet, nil
+}
+
+// transfersOf reads the transfers out of a solved flow over T, ordered by departure time and then by the IDs of the hub copies.
+func (m *fleetModel) transfersOf(T *network.TimeExpandedNetwork) []Transfer {
+	transfers := make([]Transfer, 0)
+	for _, a := range m.transfers {
+		if flow := m.F.Flow(a.arc); flow > 0 {
+			transfers = append(transfers, Transfer{From: m.hubs[a.from], To: m.hubs[a.to], Vehicles: flow})
+		}
+	}
+
+	sort.SliceStable(transfers, func(i, j int) bool {
+		a, b := T.Instants[transfers[i].From.ID()], T.Instants[transfers[j].From.ID()]
+		if !a.Time.Equal(b.Time) {
+			return a.Time.Before(b.Time)
+		}
+		if a.ID() != b.ID() {
+			return a.ID() < b.ID()
+		}
+		return transfers[i].To.ID() < transfers[j].To.ID()
+	})
+
+	return transfers
+}
@@ -0,0 +1,200 @@
+package routing_test
+
+import (
+	"math/rand"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bdshroyer/burrow"
+	"github.com/bdshroyer/burrow/network"
+	"github.com/bdshroyer/burrow/routing"
+)
+
+var _ = Describe("Fleet sizing", func() {
+	var hub *network.HubNode
+
+	BeforeEach(func() {
+		hub = &network.HubNode{Val: 1}
+	})
+
+	Describe("MinFleet", func() {
+		It("Uses as few routes as the minimum path cover", func() {
+			links := [][2]int64{{10, 11}, {11, 13}, {10, 12}, {12, 14}, {11, 14}}
+			G := testNetwork([]*network.HubNode{hub}, []int{0, 10, 20, 30, 40}, links)
+
+			fleet, err := routing.MinFleet(G)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fleet.Vehicles).To(Equal(2))
+			Expect(fleet.Routes).To(HaveLen(2))
+			Expect(fleet.Present).To(BeNil())
+
+			_, err = routing.RouteSet(fleet.Routes).Validate(G)
+			Expect(err).NotTo(HaveOccurred())
+
+			S, err := routing.NewSolution(G, fleet.Routes)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fleet.Weight).To(Equal(S.Weight))
+		})
+
+		It("Picks the lightest of the covers with the fewest routes", func() {
+			G := testNetwork([]*network.HubNode{hub}, []int{0, 10, 20}, [][2]int64{{10, 11}, {10, 12}})
+
+			fleet, err := routing.MinFleet(G)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fleet.Vehicles).To(Equal(2))
+			Expect(stopIDs(fleet.Routes[0])).To(Equal([]int64{10, 11}))
+			Expect(stopIDs(fleet.Routes[1])).To(Equal([]int64{12}))
+			Expect(fleet.Weight).To(Equal(float64(4*time.Hour + 10*time.Minute)))
+		})
+
+		It("Lets routes return to another hub", func() {
+			other := &network.HubNode{Val: 2}
+			G := testNetwork([]*network.HubNode{hub}, []int{0}, nil)
+			G.Hubs[other.ID()] = other
+			G.DEdges[10] = []*network.DeliveryEdge{{Src: G.Stops[10], Dst: other, Wgt: float64(time.Hour)}}
+
+			fleet, err := routing.MinFleet(G)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fleet.Routes).To(HaveLen(1))
+			Expect(fleet.Routes[0].Hub).To(Equal(hub))
+			Expect(fleet.Routes[0].Return).To(Equal(other))
+			Expect(fleet.Routes[0].Validate(G).Valid()).To(BeTrue())
+		})
+
+		It("Respects dispatch limits", func() {
+			hub.Fleet = 1
+			G := testNetwork([]*network.HubNode{hub}, []int{0, 10}, nil)
+
+			_, err := routing.MinFleet(G)
+			Expect(err).To(MatchError("No set of routes covers every stop within the hubs' fleet limits."))
+		})
+
+		It("Matches the minimum path cover on a generated network", func() {
+			distro, err := burrow.UniformTimestampDistribution(t0, 12*time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+
+			rand.Seed(40)
+			G, err := burrow.MakeDeliveryNetwork(burrow.DeliveryNetworkConfig{
+				HubNodes:   2,
+				StopNodes:  80,
+				Distro:     distro,
+				EdgeBounds: &burrow.TimeBox{5 * time.Minute, 40 * time.Minute},
+			})
+			Expect(err).NotTo(HaveOccurred())
+
+			fleet, err := routing.MinFleet(G)
+			Expect(err).NotTo(HaveOccurred())
+
+			exact, err := routing.MinPathCover(G)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fleet.Vehicles).To(Equal(len(exact)))
+
+			_, err = routing.RouteSet(fleet.Routes).Validate(G)
+			Expect(err).NotTo(HaveOccurred())
+
+			S, err := routing.NewSolution(G, exact)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fleet.Weight).To(BeNumerically("<=", S.Weight))
+		})
+	})
+
+	Describe("MinFleetExpanded", func() {
+		var (
+			G      *network.DeliveryNetwork
+			hourAt func(h int) time.Time
+		)
+
+		BeforeEach(func() {
+			day := t0.Truncate(24 * time.Hour)
+			hourAt = func(h int) time.Time { return day.Add(time.Duration(h) * time.Hour) }
+
+			// Stops at 08:00 and 12:00, with hub trips of an hour each way and no link between them.
+			G = testNetwork([]*network.HubNode{hub}, []int{0, 240}, nil)
+		})
+
+		It("Reuses a vehicle that is back at its hub in time", func() {
+			plain, err := routing.MinFleet(G)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plain.Vehicles).To(Equal(2))
+
+			T, err := network.NewTimeExpandedNetwork(G, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+
+			fleet, err := routing.MinFleetExpanded(T)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fleet.Vehicles).To(Equal(1))
+			Expect(fleet.Routes).To(HaveLen(2))
+			Expect(fleet.Weight).To(Equal(float64(4 * time.Hour)))
+
+			first := fleet.Routes[0]
+			Expect(T.Instants[first.Hub.ID()].Time).To(Equal(hourAt(7)))
+			Expect(T.Instants[first.End().ID()].Time).To(Equal(hourAt(9)))
+
+			for _, route := range fleet.Routes {
+				Expect(route.Validate(T.DeliveryNetwork).Valid()).To(BeTrue())
+			}
+
+			present := make([]int, 0)
+			for _, instant := range T.Timelines[hub.ID()] {
+				present = append(present, fleet.Present[instant.ID()])
+			}
+			// 07:00 through 13:00: out on the first trip at 08:00, and on the second at 12:00.
+			Expect(present).To(Equal([]int{1, 0, 1, 1, 1, 0, 1}))
+		})
+
+		It("Limits the vehicles each hub starts with", func() {
+			hub.Fleet = 1
+			G = testNetwork([]*network.HubNode{hub}, []int{0, 30}, nil)
+
+			T, err := network.NewTimeExpandedNetwork(G, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = routing.MinFleetExpanded(T)
+			Expect(err).To(MatchError("No set of routes covers every stop within the hubs' fleet limits."))
+		})
+
+		It("Moves vehicles between hubs along transfer edges", func() {
+			other := &network.HubNode{Val: 2}
+			first := &network.StopNode{Val: 10, Timestamp: hourAt(8)}
+			second := &network.StopNode{Val: 11, Timestamp: hourAt(14)}
+
+			// The first stop is served only from hub 1 and the second only from hub 2, two hours away by line haul.
+			G = network.NewDeliveryNetwork()
+			G.Hubs[hub.ID()], G.Hubs[other.ID()] = hub, other
+			G.Stops[first.ID()], G.Stops[second.ID()] = first, second
+			for _, link := range [][2]network.DeliveryNode{{hub, first}, {other, second}} {
+				out := &network.DeliveryEdge{Src: link[0], Dst: link[1], Wgt: float64(time.Hour)}
+				G.DEdges[link[0].ID()] = append(G.DEdges[link[0].ID()], out)
+				G.DEdges[link[1].ID()] = append(G.DEdges[link[1].ID()], out.ReversedEdge().(*network.DeliveryEdge))
+			}
+			transfer := &network.DeliveryEdge{Src: hub, Dst: other, Wgt: float64(2 * time.Hour)}
+			G.DEdges[hub.ID()] = append(G.DEdges[hub.ID()], transfer)
+			G.DEdges[other.ID()] = append(G.DEdges[other.ID()], transfer.ReversedEdge().(*network.DeliveryEdge))
+
+			plain, err := routing.MinFleet(G)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(plain.Vehicles).To(Equal(2))
+			Expect(plain.Transfers).To(BeNil())
+
+			T, err := network.NewTimeExpandedNetwork(G, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+
+			fleet, err := routing.MinFleetExpanded(T)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fleet.Vehicles).To(Equal(1))
+			Expect(fleet.Weight).To(Equal(float64(6 * time.Hour)))
+			Expect(fleet.Transfers).To(HaveLen(1))
+
+			moved := fleet.Transfers[0]
+			Expect(moved.Vehicles).To(Equal(1))
+
+			from, to := T.Instants[moved.From.ID()], T.Instants[moved.To.ID()]
+			Expect(from.Hub).To(BeIdenticalTo(hub))
+			Expect(to.Hub).To(BeIdenticalTo(other))
+			Expect(from.Time).To(BeTemporally(">=", hourAt(9)))
+			Expect(to.Time).To(BeTemporally("<=", hourAt(13)))
+		})
+	})
+})
@@ -0,0 +1,209 @@
+package routing
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// FlowNetwork is a directed network of arcs with integer capacities and real costs per unit of flow, for solving min-cost flow problems. Nodes are numbered from 0 and created as arcs reference them.
+type FlowNetwork struct {
+	// Arcs are stored in pairs: arc 2k is the k-th arc added and arc 2k+1 its residual reverse.
+	arcs []flowArc
+	adj  [][]int
+}
+
+type flowArc struct {
+	to       int
+	capacity int
+	flow     int
+	cost     float64
+}
+
+// NewFlowNetwork returns a flow network with n nodes and no arcs.
+func NewFlowNetwork(n int) *FlowNetwork {
+	return &FlowNetwork{arcs: make([]flowArc, 0), adj: make([][]int, n)}
+}
+
+// Nodes returns the number of nodes in the network.
+func (F *FlowNetwork) Nodes() int {
+	return len(F.adj)
+}
+
+// AddArc adds an arc from u to v carrying up to capacity units at the given cost each, growing the network if u or v is a new node, and returns the arc's index for use with Flow.
+func (F *FlowNetwork) AddArc(u, v, capacity int, cost float64) int {
+	for len(F.adj) <= u || len(F.adj) <= v {
+		F.adj = append(F.adj, nil)
+	}
+
+	F.adj[u] = append(F.adj[u], len(F.arcs))
+	F.arcs = append(F.arcs, flowArc{to: v, capacity: capacity, cost: cost})
+	F.adj[v] = append(F.adj[v], len(F.arcs))
+	F.arcs = append(F.arcs, flowArc{to: u, capacity: 0, cost: -cost})
+
+	return len(F.arcs)/2 - 1
+}
+
+// Flow returns the flow on the arc with the given index.
+func (F *FlowNetwork) Flow(arc int) int {
+	return F.arcs[2*arc].flow
+}
+
+// inflow returns the total flow on arcs into node v.
+func (F *FlowNetwork) inflow(v int) int {
+	total := 0
+	for _, a := range F.adj[v] {
+		// Odd arcs leaving v are the residual reverses of arcs into v.
+		if a%2 == 1 {
+			total += F.arcs[a^1].flow
+		}
+	}
+
+	return total
+}
+
+// residual returns the capacity left on arc a of the residual network.
+func (F *FlowNetwork) residual(a int) int {
+	return F.arcs[a].capacity - F.arcs[a].flow
+}
+
+// push sends units along arc a of the residual network.
+func (F *FlowNetwork) push(a, units int) {
+	F.arcs[a].flow += units
+	F.arcs[a^1].flow -= units
+}
+
+// MinCostFlow sends up to required units of flow from s to t at the least total cost, adding to any flow already in the network, and returns the units sent and their cost. A negative required sends as much flow as the network can carry.
+//
+// It uses successive shortest paths: each augmentation follows the cheapest path in the residual network, found with Dijkstra's algorithm over costs reduced by node potentials. Arc costs may be negative, but returns an error if the residual network has a cycle of negative cost.
+func (F *FlowNetwork) MinCostFlow(s, t, required int) (int, float64, error) {
+	n := len(F.adj)
+	if s < 0 || s >= n || t < 0 || t >= n || s == t {
+		return 0, 0.0, fmt.Errorf("Source %d and sink %d must be distinct nodes of a network with %d nodes.", s, t, n)
+	}
+
+	potential, err := F.potentials()
+	if err != nil {
+		return 0, 0.0, err
+	}
+
+	sent, cost := 0, 0.0
+	dist := make([]float64, n)
+	via := make([]int, n)
+
+	for required < 0 || sent < required {
+		F.shortestPaths(s, potential, dist, via)
+		if math.IsInf(dist[t], 1) {
+			break
+		}
+
+		units := math.MaxInt
+		if required >= 0 {
+			units = required - sent
+		}
+		for v := t; v != s; v = F.arcs[via[v]^1].to {
+			if r := F.residual(via[v]); r < units {
+				units = r
+			}
+		}
+
+		for v := t; v != s; v = F.arcs[via[v]^1].to {
+			F.push(via[v], units)
+			cost += float64(units) * F.arcs[via[v]].cost
+		}
+		sent += units
+
+		// Nodes the search didn't settle before t are capped at t's distance, which keeps every residual arc's reduced cost non-negative.
+		for v := range potential {
+			potential[v] += math.Min(dist[v], dist[t])
+		}
+	}
+
+	return sent, cost, nil
+}
+
+// potentials returns node potentials under which every arc with residual capacity has a non-negative reduced cost, found with Bellman-Ford from a virtual source linked to every node.
+func (F *FlowNetwork) potentials() ([]float64, error) {
+	n := len(F.adj)
+	potential := make([]float64, n)
+
+	for round := 0; round <= n; round++ {
+		changed := false
+
+		for u := 0; u < n; u++ {
+			for _, a := range F.adj[u] {
+				if F.residual(a) <= 0 {
+					continue
+				}
+
+				if v, d := F.arcs[a].to, potential[u]+F.arcs[a].cost; d < potential[v] {
+					potential[v] = d
+					changed = true
+				}
+			}
+		}
+
+		if !changed {
+			return potential, nil
+		}
+	}
+
+	return nil, fmt.Errorf("Flow network has a cycle of negative cost.")
+}
+
+// shortestPaths runs Dijkstra's algorithm from s over reduced costs, filling in each node's distance and the arc it was reached by. Unreachable nodes are left at +Inf.
+func (F *FlowNetwork) shortestPaths(s int, potential, dist []float64, via []int) {
+	for v := range dist {
+		dist[v], via[v] = math.Inf(1), unmatched
+	}
+	dist[s] = 0.0
+
+	queue := &distQueue{{node: s}}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(queuedDist)
+		u := item.node
+		if item.dist > dist[u] {
+			continue
+		}
+
+		for _, a := range F.adj[u] {
+			if F.residual(a) <= 0 {
+				continue
+			}
+
+			v := F.arcs[a].to
+			// Reduced costs are non-negative in exact arithmetic; clamp rounding error so Dijkstra's invariant holds.
+			d := dist[u] + math.Max(0.0, F.arcs[a].cost+potential[u]-potential[v])
+
+			if d < dist[v] {
+				dist[v], via[v] = d, a
+				heap.Push(queue, queuedDist{dist: d, node: v})
+			}
+		}
+	}
+}
+
+// queuedDist is an entry in Dijkstra's priority queue. Entries whose distance exceeds the node's current distance are stale and skipped.
+type queuedDist struct {
+	dist float64
+	node int
+}
+
+// distQueue is a min-heap of queued distances.
+type distQueue []queuedDist
+
+func (q distQueue) Len() int { return len(q) }
+
+func (q distQueue) Less(i, j int) bool { return q[i].dist < q[j].dist }
+
+func (q distQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *distQueue) Push(x any) { *q = append(*q, x.(queuedDist)) }
+
+func (q *distQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,92 @@
+package routing_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bdshroyer/burrow/routing"
+)
+
+var _ = Describe("FlowNetwork", func() {
+	var (
+		F    *routing.FlowNetwork
+		arcs []int
+	)
+
+	BeforeEach(func() {
+		// Three paths from 0 to 3: 0-1-2-3 and 0-2-3 cost 3 each, and 0-1-3 costs 4.
+		F = routing.NewFlowNetwork(4)
+		arcs = []int{
+			F.AddArc(0, 1, 2, 1.0),
+			F.AddArc(0, 2, 1, 2.0),
+			F.AddArc(1, 2, 1, 1.0),
+			F.AddArc(1, 3, 1, 3.0),
+			F.AddArc(2, 3, 2, 1.0),
+		}
+	})
+
+	It("Sends the required flow along the cheapest paths", func() {
+		sent, cost, err := F.MinCostFlow(0, 3, 2)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sent).To(Equal(2))
+		Expect(cost).To(Equal(6.0))
+		Expect(F.Flow(arcs[3])).To(BeZero())
+	})
+
+	It("Sends as much flow as it can when no amount is required", func() {
+		sent, cost, err := F.MinCostFlow(0, 3, -1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sent).To(Equal(3))
+		Expect(cost).To(Equal(10.0))
+
+		flows := make([]int, 0, len(arcs))
+		for _, arc := range arcs {
+			flows = append(flows, F.Flow(arc))
+		}
+		Expect(flows).To(Equal([]int{2, 1, 1, 1, 2}))
+	})
+
+	It("Reroutes earlier flow when that makes room for more", func() {
+		// The cheapest single path is 0-1-2-3, but a second unit needs 0-1 and 1-2 split across two paths.
+		F := routing.NewFlowNetwork(4)
+		F.AddArc(0, 1, 1, 1.0)
+		F.AddArc(0, 2, 1, 10.0)
+		F.AddArc(1, 2, 1, 1.0)
+		F.AddArc(1, 3, 1, 10.0)
+		F.AddArc(2, 3, 1, 1.0)
+
+		sent, cost, err := F.MinCostFlow(0, 3, -1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sent).To(Equal(2))
+		Expect(cost).To(Equal(22.0))
+	})
+
+	It("Handles negative costs", func() {
+		F := routing.NewFlowNetwork(3)
+		F.AddArc(0, 1, 1, -5.0)
+		F.AddArc(1, 2, 1, 1.0)
+
+		sent, cost, err := F.MinCostFlow(0, 2, -1)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(sent).To(Equal(1))
+		Expect(cost).To(Equal(-4.0))
+	})
+
+	It("Rejects negative-cost cycles", func() {
+		F := routing.NewFlowNetwork(3)
+		F.AddArc(0, 1, 1, 1.0)
+		F.AddArc(1, 2, 1, -3.0)
+		F.AddArc(2, 1, 1, 1.0)
+
+		_, _, err := F.MinCostFlow(0, 2, -1)
+		Expect(err).To(MatchError("Flow network has a cycle of negative cost."))
+	})
+
+	It("Grows to fit new nodes and rejects bad terminals", func() {
+		F.AddArc(3, 5, 1, 0.0)
+		Expect(F.Nodes()).To(Equal(6))
+
+		_, _, err := F.MinCostFlow(0, 0, 1)
+		Expect(err).To(MatchError("Source 0 and sink 0 must be distinct nodes of a network with 6 nodes."))
+	})
+})
@@ -0,0 +1,369 @@
+package routing
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+// NearestNext builds routes by repeatedly starting a vehicle at the earliest unserved stop and following the lightest stop-to-stop edge to another unserved stop until none is left. Stops at the end of a route that no hub serving its first stop can take the vehicle back from are returned to the pool.
+//
+// Like the other heuristics in this file, NearestNext runs in roughly linear time in the number of edges, so it suits networks too large for MinPathCover. Hubs are assigned to the finished routes within their dispatch limits, as in MinPathCover. None of the heuristics keep pickup-and-delivery pairs on one route, so they reject networks with any.
+func NearestNext(G *network.DeliveryNetwork) (*Solution, error) {
+	if err := rejectPairs(G, "NearestNext"); err != nil {
+		return nil, err
+	}
+
+	x := newStopIndex(G)
+	routed := make([]bool, len(x.stops))
+	chains := make([][]int, 0)
+
+	for s := 0; s < len(x.stops); s++ {
+		if routed[s] {
+			continue
+		}
+
+		chain := []int{s}
+		routed[s] = true
+
+		for cur := s; ; {
+			next, best := unmatched, math.Inf(1)
+			for _, j := range x.succ[cur] {
+				if !routed[j] && x.weight[cur][j] < best {
+					next, best = j, x.weight[cur][j]
+				}
+			}
+
+			if next == unmatched {
+				break
+			}
+
+			chain = append(chain, next)
+			routed[next] = true
+			cur = next
+		}
+
+		end := len(chain)
+		for end > 0 && !x.servable(chain[0], chain[end-1]) {
+			end--
+		}
+
+		if end == 0 {
+			return nil, fmt.Errorf("No hub can serve stop %d.", x.stops[s].ID())
+		}
+
+		// Released stops normally come later, but edges between stops sharing a timestamp can point backwards.
+		restart := s
+		for _, k := range chain[end:] {
+			routed[k] = false
+			if k < restart {
+				restart = k
+			}
+		}
+
+		chains = append(chains, chain[:end])
+		s = restart
+	}
+
+	return x.solve(G, chains)
+}
+
+// CheapestInsertion builds routes by repeatedly inserting the unserved stop that is cheapest to add, at its cheapest position: between two consecutive stops of a route, at either end of one, or on a new route of its own. The cost of a position is the change in total route weight, so a stop only opens a new route when no existing route can take it for less than a round trip from a hub.
+//
+// Insertion costs are kept in a priority queue and only recomputed for stops near each insertion, so the heuristic scales to large networks.
+func CheapestInsertion(G *network.DeliveryNetwork) (*Solution, error) {
+	if err := rejectPairs(G, "CheapestInsertion"); err != nil {
+		return nil, err
+	}
+
+	x := newStopIndex(G)
+	n := len(x.stops)
+
+	ins := &insertion{
+		x:     x,
+		next:  make([]int, n),
+		prev:  make([]int, n),
+		route: make([]int, n),
+	}
+
+	for i := 0; i < n; i++ {
+		ins.next[i], ins.prev[i], ins.route[i] = unmatched, unmatched, unmatched
+	}
+
+	version := make([]int, n)
+	queue := &costQueue{}
+
+	for s := 0; s < n; s++ {
+		if !x.servable(s, s) {
+			return nil, fmt.Errorf("No hub can serve stop %d.", x.stops[s].ID())
+		}
+
+		heap.Push(queue, queuedCost{cost: x.hubCost(s, s), stop: s})
+	}
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(queuedCost)
+		s := item.stop
+
+		if ins.route[s] != unmatched || item.version != version[s] {
+			continue
+		}
+
+		// Positions may have been used up since the cost was queued. If so, requeue at the current cost and let cheaper stops go first.
+		best := ins.best(s)
+		if best.cost > item.cost && queue.Len() > 0 && best.cost > (*queue)[0].cost {
+			version[s]++
+			heap.Push(queue, queuedCost{cost: best.cost, stop: s, version: version[s]})
+			continue
+		}
+
+		ins.insert(s, best)
+
+		// Only stops adjacent to the new stop or its neighbours gain new positions.
+		touched := append(append([]int{}, x.succ[s]...), x.pred[s]...)
+		if best.after != unmatched {
+			touched = append(touched, x.succ[best.after]...)
+		}
+		if best.before != unmatched {
+			touched = append(touched, x.pred[best.before]...)
+		}
+
+		for _, k := range touched {
+			if ins.route[k] == unmatched {
+				version[k]++
+				heap.Push(queue, queuedCost{cost: ins.best(k).cost, stop: k, version: version[k]})
+			}
+		}
+	}
+
+	return x.solve(G, ins.chains())
+}
+
+// Savings builds routes with the Clarke-Wright savings algorithm. Every stop starts on its own route, and routes are joined end to start along stop-to-stop edges in decreasing order of the weight saved: the trip back to a hub from the first route plus the trip out to the second, less the edge joining them. Only joins that save weight, and that leave some hub able to serve the joined route, are made.
+func Savings(G *network.DeliveryNetwork) (*Solution, error) {
+	if err := rejectPairs(G, "Savings"); err != nil {
+		return nil, err
+	}
+
+	x := newStopIndex(G)
+	n := len(x.stops)
+
+	type saving struct {
+		from, to int
+		value    float64
+	}
+
+	savings := make([]saving, 0)
+	for i := 0; i < n; i++ {
+		if !x.servable(i, i) {
+			return nil, fmt.Errorf("No hub can serve stop %d.", x.stops[i].ID())
+		}
+
+		for _, j := range x.succ[i] {
+			value := minWeight(x.out[i]) + minWeight(x.in[j]) - x.weight[i][j]
+			if value > 0 {
+				savings = append(savings, saving{i, j, value})
+			}
+		}
+	}
+
+	sort.SliceStable(savings, func(a, b int) bool { return savings[a].value > savings[b].value })
+
+	// Routes are linked lists threaded through next, grouped with a union-find forest. Each root records its route's first and last stop.
+	next, parent := make([]int, n), make([]int, n)
+	head, tail := make([]int, n), make([]int, n)
+	for i := 0; i < n; i++ {
+		next[i], parent[i], head[i], tail[i] = unmatched, i, i, i
+	}
+
+	var find func(i int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+
+	for _, sv := range savings {
+		ri, rj := find(sv.from), find(sv.to)
+
+		if ri == rj || tail[ri] != sv.from || head[rj] != sv.to {
+			continue
+		}
+
+		if !x.servable(head[ri], tail[rj]) {
+			continue
+		}
+
+		next[sv.from] = sv.to
+		parent[rj] = ri
+		tail[ri] = tail[rj]
+	}
+
+	merged := make([][]int, 0)
+	for r := 0; r < n; r++ {
+		if find(r) != r {
+			continue
+		}
+
+		chain := make([]int, 0)
+		for k := head[r]; k != unmatched; k = next[k] {
+			chain = append(chain, k)
+		}
+		merged = append(merged, chain)
+	}
+
+	return x.solve(G, merged)
+}
+
+// solve dispatches chains of stop positions from hubs within their dispatch limits and totals the resulting routes.
+func (x *stopIndex) solve(G *network.DeliveryNetwork, chains [][]int) (*Solution, error) {
+	stops := x.chainStops(chains)
+	sortChains(stops)
+
+	routes, err := dispatch(G, stops)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewSolution(G, routes)
+}
+
+// minWeight returns the lightest weight among a list of hub links, or 0 if the list is empty.
+func minWeight(links []hubLink) float64 {
+	if len(links) == 0 {
+		return 0.0
+	}
+
+	best := links[0].weight
+	for _, link := range links[1:] {
+		best = math.Min(best, link.weight)
+	}
+
+	return best
+}
+
+// insertion holds the routes under construction in CheapestInsertion as doubly linked lists of stop positions.
+type insertion struct {
+	x          *stopIndex
+	next, prev []int
+	route      []int
+
+	// heads[r] and tails[r] are the first and last stops of route r.
+	heads, tails []int
+}
+
+// position describes where a stop would be inserted: after stop after and before stop before, either of which is unmatched at the end of a route. Both are unmatched for a new route.
+type position struct {
+	after, before int
+	cost          float64
+}
+
+// best returns the cheapest position for stop s.
+func (ins *insertion) best(s int) position {
+	x := ins.x
+	best := position{after: unmatched, before: unmatched, cost: x.hubCost(s, s)}
+
+	consider := func(p position) {
+		if p.cost < best.cost {
+			best = p
+		}
+	}
+
+	for _, a := range x.pred[s] {
+		if ins.route[a] == unmatched {
+			continue
+		}
+
+		if b := ins.next[a]; b != unmatched {
+			if w, ok := x.weight[s][b]; ok {
+				consider(position{a, b, x.weight[a][s] + w - x.weight[a][b]})
+			}
+		} else {
+			head := ins.heads[ins.route[a]]
+			consider(position{a, unmatched, x.weight[a][s] + x.hubCost(head, s) - x.hubCost(head, a)})
+		}
+	}
+
+	for _, b := range x.succ[s] {
+		if ins.route[b] == unmatched || ins.prev[b] != unmatched {
+			continue
+		}
+
+		tail := ins.tails[ins.route[b]]
+		consider(position{unmatched, b, x.weight[s][b] + x.hubCost(s, tail) - x.hubCost(b, tail)})
+	}
+
+	return best
+}
+
+// insert places stop s at position p.
+func (ins *insertion) insert(s int, p position) {
+	ins.prev[s], ins.next[s] = p.after, p.before
+
+	switch {
+	case p.after != unmatched:
+		ins.route[s] = ins.route[p.after]
+		ins.next[p.after] = s
+	case p.before != unmatched:
+		ins.route[s] = ins.route[p.before]
+		ins.heads[ins.route[s]] = s
+	default:
+		ins.route[s] = len(ins.heads)
+		ins.heads = append(ins.heads, s)
+		ins.tails = append(ins.tails, s)
+	}
+
+	if p.before != unmatched {
+		ins.prev[p.before] = s
+	} else {
+		ins.tails[ins.route[s]] = s
+	}
+}
+
+// chains walks every route from its head.
+func (ins *insertion) chains() [][]int {
+	chains := make([][]int, 0, len(ins.heads))
+	for _, head := range ins.heads {
+		chain := make([]int, 0)
+		for k := head; k != unmatched; k = ins.next[k] {
+			chain = append(chain, k)
+		}
+		chains = append(chains, chain)
+	}
+
+	return chains
+}
+
+// queuedCost is an entry in CheapestInsertion's priority queue. Entries whose version lags the stop's current version are stale and skipped.
+type queuedCost struct {
+	cost    float64
+	stop    int
+	version int
+}
+
+// costQueue is a min-heap of queued costs, with ties going to the earlier stop.
+type costQueue []queuedCost
+
+func (q costQueue) Len() int { return len(q) }
+
+func (q costQueue) Less(i, j int) bool {
+	if q[i].cost != q[j].cost {
+		return q[i].cost < q[j].cost
+	}
+	return q[i].stop < q[j].stop
+}
+
+func (q costQueue) Swap(i, j int) { q[i], q[j] = q[j], q[i] }
+
+func (q *costQueue) Push(x any) { *q = append(*q, x.(queuedCost)) }
+
+func (q *costQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
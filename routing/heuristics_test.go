@@ -0,0 +1,133 @@
+package routing_test
+
+import (
+	"math/rand"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bdshroyer/burrow"
+	"github.com/bdshroyer/burrow/network"
+	"github.com/bdshroyer/burrow/routing"
+)
+
+// expectCover checks that routes serve every stop of G exactly once along edges G has.
+func expectCover(G *network.DeliveryNetwork, routes []routing.Route) {
+	seen := map[int64]bool{}
+
+	for _, route := range routes {
+		Expect(route.Stops).NotTo(BeEmpty())
+		Expect(G.HasEdgeFromTo(route.Hub.ID(), route.Stops[0].ID())).To(BeTrue())
+		Expect(G.HasEdgeFromTo(route.Stops[len(route.Stops)-1].ID(), route.Hub.ID())).To(BeTrue())
+
+		for i, stop := range route.Stops {
+			Expect(seen).NotTo(HaveKey(stop.ID()))
+			seen[stop.ID()] = true
+
+			if i > 0 {
+				Expect(G.HasEdgeFromTo(route.Stops[i-1].ID(), stop.ID())).To(BeTrue())
+			}
+		}
+	}
+
+	Expect(seen).To(HaveLen(len(G.Stops)))
+}
+
+var _ = Describe("Heuristics", func() {
+	heuristics := map[string]func(*network.DeliveryNetwork) (*routing.Solution, error){
+		"NearestNext":       routing.NearestNext,
+		"CheapestInsertion": routing.CheapestInsertion,
+		"Savings":           routing.Savings,
+	}
+
+	for name, build := range heuristics {
+		name, build := name, build
+
+		Describe(name, func() {
+			It("Chains stops that are linked in sequence onto a single route", func() {
+				G := testNetwork([]*network.HubNode{{Val: 1}}, []int{0, 10, 20, 30}, completeLinks(4))
+
+				S, err := build(G)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(S.Vehicles()).To(Equal(1))
+				Expect(stopIDs(S.Routes[0])).To(Equal([]int64{10, 11, 12, 13}))
+
+				// Two hub legs plus three 10-minute hops, each driven in exactly the time between its stops.
+				Expect(S.Weight).To(Equal(float64(2*time.Hour + 30*time.Minute)))
+				Expect(S.Idle).To(BeZero())
+			})
+
+			It("Covers a generated network and never beats the exact cover", func() {
+				distro, err := burrow.UniformTimestampDistribution(t0, 12*time.Hour)
+				Expect(err).NotTo(HaveOccurred())
+
+				rand.Seed(21)
+				G, err := burrow.MakeDeliveryNetwork(burrow.DeliveryNetworkConfig{
+					HubNodes:   2,
+					StopNodes:  120,
+					Distro:     distro,
+					EdgeBounds: &burrow.TimeBox{5 * time.Minute, 40 * time.Minute},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				S, err := build(G)
+				Expect(err).NotTo(HaveOccurred())
+				expectCover(G, S.Routes)
+
+				exact, err := routing.MinPathCover(G)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(S.Vehicles()).To(BeNumerically(">=", len(exact)))
+				Expect(S.Gap(exact)).To(BeNumerically(">=", 0))
+
+				total, err := routing.NewSolution(G, S.Routes)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(S.Weight).To(Equal(total.Weight))
+			})
+
+			It("Respects fleet limits", func() {
+				G := testNetwork([]*network.HubNode{{Val: 1, Fleet: 1}}, []int{0, 10, 20}, nil)
+
+				_, err := build(G)
+				Expect(err).To(MatchError(ContainSubstring("fleet limits")))
+			})
+
+			It("Fails on stops no hub can serve", func() {
+				closed := &network.HubNode{Val: 1, Hours: &network.OperatingHours{Open: 20 * time.Hour, Close: 21 * time.Hour}}
+				G := testNetwork([]*network.HubNode{closed}, []int{0}, nil)
+
+				_, err := build(G)
+				Expect(err).To(MatchError("No hub can serve stop 10."))
+			})
+		})
+	}
+
+	It("Only joins stops when some hub can serve the joined route", func() {
+		// Hub 1 dispatches only to stop 10 and hub 2 only to stop 11, and stop 11 returns only to hub 2, so 10 -> 11 can't be one route.
+		early := &network.HubNode{Val: 1, Hours: &network.OperatingHours{Open: 6 * time.Hour, Close: 8*time.Hour + 10*time.Minute}}
+		late := &network.HubNode{Val: 2, Hours: &network.OperatingHours{Open: 8*time.Hour + 10*time.Minute, Close: 20 * time.Hour}}
+		G := testNetwork([]*network.HubNode{early, late}, []int{0, 20}, [][2]int64{{10, 11}})
+
+		kept := make([]*network.DeliveryEdge, 0)
+		for _, edge := range G.DEdges[11] {
+			if edge.Dst.ID() != early.ID() {
+				kept = append(kept, edge)
+			}
+		}
+		G.DEdges[11] = kept
+
+		for _, build := range heuristics {
+			S, err := build(G)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(S.Vehicles()).To(Equal(2))
+		}
+	})
+
+	Describe("Gap", func() {
+		It("Measures the relative excess in vehicles", func() {
+			S := &routing.Solution{Routes: make([]routing.Route, 5)}
+			Expect(S.Gap(make([]routing.Route, 4))).To(Equal(0.25))
+			Expect(S.Gap(nil)).To(BeZero())
+		})
+	})
+})
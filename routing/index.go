@@ -0,0 +1,146 @@
+package routing
+
+import (
+	"math"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+// hubLink is an edge between a stop and a hub, in whichever direction the containing list implies.
+type hubLink struct {
+	hub    *network.HubNode
+	weight float64
+}
+
+// stopIndex gives the heuristics constant-time access to a network's stop-to-stop edges and hub links. Stops are referred to by position in timestamp order, as given by sortedStops.
+type stopIndex struct {
+	stops []*network.StopNode
+	succ  [][]int
+	pred  [][]int
+
+	// weight[i][j] is the weight of the edge from stop i to stop j.
+	weight []map[int]float64
+
+	// in[i] lists the hubs dispatching to stop i, and out[i] the hubs stop i returns to, both ordered by hub ID.
+	in  [][]hubLink
+	out [][]hubLink
+}
+
+func newStopIndex(G *network.DeliveryNetwork) *stopIndex {
+	x := &stopIndex{stops: sortedStops(G)}
+	n := len(x.stops)
+
+	pos := make(map[int64]int, n)
+	for i, stop := range x.stops {
+		pos[stop.ID()] = i
+	}
+
+	x.succ, x.pred = make([][]int, n), make([][]int, n)
+	x.weight = make([]map[int]float64, n)
+	x.in, x.out = make([][]hubLink, n), make([][]hubLink, n)
+
+	for i, stop := range x.stops {
+		x.weight[i] = make(map[int]float64, len(G.DEdges[stop.ID()]))
+
+		for _, edge := range G.DEdges[stop.ID()] {
+			switch edge.Kind() {
+			case network.ReturnEdge:
+				if hub, ok := G.Hubs[edge.Dst.ID()]; ok {
+					x.out[i] = append(x.out[i], hubLink{hub, edge.Wgt})
+				}
+			case network.StopLinkEdge:
+				if j, ok := pos[edge.Dst.ID()]; ok {
+					x.succ[i] = append(x.succ[i], j)
+					x.pred[j] = append(x.pred[j], i)
+					x.weight[i][j] = edge.Wgt
+				}
+			}
+		}
+	}
+
+	// Only dispatch edges leave hubs for stops; transfers and waiting arcs are left out.
+	for _, hub := range sortedHubs(G) {
+		for _, edge := range G.DEdges[hub.ID()] {
+			if j, ok := pos[edge.Dst.ID()]; ok && edge.Kind() == network.DispatchEdge {
+				x.in[j] = append(x.in[j], hubLink{hub, edge.Wgt})
+			}
+		}
+	}
+
+	for i := range x.out {
+		sortLinks(x.out[i])
+	}
+
+	return x
+}
+
+// sortLinks orders hub links by hub ID. Lists are short, so insertion sort will do.
+func sortLinks(links []hubLink) {
+	for i := 1; i < len(links); i++ {
+		for j := i; j > 0 && links[j].hub.ID() < links[j-1].hub.ID(); j-- {
+			links[j], links[j-1] = links[j-1], links[j]
+		}
+	}
+}
+
+// hubCost returns the cheapest combined weight of leaving some hub for stop head and returning to the same hub from stop tail, or +Inf if no hub can do both.
+func (x *stopIndex) hubCost(head, tail int) float64 {
+	best := math.Inf(1)
+
+	// Both lists are ordered by hub ID, so they can be intersected in a single pass.
+	in, out := x.in[head], x.out[tail]
+	for a, b := 0, 0; a < len(in) && b < len(out); {
+		switch ha, hb := in[a].hub.ID(), out[b].hub.ID(); {
+		case ha < hb:
+			a++
+		case ha > hb:
+			b++
+		default:
+			best = math.Min(best, in[a].weight+out[b].weight)
+			a++
+			b++
+		}
+	}
+
+	return best
+}
+
+// servable returns true if some hub can dispatch a vehicle to stop head and take it back from stop tail.
+func (x *stopIndex) servable(head, tail int) bool {
+	return !math.IsInf(x.hubCost(head, tail), 1)
+}
+
+// chainStops converts chains of stop positions into chains of stops.
+func (x *stopIndex) chainStops(chains [][]int) [][]*network.StopNode {
+	out := make([][]*network.StopNode, 0, len(chains))
+	for _, chain := range chains {
+		stops := make([]*network.StopNode, 0, len(chain))
+		for _, i := range chain {
+			stops = append(stops, x.stops[i])
+		}
+		out = append(out, stops)
+	}
+
+	return out
+}
+
+// edgeTable gives constant-time access to the weight of any edge in a network, keyed by source and then destination ID.
+type edgeTable map[int64]map[int64]float64
+
+func newEdgeTable(G *network.DeliveryNetwork) edgeTable {
+	table := make(edgeTable, len(G.DEdges))
+	for src, edges := range G.DEdges {
+		table[src] = make(map[int64]float64, len(edges))
+		for _, edge := range edges {
+			table[src][edge.Dst.ID()] = edge.Wgt
+		}
+	}
+
+	return table
+}
+
+// weight returns the weight of the edge from u to v, and false if there is no such edge.
+func (t edgeTable) weight(u, v int64) (float64, bool) {
+	w, ok := t[u][v]
+	return w, ok
+}
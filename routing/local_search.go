@@ -0,0 +1,359 @@
+package routing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+// minGain is the smallest weight reduction LocalSearch treats as an improvement, so that rounding error can't keep it cycling between equivalent solutions.
+const minGain = 1e-6
+
+// SearchConfig controls how long LocalSearch runs and what routes it may produce.
+type SearchConfig struct {
+	// Iterations caps the number of improving moves made. Zero means no cap.
+	Iterations uint
+
+	// Budget caps the wall-clock time spent searching. Zero means no cap.
+	Budget time.Duration
+
+	// Capacity, if positive, caps the peak load of every route, as given by Route.Load.
+	Capacity float64
+}
+
+// SearchResult is the improved solution found by LocalSearch, along with a record of how the search went.
+type SearchResult struct {
+	*Solution
+
+	// History holds the total weight of the routes before the search and after each move it made, so it is never increasing.
+	History []float64
+
+	// Converged is true if the search stopped because no move could improve the routes, rather than because it ran out of iterations or time.
+	Converged bool
+}
+
+// LocalSearch improves a set of routes over G by repeatedly applying the move that reduces total route weight the most, until no move helps or the configured iteration or time budget runs out. Three kinds of move are considered:
+//   - Relocate moves a stop to another position on its own route or on another route.
+//   - Swap exchanges two stops, on the same route or on different routes.
+//   - 2-opt reverses a run of stops within a route, or exchanges the tails of two routes after a cut in each. Stops visited at different times can't be reversed, so the tail exchange does most of the work.
+//
+// Every route produced uses only edges in G and visits its stops in timestamp order, keeps its hub and return hub, keeps each pickup-and-delivery pair together in order, and stays within cfg.Capacity. Routes emptied by relocation are dropped, so the search can reduce the number of vehicles but never increases any hub's dispatch count.
+//
+// Returns an error if the starting routes don't already satisfy those constraints. Routes in the result are ordered by the timestamp of their first stop.
+func LocalSearch(G *network.DeliveryNetwork, routes []Route, cfg SearchConfig) (*SearchResult, error) {
+	if cfg.Capacity < 0 {
+		return nil, fmt.Errorf("Vehicle capacity cannot be negative.")
+	}
+
+	if err := CheckPairs(G, routes); err != nil {
+		return nil, err
+	}
+
+	ls := &search{G: G, edges: newEdgeTable(G), capacity: cfg.Capacity}
+
+	for _, route := range routes {
+		if err := ls.check(route); err != nil {
+			return nil, err
+		}
+
+		ls.hubs = append(ls.hubs, route.Hub)
+		ls.returns = append(ls.returns, route.End())
+		ls.routes = append(ls.routes, append([]*network.StopNode{}, route.Stops...))
+
+		cost, _ := ls.cost(len(ls.routes)-1, route.Stops)
+		ls.costs = append(ls.costs, cost)
+	}
+
+	var deadline time.Time
+	if cfg.Budget > 0 {
+		deadline = time.Now().Add(cfg.Budget)
+	}
+
+	result := &SearchResult{History: []float64{ls.total()}}
+
+	for cfg.Iterations == 0 || uint(len(result.History)-1) < cfg.Iterations {
+		best, finished := ls.bestMove(deadline)
+		if !finished {
+			break
+		}
+
+		if best == nil {
+			result.Converged = true
+			break
+		}
+
+		ls.apply(best)
+		result.History = append(result.History, ls.total())
+	}
+
+	improved := make([]Route, 0, len(ls.routes))
+	for r, stops := range ls.routes {
+		if len(stops) == 0 {
+			continue
+		}
+
+		route := Route{Hub: ls.hubs[r], Stops: stops}
+		if ls.returns[r] != ls.hubs[r] {
+			route.Return = ls.returns[r]
+		}
+		improved = append(improved, route)
+	}
+
+	sortRoutes(improved)
+
+	S, err := NewSolution(G, improved)
+	if err != nil {
+		return nil, err
+	}
+
+	result.Solution = S
+	return result, nil
+}
+
+// search holds the routes under improvement in LocalSearch. Emptied routes stay in place with no stops.
+type search struct {
+	G        *network.DeliveryNetwork
+	edges    edgeTable
+	capacity float64
+
+	hubs    []*network.HubNode
+	returns []*network.HubNode
+	routes  [][]*network.StopNode
+	costs   []float64
+}
+
+// move replaces the stops of routes a and b, which may be the same route, and reduces total weight by gain.
+type move struct {
+	a, b   int
+	ra, rb []*network.StopNode
+	ca, cb float64
+	gain   float64
+}
+
+// total returns the combined weight of the routes.
+func (ls *search) total() float64 {
+	total := 0.0
+	for _, cost := range ls.costs {
+		total += cost
+	}
+
+	return total
+}
+
+// cost returns the weight of serving stops in order on route r, keeping its hubs, and false if doing so breaks any of LocalSearch's constraints.
+func (ls *search) cost(r int, stops []*network.StopNode) (float64, bool) {
+	if len(stops) == 0 {
+		return 0.0, true
+	}
+
+	if ls.capacity > 0 && chainLoad(stops) > ls.capacity {
+		return 0.0, false
+	}
+
+	total, ok := ls.edges.weight(ls.hubs[r].ID(), stops[0].ID())
+	if !ok {
+		return 0.0, false
+	}
+
+	for k := 1; k < len(stops); k++ {
+		if stops[k].Timestamp.Before(stops[k-1].Timestamp) {
+			return 0.0, false
+		}
+
+		w, ok := ls.edges.weight(stops[k-1].ID(), stops[k].ID())
+		if !ok {
+			return 0.0, false
+		}
+		total += w
+	}
+
+	w, ok := ls.edges.weight(stops[len(stops)-1].ID(), ls.returns[r].ID())
+	if !ok || !ls.pairsIntact(stops) {
+		return 0.0, false
+	}
+
+	return total + w, true
+}
+
+// pairsIntact returns true if every paired stop in stops has its partner on the same route, pickup first.
+func (ls *search) pairsIntact(stops []*network.StopNode) bool {
+	if len(ls.G.Pairs) == 0 {
+		return true
+	}
+
+	pos := make(map[int64]int)
+	for k, stop := range stops {
+		if _, ok := ls.G.Pairs[stop.ID()]; ok {
+			pos[stop.ID()] = k
+		}
+	}
+
+	for id, k := range pos {
+		stop := ls.G.Pairs[id]
+
+		partner, ok := pos[stop.Partner.ID()]
+		if !ok || (stop.IsPickup() && partner < k) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// check explains why a starting route breaks one of LocalSearch's constraints, if it does. Pairs are checked across all routes beforehand.
+func (ls *search) check(route Route) error {
+	if _, err := route.Weight(ls.G); err != nil {
+		return err
+	}
+
+	for k := 1; k < len(route.Stops); k++ {
+		if route.Stops[k].Timestamp.Before(route.Stops[k-1].Timestamp) {
+			return fmt.Errorf("Route visits stop %d after the later stop %d.", route.Stops[k].ID(), route.Stops[k-1].ID())
+		}
+	}
+
+	if load := route.Load(); ls.capacity > 0 && load > ls.capacity {
+		return fmt.Errorf("Route from hub %d carries %g, more than a vehicle's capacity of %g.", route.Hub.ID(), load, ls.capacity)
+	}
+
+	return nil
+}
+
+// bestMove returns the move that reduces total weight the most, or nil if none does. If the deadline passes before every move has been considered, bestMove gives up and returns false.
+func (ls *search) bestMove(deadline time.Time) (*move, bool) {
+	var best *move
+
+	consider := func(a, b int, ra, rb []*network.StopNode) {
+		ca, ok := ls.cost(a, ra)
+		if !ok {
+			return
+		}
+
+		gain := ls.costs[a] - ca
+		cb := 0.0
+
+		if a != b {
+			if cb, ok = ls.cost(b, rb); !ok {
+				return
+			}
+			gain += ls.costs[b] - cb
+		}
+
+		if gain > minGain && (best == nil || gain > best.gain) {
+			best = &move{a: a, b: b, ra: ra, rb: rb, ca: ca, cb: cb, gain: gain}
+		}
+	}
+
+	for a := range ls.routes {
+		if !deadline.IsZero() && time.Now().After(deadline) {
+			return nil, false
+		}
+
+		A := ls.routes[a]
+
+		ls.reverse(a, A, consider)
+
+		for b := a; b < len(ls.routes); b++ {
+			B := ls.routes[b]
+
+			ls.relocate(a, b, A, B, consider)
+			if b != a {
+				ls.relocate(b, a, B, A, consider)
+				ls.exchangeTails(a, b, A, B, consider)
+			}
+			ls.swap(a, b, A, B, consider)
+		}
+	}
+
+	return best, true
+}
+
+// relocate considers moving each stop of route a to each position of route b.
+func (ls *search) relocate(a, b int, A, B []*network.StopNode, consider func(a, b int, ra, rb []*network.StopNode)) {
+	for i := range A {
+		rest := splice(A[:i], A[i+1:])
+
+		if a == b {
+			for j := 0; j <= len(rest); j++ {
+				if j != i {
+					consider(a, a, splice(rest[:j], []*network.StopNode{A[i]}, rest[j:]), nil)
+				}
+			}
+			continue
+		}
+
+		for j := 0; j <= len(B); j++ {
+			consider(a, b, rest, splice(B[:j], []*network.StopNode{A[i]}, B[j:]))
+		}
+	}
+}
+
+// swap considers exchanging each stop of route a with each stop of route b.
+func (ls *search) swap(a, b int, A, B []*network.StopNode, consider func(a, b int, ra, rb []*network.StopNode)) {
+	for i := range A {
+		if a == b {
+			for j := i + 1; j < len(A); j++ {
+				ra := splice(A)
+				ra[i], ra[j] = ra[j], ra[i]
+				consider(a, a, ra, nil)
+			}
+			continue
+		}
+
+		for j := range B {
+			ra, rb := splice(A), splice(B)
+			ra[i], rb[j] = B[j], A[i]
+			consider(a, b, ra, rb)
+		}
+	}
+}
+
+// reverse considers reversing each run of stops within route a. Only runs of stops sharing a timestamp can be reversed in time order, so longer runs are skipped.
+func (ls *search) reverse(a int, A []*network.StopNode, consider func(a, b int, ra, rb []*network.StopNode)) {
+	for i := range A {
+		for j := i + 1; j < len(A) && A[j].Timestamp.Equal(A[i].Timestamp); j++ {
+			ra := splice(A)
+			for lo, hi := i, j; lo < hi; lo, hi = lo+1, hi-1 {
+				ra[lo], ra[hi] = ra[hi], ra[lo]
+			}
+			consider(a, a, ra, nil)
+		}
+	}
+}
+
+// exchangeTails considers cutting routes a and b and swapping the stops after each cut. Cutting one route before its first stop and the other after its last appends one route to the other.
+func (ls *search) exchangeTails(a, b int, A, B []*network.StopNode, consider func(a, b int, ra, rb []*network.StopNode)) {
+	for i := 0; i <= len(A); i++ {
+		for j := 0; j <= len(B); j++ {
+			if i == len(A) && j == len(B) {
+				continue
+			}
+
+			consider(a, b, splice(A[:i], B[j:]), splice(B[:j], A[i:]))
+		}
+	}
+}
+
+// apply makes a move.
+func (ls *search) apply(m *move) {
+	ls.routes[m.a], ls.costs[m.a] = m.ra, m.ca
+	if m.b != m.a {
+		ls.routes[m.b], ls.costs[m.b] = m.rb, m.cb
+	}
+}
+
+// splice concatenates runs of stops into a new slice.
+func splice(runs ...[]*network.StopNode) []*network.StopNode {
+	n := 0
+	for _, run := range runs {
+		n += len(run)
+	}
+
+	out := make([]*network.StopNode, 0, n)
+	for _, run := range runs {
+		out = append(out, run...)
+	}
+
+	return out
+}
@@ -0,0 +1,154 @@
+package routing_test
+
+import (
+	"math/rand"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bdshroyer/burrow"
+	"github.com/bdshroyer/burrow/network"
+	"github.com/bdshroyer/burrow/routing"
+)
+
+// singletons puts every stop of G on a route of its own from hub, in ID order.
+func singletons(G *network.DeliveryNetwork, hub *network.HubNode, ids ...int64) []routing.Route {
+	routes := make([]routing.Route, 0, len(ids))
+	for _, id := range ids {
+		routes = append(routes, routing.Route{Hub: hub, Stops: []*network.StopNode{G.Stops[id]}})
+	}
+	return routes
+}
+
+var _ = Describe("LocalSearch", func() {
+	var hub *network.HubNode
+
+	BeforeEach(func() {
+		hub = &network.HubNode{Val: 1}
+	})
+
+	It("Joins routes when that saves weight and records each step", func() {
+		G := testNetwork([]*network.HubNode{hub}, []int{0, 10, 20}, completeLinks(3))
+
+		result, err := routing.LocalSearch(G, singletons(G, hub, 10, 11, 12), routing.SearchConfig{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Converged).To(BeTrue())
+		Expect(result.Vehicles()).To(Equal(1))
+		Expect(stopIDs(result.Routes[0])).To(Equal([]int64{10, 11, 12}))
+
+		Expect(result.History).To(Equal([]float64{
+			float64(6 * time.Hour),
+			float64(4*time.Hour + 10*time.Minute),
+			float64(2*time.Hour + 20*time.Minute),
+		}))
+		Expect(result.Weight).To(Equal(result.History[len(result.History)-1]))
+	})
+
+	It("Stops after the configured number of moves", func() {
+		G := testNetwork([]*network.HubNode{hub}, []int{0, 10, 20}, completeLinks(3))
+
+		result, err := routing.LocalSearch(G, singletons(G, hub, 10, 11, 12), routing.SearchConfig{Iterations: 1})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Converged).To(BeFalse())
+		Expect(result.History).To(HaveLen(2))
+		Expect(result.Vehicles()).To(Equal(2))
+	})
+
+	It("Stops when the time budget runs out", func() {
+		G := testNetwork([]*network.HubNode{hub}, []int{0, 10, 20}, completeLinks(3))
+
+		result, err := routing.LocalSearch(G, singletons(G, hub, 10, 11, 12), routing.SearchConfig{Budget: time.Nanosecond})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Converged).To(BeFalse())
+		Expect(result.Vehicles()).To(Equal(3))
+	})
+
+	It("Keeps routes within capacity", func() {
+		G := testNetwork([]*network.HubNode{hub}, []int{0, 10, 20}, completeLinks(3))
+		for _, stop := range G.Stops {
+			stop.Demand = 3
+		}
+
+		result, err := routing.LocalSearch(G, singletons(G, hub, 10, 11, 12), routing.SearchConfig{Capacity: 7})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Vehicles()).To(Equal(2))
+
+		for _, route := range result.Routes {
+			Expect(route.Load()).To(BeNumerically("<=", 7))
+		}
+	})
+
+	It("Keeps pickup-and-delivery pairs together and in order", func() {
+		G := testNetwork([]*network.HubNode{hub}, []int{15}, nil)
+		pickup, dropoff := network.NewPair(
+			network.StopNode{Val: 20, Timestamp: t0},
+			network.StopNode{Val: 21, Timestamp: t0.Add(30 * time.Minute)},
+		)
+		G.AddPair(pickup)
+
+		link := func(src, dst *network.StopNode, wgt time.Duration) {
+			G.DEdges[src.ID()] = append(G.DEdges[src.ID()], &network.DeliveryEdge{Src: src, Dst: dst, Wgt: float64(wgt)})
+		}
+		link(&pickup.StopNode, G.Stops[10], 15*time.Minute)
+		link(G.Stops[10], &dropoff.StopNode, 15*time.Minute)
+		link(&pickup.StopNode, &dropoff.StopNode, 30*time.Minute)
+		G.DEdges[hub.ID()] = append(G.DEdges[hub.ID()], &network.DeliveryEdge{Src: hub, Dst: &pickup.StopNode, Wgt: float64(time.Hour)})
+		G.DEdges[dropoff.ID()] = append(G.DEdges[dropoff.ID()], &network.DeliveryEdge{Src: &dropoff.StopNode, Dst: hub, Wgt: float64(time.Hour)})
+
+		routes := []routing.Route{
+			{Hub: hub, Stops: []*network.StopNode{G.Stops[20], G.Stops[21]}},
+			{Hub: hub, Stops: []*network.StopNode{G.Stops[10]}},
+		}
+
+		result, err := routing.LocalSearch(G, routes, routing.SearchConfig{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Vehicles()).To(Equal(1))
+		Expect(stopIDs(result.Routes[0])).To(Equal([]int64{20, 10, 21}))
+		Expect(routing.CheckPairs(G, result.Routes)).To(Succeed())
+	})
+
+	It("Rejects starting routes that break its constraints", func() {
+		G := testNetwork([]*network.HubNode{hub}, []int{0, 10}, [][2]int64{{11, 10}})
+
+		routes := []routing.Route{{Hub: hub, Stops: []*network.StopNode{G.Stops[11], G.Stops[10]}}}
+		_, err := routing.LocalSearch(G, routes, routing.SearchConfig{})
+		Expect(err).To(MatchError("Route visits stop 10 after the later stop 11."))
+
+		routes = []routing.Route{{Hub: hub, Stops: []*network.StopNode{G.Stops[10], G.Stops[11]}}}
+		_, err = routing.LocalSearch(G, routes, routing.SearchConfig{})
+		Expect(err).To(MatchError("Route has no edge from 10 to 11."))
+
+		_, err = routing.LocalSearch(G, nil, routing.SearchConfig{Capacity: -1})
+		Expect(err).To(MatchError("Vehicle capacity cannot be negative."))
+	})
+
+	It("Improves heuristic routes over a generated network without breaking them", func() {
+		distro, err := burrow.UniformTimestampDistribution(t0, 12*time.Hour)
+		Expect(err).NotTo(HaveOccurred())
+
+		rand.Seed(37)
+		G, err := burrow.MakeDeliveryNetwork(burrow.DeliveryNetworkConfig{
+			HubNodes:   2,
+			StopNodes:  60,
+			Distro:     distro,
+			EdgeBounds: &burrow.TimeBox{5 * time.Minute, 40 * time.Minute},
+		})
+		Expect(err).NotTo(HaveOccurred())
+
+		start, err := routing.NearestNext(G)
+		Expect(err).NotTo(HaveOccurred())
+
+		result, err := routing.LocalSearch(G, start.Routes, routing.SearchConfig{})
+		Expect(err).NotTo(HaveOccurred())
+		Expect(result.Converged).To(BeTrue())
+		Expect(result.Weight).To(BeNumerically("<=", start.Weight))
+		Expect(result.Vehicles()).To(BeNumerically("<=", start.Vehicles()))
+		Expect(routing.CheckFleet(result.Routes)).To(Succeed())
+		expectCover(G, result.Routes)
+
+		for i := 1; i < len(result.History); i++ {
+			Expect(result.History[i]).To(BeNumerically("<", result.History[i-1]))
+		}
+	})
+})
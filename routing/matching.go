@@ -0,0 +1,75 @@
+package routing
+
+// unmatched marks a vertex with no partner in a matching.
+const unmatched = -1
+
+// maxMatching finds a maximum matching in a bipartite graph using the Hopcroft-Karp algorithm. adj[u] lists the right-hand vertices adjacent to left-hand vertex u, and every right-hand vertex must be less than nRight.
+// The result is indexed by left-hand vertex and holds its partner, or unmatched. Neighbours are tried in the order adj lists them, so the matching is deterministic.
+func maxMatching(adj [][]int, nRight int) []int {
+	matchL := make([]int, len(adj))
+	matchR := make([]int, nRight)
+	for u := range matchL {
+		matchL[u] = unmatched
+	}
+	for v := range matchR {
+		matchR[v] = unmatched
+	}
+
+	dist := make([]int, len(adj))
+
+	// bfs layers the free left-hand vertices and reports whether an augmenting path exists.
+	bfs := func() bool {
+		queue := make([]int, 0, len(adj))
+		for u := range adj {
+			if matchL[u] == unmatched {
+				dist[u] = 0
+				queue = append(queue, u)
+			} else {
+				dist[u] = -1
+			}
+		}
+
+		found := false
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+
+			for _, v := range adj[u] {
+				w := matchR[v]
+				if w == unmatched {
+					found = true
+				} else if dist[w] < 0 {
+					dist[w] = dist[u] + 1
+					queue = append(queue, w)
+				}
+			}
+		}
+
+		return found
+	}
+
+	var dfs func(u int) bool
+	dfs = func(u int) bool {
+		for _, v := range adj[u] {
+			w := matchR[v]
+			if w == unmatched || (dist[w] == dist[u]+1 && dfs(w)) {
+				matchL[u], matchR[v] = v, u
+				return true
+			}
+		}
+
+		// Dead end: drop u from this phase.
+		dist[u] = -1
+		return false
+	}
+
+	for bfs() {
+		for u := range adj {
+			if matchL[u] == unmatched {
+				dfs(u)
+			}
+		}
+	}
+
+	return matchL
+}
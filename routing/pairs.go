@@ -0,0 +1,50 @@
+package routing
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+// CheckPairs returns an error unless every pickup-and-delivery pair in G is served by a single route that visits the pickup before the dropoff. Pairs neither of whose stops appear in routes are ignored.
+func CheckPairs(G *network.DeliveryNetwork, routes []Route) error {
+	type visit struct{ route, pos int }
+
+	visits := make(map[int64]visit)
+	for r, route := range routes {
+		for pos, stop := range route.Stops {
+			visits[stop.ID()] = visit{r, pos}
+		}
+	}
+
+	// Pickups are checked in ID order so the error reported doesn't depend on map iteration order.
+	pickups := make([]*network.PairedStopNode, 0, len(G.Pairs)/2)
+	for _, stop := range G.Pairs {
+		if stop.IsPickup() {
+			pickups = append(pickups, stop)
+		}
+	}
+	sort.Slice(pickups, func(i, j int) bool { return pickups[i].ID() < pickups[j].ID() })
+
+	for _, pickup := range pickups {
+		dropoff := pickup.Partner
+		p, pOk := visits[pickup.ID()]
+		d, dOk := visits[dropoff.ID()]
+
+		switch {
+		case !pOk && !dOk:
+			continue
+		case !pOk:
+			return fmt.Errorf("Dropoff %d is served, but its pickup %d is not.", dropoff.ID(), pickup.ID())
+		case !dOk:
+			return fmt.Errorf("Pickup %d is served, but its dropoff %d is not.", pickup.ID(), dropoff.ID())
+		case p.route != d.route:
+			return fmt.Errorf("Pickup %d and dropoff %d are served by different vehicles.", pickup.ID(), dropoff.ID())
+		case p.pos > d.pos:
+			return fmt.Errorf("Dropoff %d is visited before its pickup %d.", dropoff.ID(), pickup.ID())
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,84 @@
+package routing_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bdshroyer/burrow/network"
+	"github.com/bdshroyer/burrow/routing"
+)
+
+var _ = Describe("CheckPairs", func() {
+	var (
+		G      *network.DeliveryNetwork
+		hub    *network.HubNode
+		pickup *network.PairedStopNode
+		other  *network.StopNode
+	)
+
+	BeforeEach(func() {
+		hub = &network.HubNode{Val: 1}
+		G = testNetwork([]*network.HubNode{hub}, []int{15}, nil)
+		other = G.Stops[10]
+
+		pickup, _ = network.NewPair(
+			network.StopNode{Val: 20, Timestamp: t0},
+			network.StopNode{Val: 21, Timestamp: t0.Add(30 * time.Minute)},
+		)
+		G.AddPair(pickup)
+	})
+
+	It("Accepts a pair served in order by one vehicle", func() {
+		routes := []routing.Route{{Hub: hub, Stops: []*network.StopNode{G.Stops[20], other, G.Stops[21]}}}
+		Expect(routing.CheckPairs(G, routes)).To(Succeed())
+	})
+
+	It("Ignores pairs that no route serves", func() {
+		routes := []routing.Route{{Hub: hub, Stops: []*network.StopNode{other}}}
+		Expect(routing.CheckPairs(G, routes)).To(Succeed())
+	})
+
+	It("Rejects a pair split across vehicles", func() {
+		routes := []routing.Route{
+			{Hub: hub, Stops: []*network.StopNode{G.Stops[20]}},
+			{Hub: hub, Stops: []*network.StopNode{G.Stops[21]}},
+		}
+		Expect(routing.CheckPairs(G, routes)).To(MatchError("Pickup 20 and dropoff 21 are served by different vehicles."))
+	})
+
+	It("Rejects a dropoff visited before its pickup", func() {
+		routes := []routing.Route{{Hub: hub, Stops: []*network.StopNode{G.Stops[21], G.Stops[20]}}}
+		Expect(routing.CheckPairs(G, routes)).To(MatchError("Dropoff 21 is visited before its pickup 20."))
+	})
+
+	It("Rejects a pair only half served", func() {
+		routes := []routing.Route{{Hub: hub, Stops: []*network.StopNode{G.Stops[20]}}}
+		Expect(routing.CheckPairs(G, routes)).To(MatchError("Pickup 20 is served, but its dropoff 21 is not."))
+
+		routes = []routing.Route{{Hub: hub, Stops: []*network.StopNode{G.Stops[21]}}}
+		Expect(routing.CheckPairs(G, routes)).To(MatchError("Dropoff 21 is served, but its pickup 20 is not."))
+	})
+
+	It("Keeps the path covers from splitting pairs", func() {
+		_, err := routing.MinPathCover(G)
+		Expect(err).To(MatchError("MinPathCover can't keep pickup-and-delivery pairs on one route, and G has 2 paired stops."))
+
+		_, err = routing.CapacitatedPathCover(G, 10)
+		Expect(err).To(MatchError("CapacitatedPathCover can't keep pickup-and-delivery pairs on one route, and G has 2 paired stops."))
+	})
+
+	It("Keeps the heuristics from splitting pairs", func() {
+		heuristics := map[string]func(*network.DeliveryNetwork) (*routing.Solution, error){
+			"NearestNext":       routing.NearestNext,
+			"CheapestInsertion": routing.CheapestInsertion,
+			"Savings":           routing.Savings,
+		}
+
+		for name, heuristic := range heuristics {
+			_, err := heuristic(G)
+			Expect(err).To(MatchError(name + " can't keep pickup-and-delivery pairs on one route, and G has 2 paired stops."))
+		}
+	})
+})
@@ -0,0 +1,235 @@
+package routing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+// MinPathCover splits G's stops into the fewest chains of stop-to-stop edges such that every stop lies on exactly one chain, then dispatches each chain from a hub that has an edge to its first stop and an edge back from its last stop. The result is the smallest number of vehicles that can serve every stop, together with their routes.
+//
+// Chains are found as a maximum matching between stops, so the cover is always minimal. Hubs are then matched to chains within their dispatch limits. If no assignment of hubs fits the limits, an error is returned; note that this can happen even when a larger cover, with shorter chains, could have been dispatched.
+//
+// The matching can't keep a pickup and its dropoff on the same chain, so networks with pickup-and-delivery pairs are rejected; use MinFleet and CheckPairs, or LocalSearch from routes that keep pairs together, instead.
+//
+// Routes are ordered by the timestamp of their first stop.
+func MinPathCover(G *network.DeliveryNetwork) ([]Route, error) {
+	if err := rejectPairs(G, "MinPathCover"); err != nil {
+		return nil, err
+	}
+
+	chains := minChains(G, sortedStops(G))
+	return dispatch(G, chains)
+}
+
+// rejectPairs returns an error naming the caller if G has any pickup-and-delivery pairs.
+func rejectPairs(G *network.DeliveryNetwork, caller string) error {
+	if len(G.Pairs) > 0 {
+		return fmt.Errorf("%s can't keep pickup-and-delivery pairs on one route, and G has %d paired stops.", caller, len(G.Pairs))
+	}
+
+	return nil
+}
+
+// stopAdjacency indexes stops by position and lists, for each stop, the positions of the stops its stop-to-stop edges lead to.
+func stopAdjacency(G *network.DeliveryNetwork, stops []*network.StopNode) [][]int {
+	index := make(map[int64]int, len(stops))
+	for i, stop := range stops {
+		index[stop.ID()] = i
+	}
+
+	adj := make([][]int, len(stops))
+	for i, stop := range stops {
+		for _, edge := range G.DEdges[stop.ID()] {
+			if j, ok := index[edge.Dst.ID()]; ok && edge.Kind() == network.StopLinkEdge {
+				adj[i] = append(adj[i], j)
+			}
+		}
+	}
+
+	return adj
+}
+
+// minChains finds a minimum set of vertex-disjoint chains covering stops. Each stop is split into an outgoing and an incoming copy, and every matched pair becomes a link in some chain, so maximising the matching minimises the number of chains.
+func minChains(G *network.DeliveryNetwork, stops []*network.StopNode) [][]*network.StopNode {
+	next := maxMatching(stopAdjacency(G, stops), len(stops))
+
+	hasPrev := make([]bool, len(stops))
+	for _, j := range next {
+		if j != unmatched {
+			hasPrev[j] = true
+		}
+	}
+
+	chains := make([][]*network.StopNode, 0)
+	for i := range stops {
+		if hasPrev[i] {
+			continue
+		}
+
+		chain := make([]*network.StopNode, 0)
+		for k := i; k != unmatched; k = next[k] {
+			chain = append(chain, stops[k])
+		}
+		chains = append(chains, chain)
+	}
+
+	return chains
+}
+
+// dispatch assigns a hub to every chain, respecting each hub's dispatch limit. A hub can serve a chain if it has an edge to the chain's first stop and an edge back from its last.
+//
+// Each chain first goes to the hub that can serve it with the most dispatches left, taking hubs without a limit first and breaking ties by ID. Chains left over are then fitted in by moving earlier chains to other hubs that can serve them, along the shortest such sequence of moves, so a hub assignment is found whenever one exists. Time and memory grow with the number of chains times the number of hubs serving each.
+func dispatch(G *network.DeliveryNetwork, chains [][]*network.StopNode) ([]Route, error) {
+	hubs := sortedHubs(G)
+
+	// remaining[h] is the number of routes hub h may still dispatch; hubs without a limit never run out.
+	remaining := make([]int, len(hubs))
+	dispatches := make([]map[int64]bool, len(hubs))
+	hubPos := make(map[int64]int, len(hubs))
+
+	for h, hub := range hubs {
+		remaining[h] = math.MaxInt
+		if limit, ok := hub.DispatchLimit(); ok {
+			remaining[h] = int(limit)
+		}
+
+		dispatches[h] = make(map[int64]bool, len(G.DEdges[hub.ID()]))
+		for _, edge := range G.DEdges[hub.ID()] {
+			if edge.Kind() == network.DispatchEdge {
+				dispatches[h][edge.Dst.ID()] = true
+			}
+		}
+
+		hubPos[hub.ID()] = h
+	}
+
+	// servers[c] lists the hubs that can serve chain c, in ID order.
+	servers := make([][]int, len(chains))
+	for c, chain := range chains {
+		first, last := chain[0], chain[len(chain)-1]
+
+		for _, edge := range G.DEdges[last.ID()] {
+			if h, ok := hubPos[edge.Dst.ID()]; ok && edge.Kind() == network.ReturnEdge && dispatches[h][first.ID()] {
+				servers[c] = append(servers[c], h)
+			}
+		}
+		sort.Ints(servers[c])
+
+		if len(servers[c]) == 0 {
+			return nil, fmt.Errorf("No hub can serve the route running from stop %d to stop %d.", first.ID(), last.ID())
+		}
+	}
+
+	a := newHubAssignment(len(chains), len(hubs), servers, remaining)
+
+	for c := range chains {
+		best := unmatched
+		for _, h := range servers[c] {
+			if remaining[h] > 0 && (best == unmatched || remaining[h] > remaining[best]) {
+				best = h
+			}
+		}
+
+		if best != unmatched {
+			a.assign(c, best)
+			remaining[best]--
+		}
+	}
+
+	for c := range chains {
+		if a.hub[c] == unmatched && !a.augment(c) {
+			return nil, fmt.Errorf("Covering every stop takes %d routes, more than the hubs' fleet limits allow.", len(chains))
+		}
+	}
+
+	routes := make([]Route, 0, len(chains))
+	for c, chain := range chains {
+		routes = append(routes, Route{Hub: hubs[a.hub[c]], Stops: chain})
+	}
+
+	return routes, nil
+}
+
+// hubAssignment tracks which hub serves each chain in dispatch, and which chains each hub serves.
+type hubAssignment struct {
+	servers   [][]int
+	remaining []int
+
+	// hub[c] is the hub serving chain c, or unmatched. members[h] lists the chains hub h serves, and pos[c] is chain c's index in its hub's list.
+	hub     []int
+	members [][]int
+	pos     []int
+}
+
+func newHubAssignment(nChains, nHubs int, servers [][]int, remaining []int) *hubAssignment {
+	a := &hubAssignment{
+		servers:   servers,
+		remaining: remaining,
+		hub:       make([]int, nChains),
+		members:   make([][]int, nHubs),
+		pos:       make([]int, nChains),
+	}
+
+	for c := range a.hub {
+		a.hub[c] = unmatched
+	}
+
+	return a
+}
+
+// assign moves chain c to hub h, taking it off any hub that served it before. Dispatch limits are left to the caller.
+func (a *hubAssignment) assign(c, h int) {
+	if old := a.hub[c]; old != unmatched {
+		list := a.members[old]
+		last := list[len(list)-1]
+		list[a.pos[c]], a.pos[last] = last, a.pos[c]
+		a.members[old] = list[:len(list)-1]
+	}
+
+	a.hub[c], a.pos[c] = h, len(a.members[h])
+	a.members[h] = append(a.members[h], c)
+}
+
+// augment fits the unassigned chain c in by a breadth-first search over hubs: from a full hub, any chain it serves may move to another hub that can serve it. The search ends at the first hub with a dispatch to spare, and the chains along the way are moved down the path. Returns false if no hub can be reached that way.
+func (a *hubAssignment) augment(c int) bool {
+	// via[h] is the chain moved onto hub h along the search path, and from[h] the hub it leaves, or unmatched for c itself.
+	via, from := make([]int, len(a.members)), make([]int, len(a.members))
+	for h := range via {
+		via[h] = unmatched
+	}
+
+	queue := make([]int, 0, len(a.members))
+	for _, h := range a.servers[c] {
+		via[h], from[h] = c, unmatched
+		queue = append(queue, h)
+	}
+
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+
+		if a.remaining[h] > 0 {
+			a.remaining[h]--
+			for h != unmatched {
+				next := from[h]
+				a.assign(via[h], h)
+				h = next
+			}
+			return true
+		}
+
+		for _, member := range a.members[h] {
+			for _, other := range a.servers[member] {
+				if via[other] == unmatched {
+					via[other], from[other] = member, h
+					queue = append(queue, other)
+				}
+			}
+		}
+	}
+
+	return false
+}
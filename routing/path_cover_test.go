@@ -0,0 +1,179 @@
+package routing_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bdshroyer/burrow/network"
+	"github.com/bdshroyer/burrow/routing"
+)
+
+var t0 = time.Date(2022, 3, 28, 8, 0, 0, 0, time.UTC)
+
+// testNetwork builds a network whose stops get IDs 10, 11, ... and timestamps the given number of minutes after t0. Every hub is linked both ways to every stop, except that hubs don't dispatch to stops outside their hours. Stop-to-stop edges are given as ID pairs and weighted by the time between the stops.
+func testNetwork(hubs []*network.HubNode, minutes []int, links [][2]int64) *network.DeliveryNetwork {
+	G := network.NewDeliveryNetwork()
+
+	for _, hub := range hubs {
+		G.Hubs[hub.ID()] = hub
+	}
+
+	for i, m := range minutes {
+		stop := &network.StopNode{Val: int64(10 + i), Timestamp: t0.Add(time.Duration(m) * time.Minute)}
+		G.Stops[stop.ID()] = stop
+
+		for _, hub := range hubs {
+			if hub.IsOpen(stop.Timestamp) {
+				G.DEdges[hub.ID()] = append(G.DEdges[hub.ID()], &network.DeliveryEdge{Src: hub, Dst: stop, Wgt: float64(time.Hour)})
+			}
+			G.DEdges[stop.ID()] = append(G.DEdges[stop.ID()], &network.DeliveryEdge{Src: stop, Dst: hub, Wgt: float64(time.Hour)})
+		}
+	}
+
+	for _, link := range links {
+		src, dst := G.Stops[link[0]], G.Stops[link[1]]
+		G.DEdges[src.ID()] = append(G.DEdges[src.ID()], &network.DeliveryEdge{Src: src, Dst: dst, Wgt: float64(dst.Timestamp.Sub(src.Timestamp))})
+	}
+
+	return G
+}
+
+// stopIDs lists the IDs of a route's stops in order.
+func stopIDs(route routing.Route) []int64 {
+	ids := make([]int64, 0, len(route.Stops))
+	for _, stop := range route.Stops {
+		ids = append(ids, stop.ID())
+	}
+	return ids
+}
+
+var _ = Describe("MinPathCover", func() {
+	// Two chains are needed: 10 -> 11 -> 13 and 12 -> 14, or some equivalent split.
+	links := [][2]int64{{10, 11}, {11, 13}, {10, 12}, {12, 14}, {11, 14}}
+	minutes := []int{0, 10, 20, 30, 40}
+
+	It("Covers every stop with the fewest routes", func() {
+		G := testNetwork([]*network.HubNode{{Val: 1}}, minutes, links)
+
+		routes, err := routing.MinPathCover(G)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(routes).To(HaveLen(2))
+
+		seen := map[int64]bool{}
+		for _, route := range routes {
+			Expect(route.Hub.ID()).To(BeEquivalentTo(1))
+
+			for i, stop := range route.Stops {
+				Expect(seen).NotTo(HaveKey(stop.ID()))
+				seen[stop.ID()] = true
+
+				if i > 0 {
+					Expect(G.HasEdgeFromTo(route.Stops[i-1].ID(), stop.ID())).To(BeTrue())
+				}
+			}
+		}
+		Expect(seen).To(HaveLen(5))
+	})
+
+	It("Needs one route per stop when no stops are linked", func() {
+		G := testNetwork([]*network.HubNode{{Val: 1}}, minutes, nil)
+
+		routes, err := routing.MinPathCover(G)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(routes).To(HaveLen(5))
+		Expect(stopIDs(routes[0])).To(Equal([]int64{10}))
+	})
+
+	It("Ignores hub-to-hub transfers", func() {
+		hubs := []*network.HubNode{{Val: 1}, {Val: 2}}
+		G := testNetwork(hubs, minutes, links)
+		G.DEdges[1] = append(G.DEdges[1], &network.DeliveryEdge{Src: hubs[0], Dst: hubs[1], Wgt: float64(time.Hour), Knd: network.TransferEdge})
+		G.DEdges[2] = append(G.DEdges[2], &network.DeliveryEdge{Src: hubs[1], Dst: hubs[0], Wgt: float64(time.Hour), Knd: network.TransferEdge})
+
+		routes, err := routing.MinPathCover(G)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(routes).To(HaveLen(2))
+
+		fleet, err := routing.MinFleet(G)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(fleet.Routes).To(HaveLen(2))
+	})
+
+	It("Spreads routes across hubs to stay within fleet limits", func() {
+		G := testNetwork([]*network.HubNode{{Val: 1, Fleet: 1}, {Val: 2, Capacity: 1}}, minutes, links)
+
+		routes, err := routing.MinPathCover(G)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(routes).To(HaveLen(2))
+		Expect(routing.CheckFleet(routes)).To(Succeed())
+		Expect(routes[0].Hub).NotTo(BeIdenticalTo(routes[1].Hub))
+	})
+
+	It("Fails when the fleet is too small to cover every stop", func() {
+		G := testNetwork([]*network.HubNode{{Val: 1, Fleet: 1}}, minutes, links)
+
+		_, err := routing.MinPathCover(G)
+		Expect(err).To(MatchError(ContainSubstring("fleet limits")))
+	})
+
+	It("Only dispatches from hubs that are open at a route's first stop", func() {
+		// Hub 1 closes at 08:15, so it can't dispatch to stop 12 at 08:20.
+		early := &network.HubNode{Val: 1, Hours: &network.OperatingHours{Open: 6 * time.Hour, Close: 8*time.Hour + 15*time.Minute}}
+		G := testNetwork([]*network.HubNode{early, {Val: 2}}, []int{0, 20}, nil)
+
+		routes, err := routing.MinPathCover(G)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(routes).To(HaveLen(2))
+		Expect(stopIDs(routes[1])).To(Equal([]int64{11}))
+		Expect(routes[1].Hub.ID()).To(BeEquivalentTo(2))
+	})
+
+	It("Moves routes between hubs to fit one that only a full hub can serve", func() {
+		// Both hubs can serve stop 10, but only hub 1 is open for stop 11. Stop 10 goes to hub 1 first and has to be moved.
+		early := &network.HubNode{Val: 2, Fleet: 1, Hours: &network.OperatingHours{Open: 6 * time.Hour, Close: 8*time.Hour + 15*time.Minute}}
+		G := testNetwork([]*network.HubNode{{Val: 1, Fleet: 1}, early}, []int{0, 20}, nil)
+
+		routes, err := routing.MinPathCover(G)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(routes).To(HaveLen(2))
+		Expect(routes[0].Hub.ID()).To(BeEquivalentTo(2))
+		Expect(routes[1].Hub.ID()).To(BeEquivalentTo(1))
+	})
+
+	It("Dispatches thousands of routes without a slot per route and hub", func() {
+		minutes := make([]int, 5000)
+		hubs := make([]*network.HubNode, 0, 5)
+		for h := 1; h <= 5; h++ {
+			hubs = append(hubs, &network.HubNode{Val: int64(h)})
+		}
+		G := testNetwork(hubs, minutes, nil)
+
+		start := time.Now()
+		routes, err := routing.MinPathCover(G)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(routes).To(HaveLen(5000))
+
+		// Giving every hub a slot per route made dispatch quadratic in the number of routes, in time and memory alike.
+		Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	})
+
+	It("Fails when no hub can serve a route", func() {
+		closed := &network.HubNode{Val: 1, Hours: &network.OperatingHours{Open: 20 * time.Hour, Close: 22 * time.Hour}}
+		G := testNetwork([]*network.HubNode{closed}, minutes, links)
+
+		_, err := routing.MinPathCover(G)
+		Expect(err).To(MatchError(ContainSubstring("No hub can serve")))
+	})
+})
+
+var _ = Describe("CheckFleet", func() {
+	It("Rejects hubs dispatching more routes than their limit", func() {
+		hub := &network.HubNode{Val: 1, Fleet: 3, Capacity: 1}
+		routes := []routing.Route{{Hub: hub}, {Hub: hub}}
+
+		Expect(routing.CheckFleet(routes)).To(MatchError(ContainSubstring("can only dispatch 1")))
+		Expect(routing.CheckFleet(routes[:1])).To(Succeed())
+	})
+})
@@ -0,0 +1,175 @@
+/*
+routing builds vehicle routes over delivery networks. A route leaves a hub, visits a chain of stops linked by stop-to-stop edges, and returns to the hub it started from.
+
+Hubs may limit the number of routes they dispatch through their fleet size and dispatch capacity (see network.HubNode.DispatchLimit). Every routing function in this package honours those limits except MinFleetExpanded, in which a vehicle may serve several routes; it limits the vehicles starting at each hub by fleet size alone and ignores dispatch capacity.
+
+Edges are told apart by their kind (see network.EdgeKind): routes leave hubs along dispatch edges, move between stops along stop-link edges and end along return edges. Hub-to-hub edges are ignored everywhere except MinFleetExpanded, which moves vehicles along the transfers and waiting arcs of a time-expanded network.
+*/
+package routing
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gonum.org/v1/gonum/graph"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+// Route is a single vehicle's tour: it leaves Hub, visits Stops in order and returns to Return. A nil Return means the vehicle goes back to Hub. Routes built by MinFleet and MinFleetExpanded may end at another hub; every other function in this package returns vehicles to the hub they left.
+type Route struct {
+	Hub    *network.HubNode
+	Stops  []*network.StopNode
+	Return *network.HubNode
+}
+
+// End returns the hub the vehicle finishes at.
+func (r Route) End() *network.HubNode {
+	if r.Return != nil {
+		return r.Return
+	}
+
+	return r.Hub
+}
+
+// Weight returns the total weight of the route's edges: out from the hub, between consecutive stops and back to the return hub. Returns an error if G lacks any of those edges.
+func (r Route) Weight(G graph.Weighted) (float64, error) {
+	if len(r.Stops) == 0 {
+		return 0.0, nil
+	}
+
+	nodes := make([]graph.Node, 0, len(r.Stops)+2)
+	nodes = append(nodes, r.Hub)
+	for _, stop := range r.Stops {
+		nodes = append(nodes, stop)
+	}
+	nodes = append(nodes, r.End())
+
+	total := 0.0
+	for i := 1; i < len(nodes); i++ {
+		u, v := nodes[i-1].ID(), nodes[i].ID()
+
+		w, ok := G.Weight(u, v)
+		if !ok || u == v {
+			return 0.0, fmt.Errorf("Route has no edge from %d to %d.", u, v)
+		}
+
+		total += w
+	}
+
+	return total, nil
+}
+
+// Idle returns the time the vehicle spends waiting at its stops, measured as RouteReport.Waiting is: the time between consecutive stops' timestamps, less the travel time of the edge joining them. Hops the vehicle can't make in time add nothing. Returns an error if G lacks any edge between consecutive stops.
+func (r Route) Idle(G graph.Weighted) (time.Duration, error) {
+	var idle time.Duration
+
+	for k := 1; k < len(r.Stops); k++ {
+		prev, next := r.Stops[k-1], r.Stops[k]
+
+		travel, ok := hopTravel(G, prev.ID(), next.ID())
+		if !ok {
+			return 0, fmt.Errorf("Route has no edge from %d to %d.", prev.ID(), next.ID())
+		}
+
+		if slack := next.Timestamp.Sub(prev.Timestamp.Add(travel)); slack > 0 {
+			idle += slack
+		}
+	}
+
+	return idle, nil
+}
+
+// hopTravel returns the travel time of the edge from u to v: the travel time its metadata gives, if any, or else its weight. Returns false if G has no such edge.
+func hopTravel(G graph.Weighted, u, v int64) (time.Duration, bool) {
+	w, ok := G.Weight(u, v)
+	if !ok || u == v {
+		return 0, false
+	}
+
+	if edge, ok := G.WeightedEdge(u, v).(*network.DeliveryEdge); ok && edge.Meta != nil && edge.Meta.Travel > 0 {
+		return edge.Meta.Travel, true
+	}
+
+	return time.Duration(w), true
+}
+
+// Load returns the most the vehicle carries at once along the route. Each stop adds its demand to the load, so a pickup and its dropoff only count while the parcel is on board.
+func (r Route) Load() float64 {
+	return chainLoad(r.Stops)
+}
+
+// chainLoad returns the peak running load over a chain of stops, starting from empty.
+func chainLoad(stops []*network.StopNode) float64 {
+	load, peak := 0.0, 0.0
+	for _, stop := range stops {
+		load += stop.Demand
+		if load > peak {
+			peak = load
+		}
+	}
+
+	return peak
+}
+
+// CheckFleet returns an error if any hub dispatches more routes than its dispatch limit allows.
+func CheckFleet(routes []Route) error {
+	dispatched := make(map[*network.HubNode]uint)
+	for _, route := range routes {
+		dispatched[route.Hub]++
+	}
+
+	hubs := make([]*network.HubNode, 0, len(dispatched))
+	for hub := range dispatched {
+		hubs = append(hubs, hub)
+	}
+	sort.Slice(hubs, func(i, j int) bool { return hubs[i].ID() < hubs[j].ID() })
+
+	for _, hub := range hubs {
+		if limit, ok := hub.DispatchLimit(); ok && dispatched[hub] > limit {
+			return fmt.Errorf("Hub %d dispatches %d routes but can only dispatch %d.", hub.ID(), dispatched[hub], limit)
+		}
+	}
+
+	return nil
+}
+
+// sortRoutes orders routes by the timestamp of their first stop, with ties broken by ID. Routes must not be empty.
+func sortRoutes(routes []Route) {
+	sort.SliceStable(routes, func(i, j int) bool {
+		a, b := routes[i].Stops[0], routes[j].Stops[0]
+		if !a.Timestamp.Equal(b.Timestamp) {
+			return a.Timestamp.Before(b.Timestamp)
+		}
+		return a.ID() < b.ID()
+	})
+}
+
+// sortedStops returns G's stops ordered by timestamp, with ties broken by ID.
+func sortedStops(G *network.DeliveryNetwork) []*network.StopNode {
+	stops := make([]*network.StopNode, 0, len(G.Stops))
+	for _, stop := range G.Stops {
+		stops = append(stops, stop)
+	}
+
+	sort.Slice(stops, func(i, j int) bool {
+		if !stops[i].Timestamp.Equal(stops[j].Timestamp) {
+			return stops[i].Timestamp.Before(stops[j].Timestamp)
+		}
+		return stops[i].ID() < stops[j].ID()
+	})
+
+	return stops
+}
+
+// sortedHubs returns G's hubs ordered by ID.
+func sortedHubs(G *network.DeliveryNetwork) []*network.HubNode {
+	hubs := make([]*network.HubNode, 0, len(G.Hubs))
+	for _, hub := range G.Hubs {
+		hubs = append(hubs, hub)
+	}
+
+	sort.Slice(hubs, func(i, j int) bool { return hubs[i].ID() < hubs[j].ID() })
+	return hubs
+}
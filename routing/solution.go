@@ -0,0 +1,50 @@
+package routing
+
+import (
+	"time"
+
+	"gonum.org/v1/gonum/graph"
+)
+
+// Solution is a set of routes serving a network, together with their combined weight and idle time.
+type Solution struct {
+	Routes []Route
+	Weight float64
+	Idle   time.Duration
+}
+
+// NewSolution totals the weight and idle time of routes over G. Returns an error if any route uses an edge G lacks.
+func NewSolution(G graph.Weighted, routes []Route) (*Solution, error) {
+	S := &Solution{Routes: routes}
+
+	for _, route := range routes {
+		w, err := route.Weight(G)
+		if err != nil {
+			return nil, err
+		}
+
+		idle, err := route.Idle(G)
+		if err != nil {
+			return nil, err
+		}
+
+		S.Weight += w
+		S.Idle += idle
+	}
+
+	return S, nil
+}
+
+// Vehicles returns the number of routes in the solution, one per vehicle.
+func (S *Solution) Vehicles() int {
+	return len(S.Routes)
+}
+
+// Gap returns the solution's optimality gap in vehicles against an exact cover such as the one from MinPathCover: the fraction by which it uses more vehicles. Returns 0 if exact is empty.
+func (S *Solution) Gap(exact []Route) float64 {
+	if len(exact) == 0 {
+		return 0.0
+	}
+
+	return float64(len(S.Routes)-len(exact)) / float64(len(exact))
+}
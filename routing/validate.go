@@ -0,0 +1,182 @@
+package routing
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/bdshroyer/burrow/network"
+)
+
+// RouteReport describes a route as driven over a network.
+type RouteReport struct {
+	// Weight is the total weight of the route's edges that exist in the network.
+	Weight float64
+
+	// Waiting is the time the vehicle spends at stops before it is due to leave them: the time between consecutive stops' timestamps, less the travel time of the edge joining them. Only edges whose metadata gives a travel time contribute, since an edge without one is read as all travel; generated networks give edges travel times only when their nodes have locations, and on networks without any, Waiting is always zero.
+	Waiting time.Duration
+
+	// Violations lists every constraint the route breaks, in the order they occur along the route.
+	Violations []error
+}
+
+// Valid returns true if the route breaks no constraints.
+func (rr RouteReport) Valid() bool {
+	return len(rr.Violations) == 0
+}
+
+// Validate drives the route over G and reports its weight, waiting time and any constraints it breaks. A route is valid if:
+//   - its hubs and stops are all in G, and no stop is visited twice;
+//   - every hop, from the hub through the stops and back to the return hub, is an edge in G;
+//   - the hub is open when the vehicle leaves for the first stop;
+//   - the vehicle can reach each stop by its timestamp, travelling each stop-to-stop edge in its travel time;
+//   - every pickup-and-delivery pair it touches is served whole, pickup first.
+//
+// Stop-to-stop edge weights are read as travel times, unless an edge's metadata gives its travel time separately, as it does in generated networks with locations.
+func (r Route) Validate(G *network.DeliveryNetwork) RouteReport {
+	report := RouteReport{Violations: make([]error, 0)}
+	violate := func(format string, args ...any) {
+		report.Violations = append(report.Violations, fmt.Errorf(format, args...))
+	}
+
+	if r.Hub == nil {
+		violate("Route has no hub.")
+		return report
+	}
+
+	for _, hub := range []*network.HubNode{r.Hub, r.Return} {
+		if hub == nil {
+			continue
+		}
+
+		if _, ok := G.Hubs[hub.ID()]; !ok {
+			violate("Hub %d is not in the network.", hub.ID())
+		}
+	}
+
+	seen := make(map[int64]bool, len(r.Stops))
+	for _, stop := range r.Stops {
+		if _, ok := G.Stops[stop.ID()]; !ok {
+			violate("Stop %d is not in the network.", stop.ID())
+		}
+
+		if seen[stop.ID()] {
+			violate("Stop %d is visited more than once.", stop.ID())
+		}
+		seen[stop.ID()] = true
+	}
+
+	if len(r.Stops) == 0 {
+		return report
+	}
+
+	hop := func(u, v int64) (float64, bool) {
+		w, ok := G.Weight(u, v)
+		if !ok || u == v {
+			violate("Route has no edge from %d to %d.", u, v)
+			return 0.0, false
+		}
+
+		report.Weight += w
+		return w, true
+	}
+
+	first := r.Stops[0]
+	hop(r.Hub.ID(), first.ID())
+	if !r.Hub.IsOpen(first.Timestamp) {
+		violate("Hub %d is closed when dispatching to stop %d.", r.Hub.ID(), first.ID())
+	}
+
+	for k := 1; k < len(r.Stops); k++ {
+		prev, next := r.Stops[k-1], r.Stops[k]
+
+		if _, ok := hop(prev.ID(), next.ID()); !ok {
+			continue
+		}
+
+		travel, _ := hopTravel(G, prev.ID(), next.ID())
+
+		arrival := prev.Timestamp.Add(travel)
+		if slack := next.Timestamp.Sub(arrival); slack >= 0 {
+			report.Waiting += slack
+		} else {
+			violate("Stop %d is reached %s after its timestamp.", next.ID(), -slack)
+		}
+	}
+
+	hop(r.Stops[len(r.Stops)-1].ID(), r.End().ID())
+
+	if err := CheckPairs(G, []Route{r}); err != nil {
+		report.Violations = append(report.Violations, err)
+	}
+
+	return report
+}
+
+// RouteSet is a full schedule: one route per vehicle.
+type RouteSet []Route
+
+// Validate validates every route in the set, returning their reports in order. The error reports the first violation in any route, or failing that whether the set covers G's stops, keeps pairs on one vehicle and stays within the hubs' dispatch limits.
+func (rs RouteSet) Validate(G *network.DeliveryNetwork) ([]RouteReport, error) {
+	reports := make([]RouteReport, 0, len(rs))
+	var err error
+
+	for i, route := range rs {
+		report := route.Validate(G)
+		reports = append(reports, report)
+
+		if err == nil && !report.Valid() {
+			err = fmt.Errorf("Route %d: %w", i, report.Violations[0])
+		}
+	}
+
+	if err != nil {
+		return reports, err
+	}
+
+	if err := rs.CheckCoverage(G); err != nil {
+		return reports, err
+	}
+
+	if err := CheckPairs(G, rs); err != nil {
+		return reports, err
+	}
+
+	return reports, CheckFleet(rs)
+}
+
+// CheckCoverage returns an error unless every stop in G is served by exactly one visit in the set. Stops are checked in ID order, so the error names the lowest offending stop.
+func (rs RouteSet) CheckCoverage(G *network.DeliveryNetwork) error {
+	visits := make(map[int64]int, len(G.Stops))
+	for _, route := range rs {
+		for _, stop := range route.Stops {
+			visits[stop.ID()]++
+		}
+	}
+
+	ids := make([]int64, 0, len(G.Stops))
+	for id := range G.Stops {
+		ids = append(ids, id)
+	}
+	for id := range visits {
+		if _, ok := G.Stops[id]; !ok {
+			ids = append(ids, id)
+		}
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	for _, id := range ids {
+		_, inNetwork := G.Stops[id]
+
+		switch n := visits[id]; {
+		case !inNetwork:
+			return fmt.Errorf("Stop %d is not in the network.", id)
+		case n == 0:
+			return fmt.Errorf("Stop %d is not served.", id)
+		case n > 1:
+			return fmt.Errorf("Stop %d is served %d times.", id, n)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,175 @@
+package routing_test
+
+import (
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+
+	"github.com/bdshroyer/burrow/network"
+	"github.com/bdshroyer/burrow/routing"
+)
+
+var _ = Describe("Validate", func() {
+	var (
+		G   *network.DeliveryNetwork
+		hub *network.HubNode
+	)
+
+	BeforeEach(func() {
+		hub = &network.HubNode{Val: 1}
+		G = testNetwork([]*network.HubNode{hub}, []int{0, 10, 30}, [][2]int64{{10, 11}, {11, 12}})
+	})
+
+	Describe("Route", func() {
+		It("Reports the weight and waiting time of a valid route", func() {
+			// Shorten the last hop so the vehicle waits at stop 11.
+			G.DEdges[11][len(G.DEdges[11])-1].Wgt = float64(15 * time.Minute)
+
+			report := routing.Route{Hub: hub, Stops: []*network.StopNode{G.Stops[10], G.Stops[11], G.Stops[12]}}.Validate(G)
+			Expect(report.Valid()).To(BeTrue())
+			Expect(report.Weight).To(Equal(float64(2*time.Hour + 25*time.Minute)))
+			Expect(report.Waiting).To(Equal(5 * time.Minute))
+		})
+
+		It("Takes travel times from edge metadata when it has them", func() {
+			link := G.DEdges[11][len(G.DEdges[11])-1]
+			link.Meta = &network.EdgeMeta{Travel: 5 * time.Minute, Cost: 3}
+
+			route := routing.Route{Hub: hub, Stops: []*network.StopNode{G.Stops[11], G.Stops[12]}}
+			report := route.Validate(G)
+			Expect(report.Valid()).To(BeTrue())
+			Expect(report.Weight).To(Equal(float64(2*time.Hour + 20*time.Minute)))
+			Expect(report.Waiting).To(Equal(15 * time.Minute))
+
+			link.Meta.Travel = 25 * time.Minute
+			Expect(route.Validate(G).Violations).To(ConsistOf(MatchError("Stop 12 is reached 5m0s after its timestamp.")))
+		})
+
+		It("Reports as idle time the same waiting Validate finds", func() {
+			link := G.DEdges[11][len(G.DEdges[11])-1]
+			link.Meta = &network.EdgeMeta{Travel: 5 * time.Minute}
+
+			route := routing.Route{Hub: hub, Stops: []*network.StopNode{G.Stops[10], G.Stops[11], G.Stops[12]}}
+			idle, err := route.Idle(G)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(idle).To(Equal(15 * time.Minute))
+			Expect(idle).To(Equal(route.Validate(G).Waiting))
+
+			S, err := routing.NewSolution(G, []routing.Route{route})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(S.Idle).To(Equal(15 * time.Minute))
+
+			_, err = routing.Route{Hub: hub, Stops: []*network.StopNode{G.Stops[12], G.Stops[10]}}.Idle(G)
+			Expect(err).To(MatchError("Route has no edge from 12 to 10."))
+		})
+
+		It("Returns to a different hub when one is given", func() {
+			other := &network.HubNode{Val: 2}
+			G.Hubs[other.ID()] = other
+			G.DEdges[12] = append(G.DEdges[12], &network.DeliveryEdge{Src: G.Stops[12], Dst: other, Wgt: float64(time.Hour)})
+
+			route := routing.Route{Hub: hub, Stops: []*network.StopNode{G.Stops[12]}, Return: other}
+			Expect(route.End()).To(Equal(other))
+			Expect(route.Validate(G).Valid()).To(BeTrue())
+
+			G.DEdges[12] = G.DEdges[12][:len(G.DEdges[12])-1]
+			Expect(route.Validate(G).Violations).To(ConsistOf(MatchError("Route has no edge from 12 to 2.")))
+		})
+
+		It("Reports every violation along the route", func() {
+			G.DEdges[10][len(G.DEdges[10])-1].Wgt = float64(20 * time.Minute)
+
+			report := routing.Route{Hub: hub, Stops: []*network.StopNode{G.Stops[10], G.Stops[11], G.Stops[10], G.Stops[12]}}.Validate(G)
+			Expect(report.Valid()).To(BeFalse())
+			Expect(report.Violations).To(HaveLen(4))
+			Expect(report.Violations[0]).To(MatchError("Stop 10 is visited more than once."))
+			Expect(report.Violations[1]).To(MatchError("Stop 11 is reached 10m0s after its timestamp."))
+			Expect(report.Violations[2]).To(MatchError("Route has no edge from 11 to 10."))
+			Expect(report.Violations[3]).To(MatchError("Route has no edge from 10 to 12."))
+		})
+
+		It("Reports hubs and stops missing from the network", func() {
+			stranger := &network.StopNode{Val: 99, Timestamp: t0}
+			report := routing.Route{Hub: &network.HubNode{Val: 7}, Stops: []*network.StopNode{stranger}}.Validate(G)
+
+			Expect(report.Violations).To(ContainElements(
+				MatchError("Hub 7 is not in the network."),
+				MatchError("Stop 99 is not in the network."),
+			))
+
+			Expect(routing.Route{}.Validate(G).Violations).To(ConsistOf(MatchError("Route has no hub.")))
+		})
+
+		It("Reports a hub that is closed at dispatch", func() {
+			hub.Hours = &network.OperatingHours{Open: 9 * time.Hour, Close: 17 * time.Hour}
+
+			report := routing.Route{Hub: hub, Stops: []*network.StopNode{G.Stops[10]}}.Validate(G)
+			Expect(report.Violations).To(ContainElement(MatchError("Hub 1 is closed when dispatching to stop 10.")))
+		})
+
+		It("Reports pairs the route breaks", func() {
+			pickup, _ := network.NewPair(
+				network.StopNode{Val: 20, Timestamp: t0},
+				network.StopNode{Val: 21, Timestamp: t0.Add(time.Hour)},
+			)
+			G.AddPair(pickup)
+			G.DEdges[1] = append(G.DEdges[1], &network.DeliveryEdge{Src: hub, Dst: G.Stops[20], Wgt: float64(time.Hour)})
+			G.DEdges[20] = append(G.DEdges[20], &network.DeliveryEdge{Src: G.Stops[20], Dst: hub, Wgt: float64(time.Hour)})
+
+			report := routing.Route{Hub: hub, Stops: []*network.StopNode{G.Stops[20]}}.Validate(G)
+			Expect(report.Violations).To(ConsistOf(MatchError("Pickup 20 is served, but its dropoff 21 is not.")))
+		})
+	})
+
+	Describe("RouteSet", func() {
+		It("Accepts a set that serves every stop once", func() {
+			routes := routing.RouteSet{
+				{Hub: hub, Stops: []*network.StopNode{G.Stops[10], G.Stops[11]}},
+				{Hub: hub, Stops: []*network.StopNode{G.Stops[12]}},
+			}
+
+			reports, err := routes.Validate(G)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(reports).To(HaveLen(2))
+			Expect(routes.CheckCoverage(G)).To(Succeed())
+		})
+
+		It("Reports stops that are missed or served twice", func() {
+			missed := routing.RouteSet{{Hub: hub, Stops: []*network.StopNode{G.Stops[10], G.Stops[11]}}}
+			Expect(missed.CheckCoverage(G)).To(MatchError("Stop 12 is not served."))
+
+			twice := routing.RouteSet{
+				{Hub: hub, Stops: []*network.StopNode{G.Stops[10], G.Stops[11]}},
+				{Hub: hub, Stops: []*network.StopNode{G.Stops[11], G.Stops[12]}},
+			}
+			Expect(twice.CheckCoverage(G)).To(MatchError("Stop 11 is served 2 times."))
+
+			_, err := twice.Validate(G)
+			Expect(err).To(MatchError("Stop 11 is served 2 times."))
+		})
+
+		It("Reports the first invalid route", func() {
+			routes := routing.RouteSet{
+				{Hub: hub, Stops: []*network.StopNode{G.Stops[10], G.Stops[11]}},
+				{Hub: hub, Stops: []*network.StopNode{G.Stops[12], G.Stops[10]}},
+			}
+
+			reports, err := routes.Validate(G)
+			Expect(err).To(MatchError("Route 1: Route has no edge from 12 to 10."))
+			Expect(reports[0].Valid()).To(BeTrue())
+			Expect(reports[1].Valid()).To(BeFalse())
+		})
+
+		It("Enforces fleet limits", func() {
+			hub.Fleet = 1
+			routes := routing.RouteSet{
+				{Hub: hub, Stops: []*network.StopNode{G.Stops[10], G.Stops[11]}},
+				{Hub: hub, Stops: []*network.StopNode{G.Stops[12]}},
+			}
+
+			_, err := routes.Validate(G)
+			Expect(err).To(MatchError("Hub 1 dispatches 2 routes but can only dispatch 1."))
+		})
+	})
+})
@@ -0,0 +1,320 @@
+package burrow
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"gopkg.in/yaml.v3"
+)
+
+// SpecFormat is an encoding of a NetworkSpec that LoadSpec can read.
+type SpecFormat int
+
+const (
+	// SpecBinary is the protobuf wire format.
+	SpecBinary SpecFormat = iota
+
+	// SpecJSON is protobuf's JSON mapping, as written by protojson.
+	SpecJSON
+
+	// SpecText is protobuf's text format, as written by prototext.
+	SpecText
+
+	// SpecYAML is YAML with the same field names and values as SpecJSON.
+	SpecYAML
+)
+
+// specExtensions maps file extensions to the formats they are read as.
+var specExtensions = map[string]SpecFormat{
+	".pb":        SpecBinary,
+	".binpb":     SpecBinary,
+	".json":      SpecJSON,
+	".txtpb":     SpecText,
+	".textproto": SpecText,
+	".pbtxt":     SpecText,
+	".yaml":      SpecYAML,
+	".yml":       SpecYAML,
+}
+
+// LoadSpec reads a NetworkSpec from path, choosing the format from the file's extension (.pb or .binpb for binary, .json, .txtpb, .textproto or .pbtxt for text format, and .yaml or .yml). Files with any other extension are read as binary unless they hold valid UTF-8, in which case they're read as JSON if they start with '{', and otherwise as text format or, failing that, YAML.
+//
+// Errors are prefixed with path. See ParseSpec for what each format accepts.
+func LoadSpec(path string) (*NetworkSpec, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	format, ok := specExtensions[strings.ToLower(filepath.Ext(path))]
+	if !ok {
+		format = sniffSpecFormat(raw)
+	}
+
+	spec, err := ParseSpec(raw, format)
+	if err != nil && !ok && format == SpecText {
+		// Text format and YAML look alike, so a file that is neither is reported as YAML, the more common of the two.
+		spec, err = ParseSpec(raw, SpecYAML)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	return spec, nil
+}
+
+// LoadNetworkConfig reads a spec with LoadSpec and converts it with NewNetworkConfigFrom.
+func LoadNetworkConfig(path string) (*DeliveryNetworkConfig, error) {
+	spec, err := LoadSpec(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewNetworkConfigFrom(spec)
+}
+
+// ParseSpec decodes a NetworkSpec in the given format.
+//
+// JSON and YAML accept field names as they appear in the proto file or in their JSON form, and report problems with the line they occur on. Durations may be written as Go durations such as "6h" or "1h30m" (which includes protojson's "21600s"), and timestamps in RFC 3339 form such as "2022-03-28T08:00:00Z". Text format follows protobuf's own rules, in which durations and timestamps are messages with seconds and nanos fields.
+func ParseSpec(raw []byte, format SpecFormat) (*NetworkSpec, error) {
+	spec := &NetworkSpec{}
+
+	switch format {
+	case SpecBinary:
+		if err := proto.Unmarshal(raw, spec); err != nil {
+			return nil, err
+		}
+
+	case SpecText:
+		if err := prototext.Unmarshal(raw, spec); err != nil {
+			return nil, err
+		}
+
+	case SpecJSON, SpecYAML:
+		// YAML is a superset of JSON, so both are decoded the same way.
+		var doc yaml.Node
+		if err := yaml.Unmarshal(raw, &doc); err != nil {
+			return nil, yamlError(err)
+		}
+
+		if len(doc.Content) > 0 {
+			if err := decodeSpecMessage(doc.Content[0], spec.ProtoReflect()); err != nil {
+				return nil, err
+			}
+		}
+
+	default:
+		return nil, fmt.Errorf("Unknown spec format %d.", format)
+	}
+
+	return spec, nil
+}
+
+// sniffSpecFormat guesses the format of a spec file whose extension doesn't give it away.
+func sniffSpecFormat(raw []byte) SpecFormat {
+	switch {
+	case !utf8.Valid(raw):
+		return SpecBinary
+	case bytes.HasPrefix(bytes.TrimSpace(raw), []byte("{")):
+		return SpecJSON
+	default:
+		return SpecText
+	}
+}
+
+// yamlError rewords the YAML parser's errors, which name the parser and a lower-case line, to match the rest of the package.
+func yamlError(err error) error {
+	msg := strings.TrimPrefix(err.Error(), "yaml: ")
+	if strings.HasPrefix(msg, "line ") {
+		msg = "Line " + strings.TrimPrefix(msg, "line ")
+	}
+
+	return fmt.Errorf("%s.", strings.TrimSuffix(msg, "."))
+}
+
+// specError reports a problem with a node of a JSON or YAML spec on the node's line.
+func specError(node *yaml.Node, format string, args ...any) error {
+	return fmt.Errorf("Line %d: %s", node.Line, fmt.Sprintf(format, args...))
+}
+
+// decodeSpecMessage sets the fields of m from a YAML mapping, using m's descriptor to tell what each value should be.
+func decodeSpecMessage(node *yaml.Node, m protoreflect.Message) error {
+	desc := m.Descriptor()
+	if node.Kind != yaml.MappingNode {
+		return specError(node, "%s must be a mapping of field names to values.", desc.Name())
+	}
+
+	fields := desc.Fields()
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		key, val := node.Content[i], node.Content[i+1]
+
+		fd := fields.ByName(protoreflect.Name(key.Value))
+		if fd == nil {
+			fd = fields.ByJSONName(key.Value)
+		}
+		if fd == nil {
+			return specError(key, "%s has no field %q.", desc.Name(), key.Value)
+		}
+
+		if val.Tag == "!!null" {
+			continue
+		}
+
+		if m.Has(fd) {
+			return specError(key, "%s is set twice.", fd.Name())
+		}
+
+		if oneof := fd.ContainingOneof(); oneof != nil {
+			if set := m.WhichOneof(oneof); set != nil {
+				return specError(key, "%s and %s cannot both be set.", set.Name(), fd.Name())
+			}
+		}
+
+		if err := decodeSpecField(val, m, fd); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// decodeSpecField sets a single field of m from its YAML value.
+func decodeSpecField(val *yaml.Node, m protoreflect.Message, fd protoreflect.FieldDescriptor) error {
+	if fd.IsMap() {
+		return specError(val, "Map fields such as %s are not supported.", fd.Name())
+	}
+
+	if !fd.IsList() {
+		v, err := decodeSpecValue(val, fd, m.NewField(fd))
+		if err != nil {
+			return err
+		}
+
+		m.Set(fd, v)
+		return nil
+	}
+
+	if val.Kind != yaml.SequenceNode {
+		return specError(val, "%s must be a list.", fd.Name())
+	}
+
+	list := m.Mutable(fd).List()
+	for _, item := range val.Content {
+		v, err := decodeSpecValue(item, fd, list.NewElement())
+		if err != nil {
+			return err
+		}
+
+		list.Append(v)
+	}
+
+	return nil
+}
+
+// decodeSpecValue decodes a single value of field fd. For message fields, empty is a new message to decode into.
+func decodeSpecValue(val *yaml.Node, fd protoreflect.FieldDescriptor, empty protoreflect.Value) (protoreflect.Value, error) {
+	if fd.Kind() == protoreflect.MessageKind {
+		msg := empty.Message()
+
+		if val.Kind == yaml.ScalarNode {
+			return decodeSpecWellKnown(val, fd, msg)
+		}
+
+		return empty, decodeSpecMessage(val, msg)
+	}
+
+	if val.Kind != yaml.ScalarNode {
+		return empty, specError(val, "%s must be a single value.", fd.Name())
+	}
+
+	s := val.Value
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if b, err := strconv.ParseBool(s); err == nil {
+			return protoreflect.ValueOfBool(b), nil
+		}
+		return empty, specError(val, "%s must be true or false, got %q.", fd.Name(), s)
+
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		if n, err := strconv.ParseInt(s, 10, 32); err == nil {
+			return protoreflect.ValueOfInt32(int32(n)), nil
+		}
+
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return protoreflect.ValueOfInt64(n), nil
+		}
+
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		if n, err := strconv.ParseUint(s, 10, 32); err == nil {
+			return protoreflect.ValueOfUint32(uint32(n)), nil
+		}
+		return empty, specError(val, "%s must be a non-negative integer, got %q.", fd.Name(), s)
+
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		if n, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return protoreflect.ValueOfUint64(n), nil
+		}
+		return empty, specError(val, "%s must be a non-negative integer, got %q.", fd.Name(), s)
+
+	case protoreflect.FloatKind:
+		if x, err := strconv.ParseFloat(s, 32); err == nil {
+			return protoreflect.ValueOfFloat32(float32(x)), nil
+		}
+		return empty, specError(val, "%s must be a number, got %q.", fd.Name(), s)
+
+	case protoreflect.DoubleKind:
+		if x, err := strconv.ParseFloat(s, 64); err == nil {
+			return protoreflect.ValueOfFloat64(x), nil
+		}
+		return empty, specError(val, "%s must be a number, got %q.", fd.Name(), s)
+
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s), nil
+
+	case protoreflect.EnumKind:
+		if ev := fd.Enum().Values().ByName(protoreflect.Name(s)); ev != nil {
+			return protoreflect.ValueOfEnum(ev.Number()), nil
+		}
+		return empty, specError(val, "%s has no value %q.", fd.Enum().Name(), s)
+
+	default:
+		return empty, specError(val, "Fields of type %s, such as %s, are not supported.", fd.Kind(), fd.Name())
+	}
+
+	return empty, specError(val, "%s must be an integer, got %q.", fd.Name(), s)
+}
+
+// decodeSpecWellKnown reads a duration or timestamp written as a single string.
+func decodeSpecWellKnown(val *yaml.Node, fd protoreflect.FieldDescriptor, msg protoreflect.Message) (protoreflect.Value, error) {
+	switch msg.Descriptor().FullName() {
+	case "google.protobuf.Duration":
+		d, err := time.ParseDuration(val.Value)
+		if err != nil {
+			return protoreflect.Value{}, specError(val, "%s must be a duration such as \"6h\" or \"90s\", got %q.", fd.Name(), val.Value)
+		}
+		return protoreflect.ValueOfMessage(durationpb.New(d).ProtoReflect()), nil
+
+	case "google.protobuf.Timestamp":
+		t, err := time.Parse(time.RFC3339Nano, val.Value)
+		if err != nil {
+			return protoreflect.Value{}, specError(val, "%s must be an RFC 3339 timestamp such as \"2022-03-28T08:00:00Z\", got %q.", fd.Name(), val.Value)
+		}
+		return protoreflect.ValueOfMessage(timestamppb.New(t).ProtoReflect()), nil
+
+	default:
+		return protoreflect.Value{}, specError(val, "%s must be a mapping of field names to values.", fd.Message().Name())
+	}
+}
@@ -0,0 +1,136 @@
+package burrow_test
+
+import (
+	"os"
+	"path/filepath"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/encoding/prototext"
+	"google.golang.org/protobuf/proto"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/bdshroyer/burrow"
+)
+
+var _ = Describe("Spec loading", func() {
+	var (
+		dir      string
+		expected *burrow.NetworkSpec
+	)
+
+	BeforeEach(func() {
+		dir = GinkgoT().TempDir()
+
+		t0 := time.Date(2022, 3, 28, 8, 0, 0, 0, time.UTC)
+		expected = &burrow.NetworkSpec{
+			Hubs:         2,
+			Stops:        50,
+			Distribution: &burrow.NetworkSpec_Uniform{Uniform: &burrow.NetworkSpec_UniformDistro{}},
+			Start:        timestamppb.New(t0),
+			End:          timestamppb.New(t0.Add(12 * time.Hour)),
+			ShortEdge:    durationpb.New(10 * time.Minute),
+			LongEdge:     durationpb.New(6 * time.Hour),
+			HubAttributes: []*burrow.NetworkSpec_HubSpec{
+				{Open: durationpb.New(6 * time.Hour), Close: durationpb.New(22 * time.Hour), Fleet: 4},
+			},
+		}
+	})
+
+	writeSpec := func(name, contents string) string {
+		path := filepath.Join(dir, name)
+		Expect(os.WriteFile(path, []byte(contents), 0o644)).To(Succeed())
+		return path
+	}
+
+	const yamlSpec = `# Two hubs, one of them open from six to ten.
+Hubs: 2
+Stops: 50
+Uniform: {}
+start: 2022-03-28T08:00:00Z
+end: "2022-03-28T20:00:00Z"
+ShortEdge: 10m
+LongEdge: 6h
+HubAttributes:
+  - Open: 6h
+    Close: 22h
+    Fleet: 4
+`
+
+	It("Reads YAML with human-readable durations and timestamps", func() {
+		spec, err := burrow.LoadSpec(writeSpec("spec.yaml", yamlSpec))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(proto.Equal(spec, expected)).To(BeTrue(), prototext.Format(spec))
+	})
+
+	It("Reads protojson, whether written by protojson or by hand", func() {
+		raw, err := protojson.Marshal(expected)
+		Expect(err).NotTo(HaveOccurred())
+
+		spec, err := burrow.LoadSpec(writeSpec("spec.json", string(raw)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(proto.Equal(spec, expected)).To(BeTrue())
+
+		spec, err = burrow.LoadSpec(writeSpec("hand.json", `{"Hubs": 2, "Stops": "50", "Gaussian": {"Mean": 3600000, "StdDev": 60000000000}, "LongEdge": "90m"}`))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(spec.Stops).To(BeEquivalentTo(50))
+		Expect(spec.GetGaussian().GetMean()).To(BeEquivalentTo(3600000))
+		Expect(spec.LongEdge.AsDuration()).To(Equal(90 * time.Minute))
+	})
+
+	It("Reads text format and binary protobuf", func() {
+		text, err := prototext.Marshal(expected)
+		Expect(err).NotTo(HaveOccurred())
+
+		spec, err := burrow.LoadSpec(writeSpec("spec.txtpb", string(text)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(proto.Equal(spec, expected)).To(BeTrue())
+
+		raw, err := proto.Marshal(expected)
+		Expect(err).NotTo(HaveOccurred())
+
+		spec, err = burrow.LoadSpec(writeSpec("spec.pb", string(raw)))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(proto.Equal(spec, expected)).To(BeTrue())
+	})
+
+	It("Detects the format of files without a known extension", func() {
+		text, err := prototext.Marshal(expected)
+		Expect(err).NotTo(HaveOccurred())
+
+		for _, contents := range []string{yamlSpec, string(text)} {
+			spec, err := burrow.LoadSpec(writeSpec("spec", contents))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(proto.Equal(spec, expected)).To(BeTrue())
+		}
+	})
+
+	DescribeTable("Reports mistakes with their line numbers",
+		func(contents, message string) {
+			path := writeSpec("spec.yaml", contents)
+			_, err := burrow.LoadSpec(path)
+			Expect(err).To(MatchError(path + ": " + message))
+		},
+		Entry("unknown field", "Hubs: 2\nHub: 3\n", `Line 2: NetworkSpec has no field "Hub".`),
+		Entry("bad integer", "Hubs: 2\nStops: many\n", `Line 2: Stops must be a non-negative integer, got "many".`),
+		Entry("bad duration", "ShortEdge: 10 minutes\n", `Line 1: ShortEdge must be a duration such as "6h" or "90s", got "10 minutes".`),
+		Entry("bad timestamp", "start: tomorrow\n", `Line 1: start must be an RFC 3339 timestamp such as "2022-03-28T08:00:00Z", got "tomorrow".`),
+		Entry("two distributions", "Uniform: {}\nGaussian: {Mean: 1}\n", "Line 2: Uniform and Gaussian cannot both be set."),
+		Entry("nested mistake", "HubAttributes:\n  - Fleet: 2\n  - Fleet: -1\n", `Line 3: Fleet must be a non-negative integer, got "-1".`),
+		Entry("malformed YAML", "Hubs: [2\n", "Line 1: did not find expected ',' or ']'."),
+	)
+
+	It("Passes loaded specs to NewNetworkConfigFrom", func() {
+		cfg, err := burrow.LoadNetworkConfig(writeSpec("spec.yaml", yamlSpec))
+		Expect(err).NotTo(HaveOccurred())
+		Expect(cfg.HubNodes).To(BeEquivalentTo(2))
+		Expect(*cfg.EdgeBounds).To(Equal(burrow.TimeBox{10 * time.Minute, 6 * time.Hour}))
+		Expect(cfg.HubConfigs[0].Fleet).To(BeEquivalentTo(4))
+
+		_, err = burrow.LoadNetworkConfig(filepath.Join(dir, "missing.yaml"))
+		Expect(err).To(HaveOccurred())
+	})
+})
@@ -0,0 +1,101 @@
+package testutils
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// KSStatistic computes the one-sample Kolmogorov-Smirnov statistic D, the
+// largest distance between the empirical CDF of the sample and the given
+// continuous reference CDF. Returns an error on an empty sample.
+func KSStatistic(rawSamples []float64, cdf func(float64) float64) (float64, error) {
+	return ksStatistic(rawSamples, cdf, cdf)
+}
+
+// DiscreteKSStatistic computes the Kolmogorov-Smirnov statistic for a sample
+// drawn from an integer-valued distribution with the given CDF. Unlike
+// KSStatistic, it accounts for the jumps in the reference CDF by comparing the
+// left limits of both CDFs at every distinct sample value. Returns an error on
+// an empty sample.
+func DiscreteKSStatistic(rawSamples []float64, cdf func(float64) float64) (float64, error) {
+	leftLimit := func(x float64) float64 {
+		return cdf(math.Ceil(x) - 1)
+	}
+
+	return ksStatistic(rawSamples, cdf, leftLimit)
+}
+
+// ksStatistic compares the empirical CDF with the reference CDF at each
+// distinct sample value, using leftLimit(x) as the reference CDF just below x.
+func ksStatistic(rawSamples []float64, cdf, leftLimit func(float64) float64) (float64, error) {
+	if len(rawSamples) == 0 {
+		return -1, fmt.Errorf("Cannot compute KS statistic on an empty sample")
+	}
+
+	samples := make([]float64, len(rawSamples))
+	copy(samples, rawSamples)
+	sort.Float64s(samples)
+
+	N := float64(len(samples))
+	D := 0.0
+
+	for i := 0; i < len(samples); {
+		j := i
+		for j < len(samples) && samples[j] == samples[i] {
+			j++
+		}
+
+		below := float64(i) / N
+		through := float64(j) / N
+
+		D = math.Max(D, math.Abs(through-cdf(samples[i])))
+		D = math.Max(D, math.Abs(below-leftLimit(samples[i])))
+
+		i = j
+	}
+
+	return D, nil
+}
+
+// KSPValue returns the probability of observing a Kolmogorov-Smirnov
+// statistic of at least D on a sample of size N if the sample really was drawn
+// from the reference distribution. Small values indicate a poor fit. Note that
+// this is the opposite convention to AndersonDarlingTest.
+//
+// This uses the asymptotic Kolmogorov distribution with the small-sample
+// correction from Stephens (1970). For discrete reference distributions the
+// result is conservative.
+//
+// Returns -1 and an error if N <= 0 or D is outside [0, 1].
+func KSPValue(D float64, N int) (float64, error) {
+	if N <= 0 {
+		return -1.0, fmt.Errorf("N must be greater than 0.")
+	}
+
+	if D < 0.0 || D > 1.0 {
+		return -1.0, fmt.Errorf("KS statistic must be between 0 and 1.")
+	}
+
+	sqrtN := math.Sqrt(float64(N))
+	lambda := (sqrtN + 0.12 + 0.11/sqrtN) * D
+
+	if lambda < 1e-3 {
+		return 1.0, nil
+	}
+
+	sum := 0.0
+	sign := 1.0
+	for k := 1; k <= 100; k++ {
+		term := sign * math.Exp(-2.0*float64(k*k)*lambda*lambda)
+		sum += term
+
+		if math.Abs(term) < 1e-12 {
+			break
+		}
+
+		sign = -sign
+	}
+
+	return math.Min(math.Max(2.0*sum, 0.0), 1.0), nil
+}
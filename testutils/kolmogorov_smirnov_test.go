@@ -0,0 +1,113 @@
+package testutils_test
+
+import (
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"golang.org/x/exp/rand"
+	"gonum.org/v1/gonum/stat/distuv"
+
+	"github.com/bdshroyer/burrow/testutils"
+)
+
+var _ = Describe("KolmogorovSmirnov", func() {
+	src := rand.NewSource(7)
+
+	Describe("KSStatistic", func() {
+		It("Returns a small distance for a sample drawn from the reference distribution", func() {
+			dist := distuv.Normal{Mu: 0.0, Sigma: 1.0, Src: src}
+
+			samples := make([]float64, 1000)
+			for i := range samples {
+				samples[i] = dist.Rand()
+			}
+
+			D, err := testutils.KSStatistic(samples, dist.CDF)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(D).To(BeNumerically("<", 0.05))
+
+			pValue, err := testutils.KSPValue(D, len(samples))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pValue).To(BeNumerically(">", 0.05))
+		})
+
+		It("Returns a large distance for a sample drawn from a different distribution", func() {
+			dist := distuv.Exponential{Rate: 1.0, Src: src}
+			reference := distuv.Normal{Mu: 1.0, Sigma: 1.0}
+
+			samples := make([]float64, 1000)
+			for i := range samples {
+				samples[i] = dist.Rand()
+			}
+
+			D, err := testutils.KSStatistic(samples, reference.CDF)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(D).To(BeNumerically(">", 0.1))
+
+			pValue, err := testutils.KSPValue(D, len(samples))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(pValue).To(BeNumerically("<", 0.01))
+		})
+
+		It("Computes the exact distance on a known sample", func() {
+			uniform := func(x float64) float64 { return x }
+
+			D, err := testutils.KSStatistic([]float64{0.1, 0.2, 0.9}, uniform)
+			Expect(err).NotTo(HaveOccurred())
+			// The ECDF reaches 2/3 at 0.2, where the uniform CDF is only 0.2.
+			Expect(D).To(BeNumerically("~", 2.0/3.0-0.2, 1e-12))
+		})
+
+		It("Returns an error on an empty sample", func() {
+			D, err := testutils.KSStatistic([]float64{}, func(x float64) float64 { return x })
+			Expect(D).To(BeEquivalentTo(-1))
+			Expect(err).To(MatchError("Cannot compute KS statistic on an empty sample"))
+		})
+	})
+
+	Describe("DiscreteKSStatistic", func() {
+		It("Accounts for jumps in a discrete reference CDF", func() {
+			dist := distuv.Poisson{Lambda: 3.0, Src: src}
+
+			samples := make([]float64, 2000)
+			for i := range samples {
+				samples[i] = dist.Rand()
+			}
+
+			D, err := testutils.DiscreteKSStatistic(samples, dist.CDF)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(D).To(BeNumerically("<", 0.05))
+
+			// The continuous statistic mistakes the jumps for misfit.
+			naive, err := testutils.KSStatistic(samples, dist.CDF)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(naive).To(BeNumerically(">", D))
+		})
+	})
+
+	Describe("KSPValue", func() {
+		It("Returns 1 for a perfect fit and approaches 0 for large distances", func() {
+			p, err := testutils.KSPValue(0.0, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(BeEquivalentTo(1.0))
+
+			p, err = testutils.KSPValue(0.5, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(BeNumerically("<", 1e-10))
+		})
+
+		It("Matches the tabulated 95% critical value", func() {
+			// Asymptotic critical value for alpha = 0.05 is 1.358 / sqrt(N).
+			p, err := testutils.KSPValue(1.358/10.0, 100)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(p).To(BeNumerically("~", 0.05, 0.01))
+		})
+
+		It("Rejects invalid input", func() {
+			_, err := testutils.KSPValue(0.1, 0)
+			Expect(err).To(MatchError("N must be greater than 0."))
+
+			_, err = testutils.KSPValue(1.1, 10)
+			Expect(err).To(MatchError("KS statistic must be between 0 and 1."))
+		})
+	})
+})
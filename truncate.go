@@ -0,0 +1,180 @@
+package burrow
+
+import (
+	"fmt"
+	"reflect"
+	"time"
+
+	"gonum.org/v1/gonum/stat/distuv"
+)
+
+// maxRejections is the number of draws in a row Truncate makes before giving up on landing in the window and pinning the last draw to it.
+const maxRejections = 10000
+
+// InvertibleDistribution describes a distribution by its cumulative distribution function and its inverse, which lets TruncateInverse sample any window of it in a single draw.
+type InvertibleDistribution[T Rangeable] struct {
+	CDF      func(T) float64
+	Quantile func(float64) T
+}
+
+// Truncate confines dist to [lo, hi] by rejection: draws outside the window are discarded and redrawn. This suits windows that hold most of the distribution's mass; for windows far out in a tail, use TruncateInverse.
+//
+// If maxRejections draws in a row miss the window, the last one is pinned to its nearer edge rather than looping forever.
+func Truncate[T Rangeable](dist SampleDistribution[T], lo, hi T) (SampleDistribution[T], error) {
+	if dist == nil {
+		return nil, fmt.Errorf("Must receive a non-null sample distribution.")
+	}
+
+	if compareRangeable(lo, hi) >= 0 {
+		return nil, fmt.Errorf("Truncation window must have a positive length.")
+	}
+
+	distroFunc := func() T {
+		var sample T
+		for i := 0; i < maxRejections; i++ {
+			if sample = dist(); compareRangeable(sample, lo) >= 0 && compareRangeable(sample, hi) <= 0 {
+				return sample
+			}
+		}
+
+		return clampRangeable(sample, lo, hi)
+	}
+
+	return SampleDistribution[T](distroFunc), nil
+}
+
+// TruncateInverse confines dist to [lo, hi] by inverse transform sampling: a uniform draw between the CDF's values at lo and hi is mapped back through the quantile function. Every draw lands in the window, however little of the distribution's mass it holds.
+func TruncateInverse[T Rangeable](dist InvertibleDistribution[T], lo, hi T) (SampleDistribution[T], error) {
+	return truncateInverse(globalRand{}, dist, lo, hi)
+}
+
+func truncateInverse[T Rangeable](rng randSource, dist InvertibleDistribution[T], lo, hi T) (SampleDistribution[T], error) {
+	if dist.CDF == nil || dist.Quantile == nil {
+		return nil, fmt.Errorf("Inverse truncation requires both a CDF and a quantile function.")
+	}
+
+	if compareRangeable(lo, hi) >= 0 {
+		return nil, fmt.Errorf("Truncation window must have a positive length.")
+	}
+
+	pLo, pHi := dist.CDF(lo), dist.CDF(hi)
+	if pHi <= pLo {
+		return nil, fmt.Errorf("Distribution has no mass in the truncation window.")
+	}
+
+	distroFunc := func() T {
+		// The quantile function may round a draw just past either edge.
+		return clampRangeable(dist.Quantile(pLo+rng.Float64()*(pHi-pLo)), lo, hi)
+	}
+
+	return SampleDistribution[T](distroFunc), nil
+}
+
+// Clamp bounds dist to [lo, hi] by pinning draws outside the window to its nearer edge. Unlike truncation, this piles the mass outside the window onto its edges.
+func Clamp[T Rangeable](dist SampleDistribution[T], lo, hi T) (SampleDistribution[T], error) {
+	if dist == nil {
+		return nil, fmt.Errorf("Must receive a non-null sample distribution.")
+	}
+
+	if compareRangeable(lo, hi) > 0 {
+		return nil, fmt.Errorf("Lower bound must not exceed upper bound.")
+	}
+
+	distroFunc := func() T {
+		return clampRangeable(dist(), lo, hi)
+	}
+
+	return SampleDistribution[T](distroFunc), nil
+}
+
+// InvertibleGaussianTimestamps describes normally distributed timestamps centered on tMean, for use with TruncateInverse.
+func InvertibleGaussianTimestamps(tMean time.Time, tStdDev time.Duration) (InvertibleDistribution[time.Time], error) {
+	if tStdDev <= 0 {
+		return InvertibleDistribution[time.Time]{}, fmt.Errorf("Standard deviation must be positive.")
+	}
+
+	norm := distuv.Normal{Mu: 0, Sigma: float64(tStdDev)}
+
+	return InvertibleDistribution[time.Time]{
+		CDF: func(t time.Time) float64 {
+			return norm.CDF(float64(t.Sub(tMean)))
+		},
+		Quantile: func(p float64) time.Time {
+			return tMean.Add(time.Duration(norm.Quantile(p)))
+		},
+	}, nil
+}
+
+// InvertibleUniformTimestamps describes timestamps spread uniformly over [tStart, tStart+uniformRange), for use with TruncateInverse.
+func InvertibleUniformTimestamps(tStart time.Time, uniformRange time.Duration) (InvertibleDistribution[time.Time], error) {
+	if uniformRange <= 0 {
+		return InvertibleDistribution[time.Time]{}, fmt.Errorf("Requires a non-zero duration")
+	}
+
+	return InvertibleDistribution[time.Time]{
+		CDF: func(t time.Time) float64 {
+			p := float64(t.Sub(tStart)) / float64(uniformRange)
+			switch {
+			case p < 0:
+				return 0
+			case p > 1:
+				return 1
+			default:
+				return p
+			}
+		},
+		Quantile: func(p float64) time.Time {
+			return tStart.Add(time.Duration(p * float64(uniformRange)))
+		},
+	}, nil
+}
+
+// clampRangeable pins x to [lo, hi].
+func clampRangeable[T Rangeable](x, lo, hi T) T {
+	switch {
+	case compareRangeable(x, lo) < 0:
+		return lo
+	case compareRangeable(x, hi) > 0:
+		return hi
+	default:
+		return x
+	}
+}
+
+// compareRangeable returns -1, 0 or 1 as a is less than, equal to or greater than b. Go's ordering operators can't be used on Rangeable values, since time.Time has none, so ordered types are compared by their underlying kind.
+func compareRangeable[T Rangeable](a, b T) int {
+	if ta, ok := any(a).(time.Time); ok {
+		tb := any(b).(time.Time)
+		switch {
+		case ta.Before(tb):
+			return -1
+		case ta.After(tb):
+			return 1
+		default:
+			return 0
+		}
+	}
+
+	va, vb := reflect.ValueOf(a), reflect.ValueOf(b)
+	switch va.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return compareOrdered(va.Int(), vb.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return compareOrdered(va.Uint(), vb.Uint())
+	case reflect.Float32, reflect.Float64:
+		return compareOrdered(va.Float(), vb.Float())
+	default:
+		return compareOrdered(va.String(), vb.String())
+	}
+}
+
+func compareOrdered[T int64 | uint64 | float64 | string](a, b T) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	default:
+		return 0
+	}
+}
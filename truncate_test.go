@@ -0,0 +1,140 @@
+package burrow_test
+
+import (
+	"math/rand"
+	"time"
+
+	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
+	"gonum.org/v1/gonum/stat"
+
+	"github.com/bdshroyer/burrow"
+)
+
+var _ = Describe("Truncation", func() {
+	var (
+		tMu    time.Time
+		tSigma time.Duration
+		normal burrow.SampleDistribution[time.Time]
+	)
+
+	BeforeEach(func() {
+		rand.Seed(3)
+
+		tMu = today().Add(12 * time.Hour)
+		tSigma = 4 * time.Hour
+
+		var err error
+		normal, err = burrow.GaussianTimestampDistribution(tMu, tSigma)
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	Describe("Truncate", func() {
+		It("Redraws timestamps until they land in the window", func() {
+			lo, hi := tMu.Add(-3*time.Hour), tMu.Add(5*time.Hour)
+			distro, err := burrow.Truncate(normal, lo, hi)
+			Expect(err).NotTo(HaveOccurred())
+
+			for i := 0; i < 1000; i++ {
+				Expect(distro()).To(BeTemporally(">=", lo))
+				Expect(distro()).To(BeTemporally("<=", hi))
+			}
+		})
+
+		It("Works on any ordered type, including named ones", func() {
+			var delays burrow.SampleDistribution[time.Duration] = func() time.Duration {
+				return time.Duration(rand.Int63n(int64(10 * time.Hour)))
+			}
+
+			distro, err := burrow.Truncate(delays, time.Hour, 2*time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(distro()).To(And(BeNumerically(">=", time.Hour), BeNumerically("<=", 2*time.Hour)))
+
+			var names burrow.SampleDistribution[string] = func() string { return []string{"a", "m", "z"}[rand.Intn(3)] }
+			only, err := burrow.Truncate(names, "b", "y")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(only()).To(Equal("m"))
+		})
+
+		It("Pins draws to the window when they keep missing it", func() {
+			var constant burrow.SampleDistribution[float64] = func() float64 { return 10 }
+
+			distro, err := burrow.Truncate(constant, 0.0, 1.0)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(distro()).To(Equal(1.0))
+		})
+
+		It("Rejects empty windows", func() {
+			_, err := burrow.Truncate(normal, tMu, tMu)
+			Expect(err).To(MatchError("Truncation window must have a positive length."))
+
+			_, err = burrow.Truncate[time.Time](nil, tMu, tMu.Add(time.Hour))
+			Expect(err).To(MatchError("Must receive a non-null sample distribution."))
+		})
+	})
+
+	Describe("TruncateInverse", func() {
+		It("Samples windows deep in a tail in one draw each", func() {
+			inv, err := burrow.InvertibleGaussianTimestamps(tMu, tSigma)
+			Expect(err).NotTo(HaveOccurred())
+
+			lo, hi := tMu.Add(6*tSigma), tMu.Add(7*tSigma)
+			distro, err := burrow.TruncateInverse(inv, lo, hi)
+			Expect(err).NotTo(HaveOccurred())
+
+			offsets := make([]float64, 1000)
+			for i := range offsets {
+				ts := distro()
+				Expect(ts).To(BeTemporally(">=", lo))
+				Expect(ts).To(BeTemporally("<=", hi))
+				offsets[i] = float64(ts.Sub(tMu)) / float64(tSigma)
+			}
+
+			// Mass falls off so fast beyond six deviations that samples crowd the window's lower edge.
+			Expect(stat.Mean(offsets, nil)).To(BeNumerically("<", 6.3))
+		})
+
+		It("Matches uniform sampling over a sub-window of a uniform distribution", func() {
+			inv, err := burrow.InvertibleUniformTimestamps(tMu, 10*time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+
+			distro, err := burrow.TruncateInverse(inv, tMu.Add(2*time.Hour), tMu.Add(4*time.Hour))
+			Expect(err).NotTo(HaveOccurred())
+
+			offsets := make([]float64, 5000)
+			for i := range offsets {
+				offsets[i] = distro().Sub(tMu).Hours()
+			}
+			Expect(stat.Mean(offsets, nil)).To(BeNumerically("~", 3, 0.05))
+		})
+
+		It("Rejects windows without any mass", func() {
+			inv, err := burrow.InvertibleUniformTimestamps(tMu, time.Hour)
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = burrow.TruncateInverse(inv, tMu.Add(2*time.Hour), tMu.Add(3*time.Hour))
+			Expect(err).To(MatchError("Distribution has no mass in the truncation window."))
+		})
+	})
+
+	Describe("Clamp", func() {
+		It("Pins draws outside the window to its edges", func() {
+			lo, hi := tMu.Add(-time.Hour), tMu.Add(time.Hour)
+			distro, err := burrow.Clamp(normal, lo, hi)
+			Expect(err).NotTo(HaveOccurred())
+
+			atEdge := 0
+			for i := 0; i < 1000; i++ {
+				ts := distro()
+				Expect(ts).To(BeTemporally(">=", lo))
+				Expect(ts).To(BeTemporally("<=", hi))
+				if ts.Equal(lo) || ts.Equal(hi) {
+					atEdge++
+				}
+			}
+
+			// About 80% of N(0, 4h) lies outside [-1h, 1h].
+			Expect(atEdge).To(BeNumerically("~", 800, 50))
+		})
+	})
+})